@@ -0,0 +1,53 @@
+package run
+
+import "time"
+
+// AbandonAfter sets the maximum amount of time an execution of a runnable
+// is waited for once its context is cancelled (due to its timeout
+// or a missed heartbeat), before it is abandoned (default: 0, no limit),
+// so that a stuck execution does not prevent forward progress.
+//
+// Unlike StopGrace, the instance keeps running: RunAbandoned is emitted
+// and the execution fails with ErrRunAbandoned, with the next one
+// scheduled according to the options of the instance (e.g. Restart).
+// Executions are run on a separate goroutine if this option is set.
+//
+// Abandoned executions are left running on their goroutine
+// until the runnable returns, and are tracked in Stats.
+// They are not accounted against Concurrency, so that executions
+// of the same copy of the runnable may overlap: the runnable
+// and its middleware must be safe to execute concurrently,
+// and executions that must not overlap should be guarded by a gate
+// (see WithGate), which is held until abandoned executions return.
+//
+// If the instance is stopped (or its context is cancelled),
+// StopGrace applies instead, if set.
+func AbandonAfter(d time.Duration) Option {
+	return func(o *options) *options {
+		o.abandonAfter = d
+		return o
+	}
+}
+
+// abandonment returns the maximum amount of time a cancelled execution
+// is waited for before it is abandoned.
+func (o *options) abandonment() time.Duration {
+	if o == nil {
+		return 0
+	}
+	return o.abandonAfter
+}
+
+// abandon accounts an execution that was abandoned,
+// until it eventually returns, which is signalled through the provided channel.
+func (i *Instance) abandon(returned <-chan struct{}) {
+	i.mu.Lock()
+	i.stats.Abandoned++
+	i.mu.Unlock()
+
+	i.leaked.Add(1)
+	go func() {
+		<-returned
+		i.leaked.Add(^uint64(0))
+	}()
+}
@@ -0,0 +1,172 @@
+package run
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testAbandon(t *testing.T) {
+	// stuck returns a runnable whose first execution ignores
+	// the cancellation of its context until released,
+	// signalling once it starts.
+	stuck := func(started chan<- struct{}, release <-chan struct{}) Runnable {
+		var runs atomic.Int32
+		return func(context.Context) error {
+			if runs.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			return nil
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"AbandonAfter": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{AbandonAfter(time.Second)})
+			as.Equal(&options{abandonAfter: time.Second}, opts)
+			as.Equal(time.Second, opts.abandonment())
+
+			opts = nil
+			as.Zero(opts.abandonment())
+
+			_, err := NewChecked(nil, AbandonAfter(-time.Second))
+			as.Equal(OptionError{Option: "AbandonAfter", Reason: "must not be negative"}, err)
+		},
+		"abandoned and restarted": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			inst := New(stuck(make(chan struct{}), release),
+				Timeout(testTimeDelta), AbandonAfter(testTimeDelta), Restart(true))
+
+			as.Equal([]Event{
+				RunStarted{},
+				RunAbandoned{},
+				BackoffStarted{},
+				RunStarted{},
+				RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			stats := inst.Stats()
+			as.Equal(uint64(2), stats.Runs)
+			as.Equal(uint64(1), stats.FailedRuns)
+			as.Equal(ErrRunAbandoned, stats.LastError)
+			as.Equal(uint64(1), stats.Abandoned)
+			as.Equal(uint64(1), stats.Leaked)
+
+			// Leaked executions are tracked until they return, even across resets.
+			as.NoError(inst.Reset())
+			close(release)
+			as.Eventually(func() bool {
+				return inst.Stats().Leaked == 0
+			}, time.Second, testTimeDelta/10)
+			as.Zero(inst.Stats().Abandoned)
+		},
+		"gate held until return": func(t *testing.T) {
+			as := newAssertions(t)
+
+			// The gate is a mutex, which the second execution
+			// can only acquire once the abandoned one returns.
+			var executing atomic.Int32
+			gate := &channelGate{ch: make(chan struct{}, 1)}
+			started, release := make(chan struct{}), make(chan struct{})
+			r := stuck(started, release)
+			inst := New(func(ctx context.Context) error {
+				as.Equal(int32(1), executing.Add(1))
+				defer executing.Add(-1)
+				return r(ctx)
+			}, Timeout(testTimeDelta), AbandonAfter(testTimeDelta),
+				Restart(true), WithGate(gate))
+
+			evCh := inst.Events(context.TODO())
+			as.Equal(RunStarted{}, <-evCh)
+			as.IsType(RunAbandoned{}, <-evCh)
+			as.IsType(BackoffStarted{}, <-evCh)
+			select {
+			case ev := <-evCh:
+				as.Failf("execution overlapped with abandoned one", "%#v", ev)
+			case <-time.After(testTimeDelta):
+			}
+
+			close(release)
+			as.Equal([]Event{
+				RunStarted{},
+				RunSucceeded{},
+				Terminated{},
+			}, waitEvents(evCh))
+		},
+		"abandoned without restart": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			defer close(release)
+			inst := New(stuck(make(chan struct{}), release),
+				Timeout(testTimeDelta), AbandonAfter(testTimeDelta))
+
+			as.Equal([]error{ErrRunAbandoned}, waitErrors(inst.Run(context.TODO())))
+			as.Equal(StateTerminated, inst.State())
+		},
+		"returned in time": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}, Timeout(testTimeDelta), AbandonAfter(testTimeDelta))
+
+			errs := waitErrors(inst.Run(context.TODO()))
+			if as.Len(errs, 1) {
+				as.ErrorIs(errs[0], ErrRunTimeout)
+			}
+			as.Zero(inst.Stats().Abandoned)
+		},
+		"stopped": func(t *testing.T) {
+			as := newAssertions(t)
+
+			started, release := make(chan struct{}), make(chan struct{})
+			defer close(release)
+			inst := New(stuck(started, release),
+				AbandonAfter(testTimeDelta), Restart(true))
+
+			evCh := inst.Events(context.TODO())
+			go func() {
+				<-started
+				inst.Stop()
+			}()
+			as.Equal([]Event{
+				RunStarted{},
+				RunAbandoned{},
+				Terminated{},
+			}, waitEvents(evCh))
+			as.Equal(StateStopped, inst.State())
+			as.Equal(uint64(1), inst.Stats().Leaked)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
+
+// channelGate is a gate held while its channel is full.
+type channelGate struct {
+	ch chan struct{}
+}
+
+func (g *channelGate) Acquire(ctx context.Context) error {
+	select {
+	case g.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *channelGate) Release(context.Context) error {
+	<-g.ch
+	return nil
+}
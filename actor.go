@@ -0,0 +1,84 @@
+package run
+
+import "context"
+
+// FromActor returns a runnable executing an actor,
+// as defined by github.com/oklog/run: execute runs until interrupt is called,
+// after which it should return promptly.
+//
+// Interrupt is called with the cause of the cancellation of the context
+// of an execution (see context.Cause), e.g. ErrStopped
+// when its instance is stopped, unless execute has already returned.
+func FromActor(execute func() error, interrupt func(error)) Runnable {
+	return func(ctx context.Context) error {
+		done, exited := make(chan struct{}), make(chan struct{})
+		defer func() {
+			close(done)
+			<-exited
+		}()
+
+		go func() {
+			defer close(exited)
+			select {
+			case <-ctx.Done():
+				interrupt(context.Cause(ctx))
+			case <-done:
+			}
+		}()
+
+		return execute()
+	}
+}
+
+// Actor returns an actor running an instance, as defined by
+// github.com/oklog/run, to be added to a run.Group of that package.
+//
+// Execute runs the instance under the provided context and returns
+// its final error once it terminates (as Do does), while interrupt stops it.
+// If the instance has already been run, execute returns nil immediately.
+func (i *Instance) Actor(ctx context.Context) (execute func() error, interrupt func(error)) {
+	execute = func() error {
+		evCh := i.Events(ctx)
+		if evCh == nil {
+			return nil
+		}
+
+		var out outcome
+		for ev := range evCh {
+			out.observe(ev)
+		}
+		return out.err
+	}
+	interrupt = func(error) {
+		i.Stop()
+	}
+	return execute, interrupt
+}
+
+// Actor returns an actor running a group, as defined by
+// github.com/oklog/run, to be added to a run.Group of that package.
+//
+// Execute runs the group under the provided context and returns
+// the first error propagated by it once it terminates
+// (see Group.Run), while interrupt stops it.
+// If the group has already been run, execute returns nil immediately.
+func (g *Group) Actor(ctx context.Context) (execute func() error, interrupt func(error)) {
+	execute = func() error {
+		errCh := g.Run(ctx)
+		if errCh == nil {
+			return nil
+		}
+
+		var first error
+		for err := range errCh {
+			if first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+	interrupt = func(error) {
+		g.Stop()
+	}
+	return execute, interrupt
+}
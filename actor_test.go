@@ -0,0 +1,109 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testActor(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"FromActor interrupted on stop": func(t *testing.T) {
+			as := newAssertions(t)
+
+			quit := make(chan error, 1)
+			inst := New(FromActor(func() error {
+				return <-quit
+			}, func(err error) {
+				quit <- err
+			}))
+
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			inst.Stop()
+			as.Equal([]error{ErrStopped}, waitErrors(errCh))
+			as.Equal(StateStopped, inst.State())
+		},
+		"FromActor returning on its own": func(t *testing.T) {
+			as := newAssertions(t)
+
+			interrupted := false
+			err := Do(context.TODO(), FromActor(func() error {
+				return testError(1)
+			}, func(error) {
+				interrupted = true
+			}))
+			as.Equal(testError(1), err)
+			as.False(interrupted)
+		},
+		"FromActor interrupted on cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			quit := make(chan error, 1)
+			inst := New(FromActor(func() error {
+				return <-quit
+			}, func(err error) {
+				quit <- err
+			}))
+
+			errCh := inst.Run(ctx)
+			as.NoError(inst.WaitReady(ctx))
+			cancel()
+			as.Equal([]error{context.Canceled}, waitErrors(errCh))
+		},
+		"Instance.Actor": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return testError(2)
+			})
+
+			execute, interrupt := inst.Actor(context.TODO())
+			done := make(chan error)
+			go func() {
+				done <- execute()
+			}()
+			as.NoError(inst.WaitReady(context.TODO()))
+			interrupt(testError(3))
+			as.Equal(testError(2), <-done)
+			as.Equal(StateStopped, inst.State())
+
+			// Already run.
+			execute, _ = inst.Actor(context.TODO())
+			as.NoError(execute())
+		},
+		"Group.Actor": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				<-ctx.Done()
+				return testError(4)
+			}))
+			as.NoError(g.Add("b", func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}))
+
+			execute, interrupt := g.Actor(context.TODO())
+			done := make(chan error)
+			go func() {
+				done <- execute()
+			}()
+			as.NoError(g.WaitReady(context.TODO()))
+			interrupt(testError(5))
+			as.Equal(MemberError{Member: "a", Err: testError(4)}, <-done)
+
+			// Already run.
+			execute, _ = g.Actor(context.TODO())
+			as.NoError(execute())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
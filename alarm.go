@@ -0,0 +1,134 @@
+package run
+
+import "time"
+
+// alarmOptions defines the failure-rate alarm of a runnable.
+type alarmOptions struct {
+	// threshold is the failure rate above which the instance is degraded,
+	// over the executions that finished within window,
+	// provided there are at least minRuns of them.
+	threshold float64
+	window    time.Duration
+	minRuns   uint
+}
+
+// FailureRateAlarm sets a failure-rate alarm on an instance
+// (default: none), e.g. FailureRateAlarm(0.5, 10*time.Minute, 5)
+// for more than half of at least 5 executions failing within 10 minutes.
+//
+// Once the rate of failed executions among those that finished within
+// the provided window exceeds the threshold (with at least minRuns
+// executions within it), FailureRateExceeded is emitted,
+// and once it no longer does, FailureRateRestored is emitted.
+// The threshold should be within [0, 1), and the window positive.
+//
+// The rate is assessed after each execution.
+func FailureRateAlarm(threshold float64, window time.Duration,
+	minRuns uint) Option {
+
+	return func(o *options) *options {
+		o.alarm = alarmOptions{
+			threshold: threshold,
+			window:    window,
+			minRuns:   minRuns,
+		}
+		return o
+	}
+}
+
+// FailureRateExceeded is emitted when the failure rate of an instance
+// exceeds the threshold of its alarm (see FailureRateAlarm).
+type FailureRateExceeded struct {
+	// Rate is the failure rate over the Runs executions
+	// that finished within the window of the alarm.
+	Rate float64
+	Runs int
+}
+
+// FailureRateRestored is emitted when the failure rate of an instance
+// no longer exceeds the threshold of its alarm, after it did
+// (see FailureRateAlarm).
+type FailureRateRestored struct {
+	// Rate is the failure rate over the Runs executions
+	// that finished within the window of the alarm.
+	Rate float64
+	Runs int
+}
+
+func (FailureRateExceeded) event() {}
+func (FailureRateRestored) event() {}
+
+// alarms indicates whether the failure rate of a runnable is monitored.
+func (o *options) alarms() bool {
+	return o != nil && o.alarm.window > 0
+}
+
+// failureRate holds the outcomes of the recent executions of an instance,
+// along with whether its alarm has been raised.
+type failureRate struct {
+	outcomes []outcomeAt
+	failures int
+	raised   bool
+}
+
+// outcomeAt represents the outcome of an execution finished at a time.
+type outcomeAt struct {
+	at     time.Time
+	failed bool
+}
+
+// assess records the outcome of an execution finished at the provided time,
+// and returns the event emitted if the alarm is raised or cleared by it,
+// or nil if it is not.
+func (r *failureRate) assess(opts alarmOptions, at time.Time,
+	failed bool) Event {
+
+	r.outcomes = append(r.outcomes, outcomeAt{at: at, failed: failed})
+	if failed {
+		r.failures++
+	}
+	expired := 0
+	for _, out := range r.outcomes {
+		if at.Sub(out.at) < opts.window {
+			break
+		}
+		if out.failed {
+			r.failures--
+		}
+		expired++
+	}
+	r.outcomes = append(r.outcomes[:0], r.outcomes[expired:]...)
+
+	runs := len(r.outcomes)
+	rate := float64(r.failures) / float64(runs)
+	exceeded := rate > opts.threshold && uint(runs) >= opts.minRuns
+	switch {
+	case exceeded && !r.raised:
+		r.raised = true
+		return FailureRateExceeded{Rate: rate, Runs: runs}
+	case !exceeded && r.raised && rate <= opts.threshold:
+		r.raised = false
+		return FailureRateRestored{Rate: rate, Runs: runs}
+	}
+	return nil
+}
+
+// monitor records the outcome of an execution of an instance
+// finished at the provided time, emitting the event of its alarm (if any).
+func (i *Instance) monitor(err error, at time.Time, emit func(Event)) {
+	opts := i.options()
+	if !opts.alarms() {
+		return
+	}
+
+	i.mu.Lock()
+	if i.rate == nil {
+		i.rate = new(failureRate)
+	}
+	ev := i.rate.assess(opts.alarm, at, err != nil)
+	i.mu.Unlock()
+
+	if ev != nil {
+		emit(ev)
+	}
+}
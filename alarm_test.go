@@ -0,0 +1,110 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testAlarm(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"FailureRateAlarm": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{
+				FailureRateAlarm(0.5, time.Minute, 3),
+			})
+			as.Equal(alarmOptions{threshold: 0.5, window: time.Minute, minRuns: 3}, opts.alarm)
+			as.True(opts.alarms())
+
+			var zero *options
+			as.False(zero.alarms())
+			as.False(new(options).alarms())
+		},
+		"invalid alarm": func(t *testing.T) {
+			as := newAssertions(t)
+
+			expected := OptionError{
+				Option: "FailureRateAlarm",
+				Reason: "requires a threshold in [0, 1) and a positive window",
+			}
+			for _, opt := range []Option{
+				FailureRateAlarm(-0.1, time.Minute, 0),
+				FailureRateAlarm(1, time.Minute, 0),
+				FailureRateAlarm(0.5, 0, 0),
+			} {
+				_, err := NewChecked(nil, opt)
+				as.Equal(expected, err)
+			}
+			_, err := NewChecked(nil, FailureRateAlarm(0, time.Minute, 0))
+			as.NoError(err)
+		},
+		"assessment": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := alarmOptions{threshold: 0.5, window: time.Minute, minRuns: 2}
+			start := time.Now()
+			var rate failureRate
+			for _, step := range []struct {
+				after    time.Duration
+				failed   bool
+				expected Event
+			}{
+				{after: 0, failed: true},
+				{after: time.Second, failed: true,
+					expected: FailureRateExceeded{Rate: 1, Runs: 2}},
+				{after: time.Minute + time.Second/2, failed: true},
+				// A single execution remains within the window.
+				{after: 5 * time.Minute, failed: true},
+				{after: 5*time.Minute + time.Second, failed: false,
+					expected: FailureRateRestored{Rate: 0.5, Runs: 2}},
+				{after: 7 * time.Minute, failed: true},
+				{after: 7*time.Minute + time.Second, failed: true,
+					expected: FailureRateExceeded{Rate: 1, Runs: 2}},
+			} {
+				as.Equal(step.expected,
+					rate.assess(opts, start.Add(step.after), step.failed),
+					"after %v", step.after)
+			}
+		},
+		"events": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				if runs++; runs <= 3 {
+					return testError(runs)
+				}
+				return nil
+			}, Recur(true), RunLimit(3), Restart(true),
+				FailureRateAlarm(0.5, time.Hour, 2))
+
+			var alarms []Event
+			for ev := range inst.Events(context.TODO()) {
+				switch ev.(type) {
+				case FailureRateExceeded, FailureRateRestored:
+					alarms = append(alarms, ev)
+				}
+			}
+			as.Equal([]Event{
+				FailureRateExceeded{Rate: 1, Runs: 2},
+				FailureRateRestored{Rate: 0.5, Runs: 6},
+			}, alarms)
+		},
+		"reset": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, FailureRateAlarm(0.5, time.Hour, 0))
+			waitErrors(inst.Run(context.TODO()))
+			as.NotNil(inst.rate)
+			as.NoError(inst.Reset())
+			as.Nil(inst.rate)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
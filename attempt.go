@@ -0,0 +1,64 @@
+package run
+
+import (
+	"context"
+	"time"
+)
+
+// Attempt describes a single execution of a runnable,
+// and is available to it through its context.
+type Attempt struct {
+	// Number is the (1-based) number of the execution.
+	Number uint64
+	// ConsecutiveFailures is the number of failed executions
+	// preceding the current one that are accounted towards the restart limit.
+	ConsecutiveFailures uint64
+	// PreviousErr is the error returned by the previous execution (if any).
+	PreviousErr error
+	// ScheduledAt is the time the execution was scheduled to start.
+	ScheduledAt time.Time
+}
+
+// attemptKey is the context key for execution metadata.
+type attemptKey struct{}
+
+// AttemptFromContext returns the execution metadata
+// carried by the context of a runnable,
+// and whether any was found.
+func AttemptFromContext(ctx context.Context) (Attempt, bool) {
+	a, ok := ctx.Value(attemptKey{}).(Attempt)
+	return a, ok
+}
+
+// withAttempt returns a child of the provided context
+// carrying the provided execution metadata.
+func withAttempt(ctx context.Context, a Attempt) context.Context {
+	return context.WithValue(ctx, attemptKey{}, a)
+}
+
+// ContextFactory derives the context of an execution of a runnable
+// from the provided parent context, which carries its Attempt.
+type ContextFactory func(parent context.Context, attempt Attempt) context.Context
+
+// WithContextFactory sets a function deriving the context of each execution
+// of a runnable (default: nil, the context of the instance is used),
+// e.g. to attach values, deadlines or tracing data per attempt.
+//
+// The derived context should be a child of the provided parent,
+// so that executions are cancelled along with their instance.
+// Any timeout of the runnable is applied on top of it.
+func WithContextFactory(f ContextFactory) Option {
+	return func(o *options) *options {
+		o.contextFn = f
+		return o
+	}
+}
+
+// runContext returns the context of an execution of a runnable,
+// derived from the provided parent context.
+func (o *options) runContext(parent context.Context, attempt Attempt) context.Context {
+	if o == nil || o.contextFn == nil {
+		return parent
+	}
+	return o.contextFn(parent, attempt)
+}
@@ -0,0 +1,134 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testAttempt(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"no attempt in plain context": func(t *testing.T) {
+			as := newAssertions(t)
+
+			_, ok := AttemptFromContext(context.TODO())
+
+			as.False(ok)
+		},
+		"runnable context carries attempt": func(t *testing.T) {
+			as := newAssertions(t)
+
+			backoff := 10 * time.Millisecond
+			var attempts []Attempt
+			var returned []time.Time
+			inst := New(func(ctx context.Context) error {
+				a, ok := AttemptFromContext(ctx)
+				as.True(ok)
+				attempts = append(attempts, a)
+				defer func() {
+					returned = append(returned, time.Now())
+				}()
+
+				if len(attempts) < 3 {
+					return testError(len(attempts))
+				}
+				return nil
+			},
+				Restart(true),
+				RestartLimit(0, ConstantBackoff(backoff)),
+				Recur(true),
+				RunLimit(2),
+			)
+
+			started := time.Now()
+			waitErrors(inst.Run(context.TODO()))
+
+			if !as.Len(attempts, 4) {
+				return
+			}
+			as.Equal(uint64(1), attempts[0].Number)
+			as.Zero(attempts[0].ConsecutiveFailures)
+			as.Nil(attempts[0].PreviousErr)
+			as.WithinDuration(started, attempts[0].ScheduledAt, testTimeDelta)
+
+			as.Equal(uint64(2), attempts[1].Number)
+			as.Equal(uint64(1), attempts[1].ConsecutiveFailures)
+			as.Equal(testError(1), attempts[1].PreviousErr)
+			as.WithinDuration(returned[0].Add(backoff), attempts[1].ScheduledAt, testTimeDelta)
+
+			as.Equal(uint64(3), attempts[2].Number)
+			as.Equal(uint64(2), attempts[2].ConsecutiveFailures)
+			as.Equal(testError(2), attempts[2].PreviousErr)
+
+			as.Equal(uint64(4), attempts[3].Number)
+			as.Zero(attempts[3].ConsecutiveFailures)
+			as.Nil(attempts[3].PreviousErr)
+		},
+		"restart without backoff": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var attempts []Attempt
+			inst := New(func(ctx context.Context) error {
+				a, _ := AttemptFromContext(ctx)
+				attempts = append(attempts, a)
+				if len(attempts) < 3 {
+					return testError(len(attempts))
+				}
+				return nil
+			}, Restart(true))
+
+			errs := waitErrors(inst.Run(context.TODO()))
+
+			as.Equal([]error{testError(1), testError(2)}, errs)
+			if as.Len(attempts, 3) {
+				as.Equal(uint64(2), attempts[2].ConsecutiveFailures)
+			}
+		},
+		"WithContextFactory": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{WithContextFactory(nil)})
+			as.Equal(&options{}, opts)
+			as.Equal(context.TODO(), opts.runContext(context.TODO(), Attempt{}))
+			opts = nil
+			as.Equal(context.TODO(), opts.runContext(context.TODO(), Attempt{}))
+		},
+		"context factory derives run contexts": func(t *testing.T) {
+			as := newAssertions(t)
+
+			type key struct{}
+			var values []interface{}
+			err := Do(context.TODO(), func(ctx context.Context) error {
+				values = append(values, ctx.Value(key{}))
+				if len(values) < 2 {
+					return testError(1)
+				}
+				// The deadline set by the factory is not a run timeout.
+				<-ctx.Done()
+				return ctx.Err()
+			}, Restart(true), RestartLimit(2, nil), Timeout(time.Hour),
+				WithContextFactory(func(parent context.Context,
+					attempt Attempt) context.Context {
+
+					a, ok := AttemptFromContext(parent)
+					as.True(ok)
+					as.Equal(attempt, a)
+
+					ctx := context.WithValue(parent, key{}, attempt.Number)
+					if attempt.Number == 2 {
+						var cancel context.CancelFunc
+						ctx, cancel = context.WithTimeout(ctx, 0)
+						t.Cleanup(cancel)
+					}
+					return ctx
+				}))
+
+			as.Equal(context.DeadlineExceeded, err)
+			as.Equal([]interface{}{uint64(1), uint64(2)}, values)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,54 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testBlocking(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"errors delivered to handler": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			var errs []error
+			inst := New(func(context.Context) error {
+				runs++
+				return testError(runs)
+			}, Restart(true), RestartLimit(2, nil),
+				OnError(func(err error) {
+					errs = append(errs, err)
+				}),
+				ReportTermination(true))
+
+			as.Equal(testError(2), inst.RunBlocking(context.TODO()))
+			as.Equal([]error{testError(1), testError(2), RestartLimitExceeded}, errs)
+			as.Equal(2, runs)
+
+			as.Equal(ErrAlreadyRunning, inst.RunBlocking(context.TODO()))
+			as.Nil(inst.Run(context.TODO()))
+		},
+		"errors discarded without handler": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			})
+			as.Equal(testError(1), inst.RunBlocking(context.TODO()))
+			as.Equal(Failed, inst.TerminationReason())
+		},
+		"successful": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, Recur(true), RunLimit(3))
+			as.NoError(inst.RunBlocking(context.TODO()))
+			as.Equal(uint64(3), inst.SuccessfulRuns())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
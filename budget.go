@@ -0,0 +1,74 @@
+package run
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget limits the number of restarts after failed executions
+// of the instances sharing it within a time window (see WithRetryBudget),
+// so that they cannot collectively overwhelm a failing dependency.
+//
+// It is a token bucket of the provided capacity, refilled continuously
+// at the rate of its capacity per window, and starting full.
+type Budget struct {
+	capacity float64
+	window   time.Duration
+
+	// mu guards the available tokens,
+	// along with the time they were last refilled
+	// (zero until first used, so that a budget is not tied
+	// to the clock it was created with).
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewBudget creates a new budget allowing the provided number of retries
+// per time window.
+func NewBudget(retries uint, window time.Duration) *Budget {
+	return &Budget{
+		capacity: float64(retries),
+		window:   window,
+		tokens:   float64(retries),
+	}
+}
+
+// Allow consumes a retry from a budget at the provided time,
+// and indicates whether one was available.
+func (b *Budget) Allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 && b.window > 0 {
+		b.tokens += b.capacity * float64(elapsed) / float64(b.window)
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// WithRetryBudget sets a budget shared by instances for their restarts
+// after failed executions (default: nil, no budget).
+//
+// If the budget is depleted when an instance would restart,
+// it emits RetryDenied and terminates, as if its restart limit was reached.
+func WithRetryBudget(b *Budget) Option {
+	return func(o *options) *options {
+		o.budget = b
+		return o
+	}
+}
+
+// allowRetry indicates whether the retry budget of a runnable
+// (if any) allows a restart.
+func (o *options) allowRetry() bool {
+	return o == nil || o.budget == nil || o.budget.Allow(o.clock().Now())
+}
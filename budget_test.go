@@ -0,0 +1,83 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testBudget(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"WithRetryBudget": func(t *testing.T) {
+			as := newAssertions(t)
+
+			b := NewBudget(1, time.Second)
+			opts := apply(t, new(options), []Option{WithRetryBudget(b)})
+			as.Equal(&options{budget: b}, opts)
+			as.True(opts.allowRetry())
+			as.False(opts.allowRetry())
+
+			opts = nil
+			as.True(opts.allowRetry())
+		},
+		"token bucket": func(t *testing.T) {
+			as := newAssertions(t)
+
+			b := NewBudget(2, time.Minute)
+			now := time.Now()
+			as.True(b.Allow(now))
+			as.True(b.Allow(now))
+			as.False(b.Allow(now))
+
+			// Refilled at the rate of 2 per minute.
+			as.False(b.Allow(now.Add(20 * time.Second)))
+			as.True(b.Allow(now.Add(30 * time.Second)))
+			as.False(b.Allow(now.Add(30 * time.Second)))
+
+			// Capped at capacity.
+			now = now.Add(time.Hour)
+			as.True(b.Allow(now))
+			as.True(b.Allow(now))
+			as.False(b.Allow(now))
+
+			// Time going backwards does not refill.
+			as.False(b.Allow(now.Add(-time.Minute)))
+
+			empty := NewBudget(0, 0)
+			as.False(empty.Allow(time.Now()))
+		},
+		"shared budget denies restarts": func(t *testing.T) {
+			as := newAssertions(t)
+
+			b := NewBudget(1, time.Hour)
+			failing := func(context.Context) error {
+				return testError(1)
+			}
+			first := New(failing, Restart(true), RestartLimit(0, nil),
+				WithRetryBudget(b))
+			second := New(failing, Restart(true), RestartLimit(0, nil),
+				WithRetryBudget(b))
+
+			as.Equal([]Event{
+				RunStarted{},
+				RunFailed{Err: testError(1)},
+				BackoffStarted{},
+				RunStarted{},
+				RunFailed{Err: testError(1)},
+				RetryDenied{},
+				Terminated{},
+			}, waitEvents(first.Events(context.TODO())))
+			as.Equal([]Event{
+				RunStarted{},
+				RunFailed{Err: testError(1)},
+				RetryDenied{},
+				Terminated{},
+			}, waitEvents(second.Events(context.TODO())))
+			as.Equal(StateTerminated, second.State())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
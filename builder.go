@@ -0,0 +1,94 @@
+package run
+
+import (
+	"context"
+	"time"
+)
+
+// Builder configures an instance through chained method calls,
+// as an alternative to passing options to New, e.g.
+//
+//	inst, errs, err := run.Build(r).
+//		Recur(30 * time.Second).
+//		RestartExponential(5, time.Second, time.Minute).
+//		Recover().
+//		Start(ctx)
+//
+// Each method adds the respective options, so that later calls
+// override earlier ones, and With adds any other options.
+// The zero value is not usable; use Build instead.
+type Builder struct {
+	r    Runnable
+	opts []Option
+}
+
+// Build returns a builder of an instance of the provided runnable.
+func Build(r Runnable) *Builder {
+	return &Builder{r: r}
+}
+
+// With adds the provided options to the instance.
+func (b *Builder) With(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Name names the instance (see WithName).
+func (b *Builder) Name(name string) *Builder {
+	return b.With(WithName(name))
+}
+
+// Recur makes the runnable recur with the provided period
+// after each successful execution (see Recur and Period).
+func (b *Builder) Recur(period time.Duration) *Builder {
+	return b.With(Recur(true), Period(period))
+}
+
+// RunLimit limits the successful executions of the runnable
+// (see RunLimit).
+func (b *Builder) RunLimit(limit uint64) *Builder {
+	return b.With(RunLimit(limit))
+}
+
+// Timeout sets the execution timeout of the runnable (see Timeout).
+func (b *Builder) Timeout(timeout time.Duration) *Builder {
+	return b.With(Timeout(timeout))
+}
+
+// Restart restarts the runnable after failed executions,
+// up to the provided limit (0 for no limit) and with the provided backoff
+// (see Restart and RestartLimit).
+func (b *Builder) Restart(limit uint64, backoffFn BackoffFn) *Builder {
+	return b.With(Restart(true), RestartLimit(limit, backoffFn))
+}
+
+// RestartExponential restarts the runnable after failed executions,
+// up to the provided limit (0 for no limit) and with exponential backoff
+// (see ExponentialBackoff).
+func (b *Builder) RestartExponential(limit uint64,
+	base, ceiling time.Duration) *Builder {
+
+	return b.Restart(limit, ExponentialBackoff(base, ceiling))
+}
+
+// Recover recovers from panics of the runnable (see Recover).
+func (b *Builder) Recover() *Builder {
+	return b.With(Recover(true))
+}
+
+// Instance creates the configured instance,
+// after validating the combination of its options (see NewChecked).
+func (b *Builder) Instance() (*Instance, error) {
+	return NewChecked(b.r, b.opts...)
+}
+
+// Start creates the configured instance (see Builder.Instance)
+// and runs it (see Instance.Run), returning it along with
+// the channel where its errors are propagated.
+func (b *Builder) Start(ctx context.Context) (*Instance, <-chan error, error) {
+	inst, err := b.Instance()
+	if err != nil {
+		return nil, nil, err
+	}
+	return inst, inst.Run(ctx), nil
+}
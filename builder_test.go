@@ -0,0 +1,90 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testBuilder(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"builds options": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst, err := Build(nil).
+				Name("sync").
+				Recur(time.Minute).
+				RunLimit(3).
+				Timeout(time.Second).
+				RestartExponential(5, time.Second, time.Minute).
+				Recover().
+				With(StopGrace(time.Second)).
+				Instance()
+			if !as.NoError(err) {
+				return
+			}
+
+			backoff := inst.opts.restartable.backoff
+			inst.opts.restartable.backoff = nil
+			as.Equal(&options{
+				name:        "sync",
+				recurring:   recurrenceOptions{recur: true, period: time.Minute},
+				constrained: constraintOptions{runLimit: 3, timeout: time.Second},
+				restartable: restartOptions{restartOnError: true, restartLimit: 5},
+				recoverable: panicOptions{calm: true},
+				stopGrace:   time.Second,
+			}, inst.opts)
+			as.Equal(2*time.Second, backoff(2))
+			as.Equal(time.Minute, backoff(10))
+		},
+		"later calls override earlier ones": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst, err := Build(nil).
+				Restart(1, nil).
+				Restart(0, ConstantBackoff(time.Second)).
+				Instance()
+			if as.NoError(err) {
+				as.Equal("runs once, restarts indefinitely with backoff 1s, "+
+					"resets failures on success", inst.Describe())
+			}
+		},
+		"starts": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst, errs, err := Build(func(context.Context) error {
+				if runs++; runs == 1 {
+					return testError(runs)
+				}
+				return nil
+			}).
+				Recur(0).
+				RunLimit(2).
+				Restart(0, nil).
+				Start(context.TODO())
+			if !as.NoError(err) {
+				return
+			}
+
+			as.Equal([]error{testError(1)}, waitErrors(errs))
+			as.Equal(3, runs)
+			as.Equal(RunLimitReached, inst.TerminationReason())
+		},
+		"validates on start": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst, errs, err := Build(nil).
+				RunLimit(2).
+				Start(context.TODO())
+
+			as.Nil(inst)
+			as.Nil(errs)
+			as.Equal(OptionError{Option: "RunLimit", Reason: "requires Recur or Triggers"}, err)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,103 @@
+package run
+
+import "time"
+
+// CatchUpPolicy determines how a recurring runnable restored from a store
+// (see WithStore) handles the executions that were due
+// while the process was down.
+type CatchUpPolicy int
+
+const (
+	// CatchUpRunOnce executes once immediately
+	// in place of all missed executions.
+	CatchUpRunOnce CatchUpPolicy = iota
+	// CatchUpSkip skips missed executions,
+	// waiting for the next one on schedule.
+	CatchUpSkip
+	// CatchUpRunAll executes each missed execution
+	// immediately one after the other, until caught up.
+	CatchUpRunAll
+)
+
+// CatchUp sets the catch-up policy of a recurring runnable
+// restored from a store (default: CatchUpRunOnce), matching
+// e.g. the semantics of anacron (CatchUpRunOnce) or cron (CatchUpSkip).
+//
+// The missed executions are those the schedule of the runnable
+// determines (or its period, after the restored next execution)
+// until the runnable is restored. Skipped executions are reported
+// through a RunsMissed event, while pending restarts after failed
+// executions always take place immediately.
+func CatchUp(policy CatchUpPolicy) Option {
+	return func(o *options) *options {
+		o.recurring.catchUp = policy
+		return o
+	}
+}
+
+// catchUp returns the delay before the first execution of an instance
+// restored from its store, provided with the current time and the time
+// its next execution was due at, along with the time of the missed
+// execution it catches up with (if any) and the number of skipped
+// executions, or false if there are no more executions.
+func (i *Instance) catchUp(now, due time.Time) (
+	after time.Duration, behind time.Time, missed uint64, ok bool) {
+
+	if !due.Before(now) {
+		return due.Sub(now), time.Time{}, 0, true
+	}
+	rOpts := i.options().recurring
+	if !rOpts.recur || i.failedRuns.Load() != 0 {
+		return 0, time.Time{}, 0, true
+	}
+
+	switch rOpts.catchUp {
+	case CatchUpSkip:
+		if rOpts.schedule == nil {
+			if rOpts.period <= 0 {
+				return 0, time.Time{}, 0, true
+			}
+			missed = uint64(now.Sub(due)/rOpts.period) + 1
+			next := due.Add(time.Duration(missed) * rOpts.period)
+			return next.Sub(now), time.Time{}, missed, true
+		}
+		for next := due; ; missed++ {
+			switch {
+			case next.IsZero():
+				return 0, time.Time{}, missed, false
+			case !next.Before(now):
+				return next.Sub(now), time.Time{}, missed, true
+			}
+			next = rOpts.schedule.Next(next)
+		}
+	case CatchUpRunAll:
+		return 0, due, 0, true
+	default:
+		return 0, time.Time{}, 0, true
+	}
+}
+
+// caughtUp returns the delay before the execution following a missed one
+// that was due at the provided time, provided with the current time,
+// along with the time it was due at if it was missed too,
+// or false if there are no more executions.
+func (r recurrenceOptions) caughtUp(now, behind time.Time) (
+	after time.Duration, next time.Time, ok bool) {
+
+	switch {
+	case r.schedule != nil:
+		next = r.schedule.Next(behind)
+		if next.IsZero() {
+			return 0, time.Time{}, false
+		}
+	case r.period > 0:
+		next = behind.Add(r.period)
+	default:
+		return 0, time.Time{}, true
+	}
+
+	if next.Before(now) {
+		return 0, next, true
+	}
+	return next.Sub(now), time.Time{}, true
+}
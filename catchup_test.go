@@ -0,0 +1,207 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// restoredStore returns a store holding a state whose next execution
+// was due at the provided time, after the provided number of failures.
+func restoredStore(as *assert.Assertions, due time.Time,
+	failures uint64) *MemoryStore {
+
+	store := &MemoryStore{}
+	as.NoError(store.Save(context.TODO(), RunState{
+		ConsecutiveFailures: failures,
+		NextRun:             due,
+	}))
+	return store
+}
+
+func testCatchUp(t *testing.T) {
+	const interval = 10 * time.Minute
+	// every executes at each multiple of the interval,
+	// until the provided time (if set).
+	every := func(until time.Time) Schedule {
+		return ScheduleFunc(func(after time.Time) time.Time {
+			next := after.Truncate(interval).Add(interval)
+			if !until.IsZero() && next.After(until) {
+				return time.Time{}
+			}
+			return next
+		})
+	}
+
+	subtests := map[string]func(*testing.T){
+		"run once": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, Recur(true), Period(time.Hour), RunLimit(1),
+				WithStore(restoredStore(as, time.Now().Add(-3*time.Hour), 0)))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(1, runs)
+		},
+		"skip scheduled": func(t *testing.T) {
+			as := newAssertions(t)
+
+			now := time.Now()
+			grid := now.Truncate(interval)
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, WithSchedule(every(time.Time{})), CatchUp(CatchUpSkip),
+				WithStore(restoredStore(as, grid.Add(-3*interval), 0)))
+
+			evCh := make(chan []Event)
+			go func() {
+				evCh <- waitEvents(inst.Events(ctx))
+			}()
+			as.Eventually(func() bool {
+				return !inst.Stats().NextRun.IsZero()
+			}, time.Second, time.Millisecond)
+			as.Equal(grid.Add(interval), inst.Stats().NextRun.Truncate(time.Second))
+			cancel()
+
+			as.Equal([]Event{
+				RunsMissed{Count: 4},
+				Terminated{Reason: context.Canceled},
+			}, <-evCh)
+			as.Zero(runs)
+		},
+		"skip periodic": func(t *testing.T) {
+			as := newAssertions(t)
+
+			now := time.Now()
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, Recur(true), Period(time.Hour), CatchUp(CatchUpSkip),
+				WithStore(restoredStore(as, now.Add(-90*time.Minute), 0)))
+
+			evCh := make(chan []Event)
+			go func() {
+				evCh <- waitEvents(inst.Events(ctx))
+			}()
+			as.Eventually(func() bool {
+				return !inst.Stats().NextRun.IsZero()
+			}, time.Second, time.Millisecond)
+			as.WithinDuration(now.Add(30*time.Minute), inst.Stats().NextRun, testTimeDelta)
+			cancel()
+
+			as.Equal([]Event{
+				RunsMissed{Count: 2},
+				Terminated{Reason: context.Canceled},
+			}, <-evCh)
+		},
+		"skip until end of schedule": func(t *testing.T) {
+			as := newAssertions(t)
+
+			due := time.Now().Truncate(interval).Add(-2 * interval)
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, WithSchedule(every(due)), CatchUp(CatchUpSkip),
+				WithStore(restoredStore(as, due, 0)))
+
+			as.Equal([]Event{
+				RunsMissed{Count: 1},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.Zero(runs)
+			as.Equal(Completed, inst.TerminationReason())
+		},
+		"skip without period": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, Recur(true), RunLimit(1), CatchUp(CatchUpSkip),
+				WithStore(restoredStore(as, time.Now().Add(-time.Hour), 0)))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(1, runs)
+		},
+		"pending restart": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, Recur(true), Period(time.Hour), RunLimit(1), CatchUp(CatchUpSkip),
+				WithStore(restoredStore(as, time.Now().Add(-3*time.Hour), 1)))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(1, runs)
+		},
+		"run all scheduled": func(t *testing.T) {
+			as := newAssertions(t)
+
+			due := time.Now().Truncate(interval).Add(-2 * interval)
+			var scheduled []time.Time
+			inst := New(func(ctx context.Context) error {
+				attempt, _ := AttemptFromContext(ctx)
+				scheduled = append(scheduled, attempt.ScheduledAt)
+				return nil
+			}, WithSchedule(every(due.Add(interval))), CatchUp(CatchUpRunAll),
+				WithStore(restoredStore(as, due, 0)))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Len(scheduled, 2)
+			as.Equal(Completed, inst.TerminationReason())
+		},
+		"run all periodic": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for period, limit := range map[time.Duration]uint64{
+				time.Hour: 2,
+				0:         3,
+			} {
+				runs := 0
+				inst := New(func(context.Context) error {
+					runs++
+					return nil
+				}, Recur(true), Period(period), RunLimit(limit), CatchUp(CatchUpRunAll),
+					WithStore(restoredStore(as, time.Now().Add(-90*time.Minute), 0)))
+
+				started := time.Now()
+				as.Empty(waitErrors(inst.Run(context.TODO())))
+				as.Equal(int(limit), runs)
+				as.WithinDuration(started, time.Now(), testTimeDelta)
+			}
+		},
+		"on schedule": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var opts *options
+			inst := &Instance{opts: opts}
+			now := time.Now()
+			after, behind, missed, ok := inst.catchUp(now, now.Add(time.Minute))
+			as.Equal(time.Minute, after)
+			as.Zero(behind)
+			as.Zero(missed)
+			as.True(ok)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,81 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testCause(t *testing.T) {
+	// causeOf returns a runnable reporting the cause
+	// of the cancellation of its context.
+	causeOf := func(causes chan<- error) Runnable {
+		return func(ctx context.Context) error {
+			Ready(ctx)
+			<-ctx.Done()
+			causes <- context.Cause(ctx)
+			return ctx.Err()
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"stop": func(t *testing.T) {
+			as := newAssertions(t)
+
+			causes := make(chan error, 1)
+			inst := New(causeOf(causes), AwaitReady(true))
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			inst.Stop()
+			as.Equal([]error{context.Canceled}, waitErrors(errCh))
+			as.Equal(ErrStopped, <-causes)
+		},
+		"run timeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			causes := make(chan error, 1)
+			err := Do(context.TODO(), causeOf(causes), Timeout(testTimeDelta))
+			as.ErrorIs(err, ErrRunTimeout)
+			as.Equal(ErrRunTimeout, <-causes)
+		},
+		"heartbeat timeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			causes := make(chan error, 1)
+			err := Do(context.TODO(), causeOf(causes),
+				HeartbeatTimeout(testTimeDelta))
+			as.Equal(ErrHeartbeatTimeout, err)
+			as.Equal(ErrHeartbeatTimeout, <-causes)
+		},
+		"parent cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			causes := make(chan error, 1)
+			ctx, cancel := context.WithCancelCause(context.TODO())
+			inst := New(causeOf(causes), AwaitReady(true), WithChanBuffer(2),
+				Restart(true), RestartLimit(0, nil))
+			errCh := inst.Run(ctx)
+			as.NoError(inst.WaitReady(context.TODO()))
+			cancel(testError(1))
+
+			// The execution fails with the context error,
+			// while the instance terminates with its cause
+			// instead of restarting.
+			as.Equal([]error{context.Canceled, testError(1)}, waitErrors(errCh))
+			as.Equal(testError(1), <-causes)
+		},
+		"parent cancellation while waiting": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithCancelCause(context.TODO())
+			cancel(testError(1))
+			as.Equal(testError(1), Do(ctx, func(context.Context) error {
+				return nil
+			}, InitialDelay(time.Hour)))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,82 @@
+package run
+
+import "fmt"
+
+// OptionError describes a nonsensical combination of options,
+// as reported by NewChecked.
+type OptionError struct {
+	// Option is the name of the offending option.
+	Option string
+	// Reason describes why it is nonsensical.
+	Reason string
+}
+
+// Error satisfies error interface for OptionError.
+func (e OptionError) Error() string {
+	return fmt.Sprintf("invalid option %s: %s", e.Option, e.Reason)
+}
+
+// NewChecked creates a new runnable instance with the provided options
+// (see New), after validating their combination.
+//
+// It returns the OptionError of each nonsensical combination
+// (joined, see errors.Join), such as a period without recurrence,
+// along with a nil instance.
+func NewChecked(r Runnable, opts ...Option) (*Instance, error) {
+	inst := New(r, opts...)
+	if err := inst.opts.check(); err != nil {
+		return nil, err
+	}
+	return &inst, nil
+}
+
+// check returns the errors of the nonsensical combinations of options.
+func (o *options) check() error {
+	var errs []error
+	invalid := func(option, reason string) {
+		errs = append(errs, OptionError{Option: option, Reason: reason})
+	}
+
+	rOpts, cOpts, restart := o.recurring, o.constrained, o.restartable
+	if rOpts.period != 0 && !rOpts.recur {
+		invalid("Period", "requires Recur")
+	}
+	if rOpts.period < 0 {
+		invalid("Period", "must not be negative")
+	}
+	if rOpts.fixedRate && (!rOpts.recur || rOpts.period <= 0) {
+		invalid("FixedRate", "requires Recur with a positive Period")
+	}
+	if cOpts.timeout < 0 {
+		invalid("Timeout", "must not be negative")
+	}
+	if o.slowAfter < 0 {
+		invalid("SlowRunThreshold", "must not be negative")
+	}
+	if o.unresponsiveAfter < 0 {
+		invalid("UnresponsiveGrace", "must not be negative")
+	}
+	if o.abandonAfter < 0 {
+		invalid("AbandonAfter", "must not be negative")
+	}
+	if cOpts.runLimit != 0 && !rOpts.recur && !o.awaitsTriggers() {
+		invalid("RunLimit", "requires Recur or Triggers")
+	}
+	if restart.resetOnSuccess && !restart.restartOnError {
+		invalid("ResetOnSuccess", "requires Restart")
+	}
+	if restart.restartLimit != 0 && !restart.restartOnError {
+		invalid("RestartLimit", "requires Restart")
+	}
+	if restart.timeoutBackoff != nil && !restart.restartOnError {
+		invalid("TimeoutRestartLimit", "requires Restart")
+	}
+	if alarm := o.alarm; alarm != (alarmOptions{}) &&
+		(alarm.threshold < 0 || alarm.threshold >= 1 || alarm.window <= 0) {
+		invalid("FailureRateAlarm", "requires a threshold in [0, 1) and a positive window")
+	}
+	if o.bubble && o.parent == nil {
+		invalid("BubbleErrors", "requires ChildOf")
+	}
+	return joined(errs...)
+}
@@ -0,0 +1,89 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testCheck(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"valid options": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for _, opts := range [][]Option{
+				nil,
+				{Recur(true), Period(time.Second), FixedRate(true), RunLimit(3)},
+				{Triggers(make(chan struct{})), RunLimit(1)},
+				{ServiceProfile(), ResetOnSuccess(true)},
+				{Restart(true)},
+				{CronJobProfile(time.Minute), Timeout(time.Second)},
+			} {
+				inst, err := NewChecked(func(context.Context) error {
+					return nil
+				}, opts...)
+				as.NoError(err)
+				as.NotNil(inst.opts)
+			}
+		},
+		"invalid options": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for _, tc := range []struct {
+				opts     []Option
+				expected []OptionError
+			}{
+				{
+					opts: []Option{Period(-time.Second)},
+					expected: []OptionError{
+						{Option: "Period", Reason: "requires Recur"},
+						{Option: "Period", Reason: "must not be negative"},
+					},
+				},
+				{
+					opts: []Option{FixedRate(true), Recur(true)},
+					expected: []OptionError{
+						{Option: "FixedRate", Reason: "requires Recur with a positive Period"},
+					},
+				},
+				{
+					opts: []Option{Timeout(-time.Second), RunLimit(1)},
+					expected: []OptionError{
+						{Option: "Timeout", Reason: "must not be negative"},
+						{Option: "RunLimit", Reason: "requires Recur or Triggers"},
+					},
+				},
+				{
+					opts: []Option{ResetOnSuccess(true), RestartLimit(3, nil)},
+					expected: []OptionError{
+						{Option: "ResetOnSuccess", Reason: "requires Restart"},
+						{Option: "RestartLimit", Reason: "requires Restart"},
+					},
+				},
+				{
+					opts: []Option{BubbleErrors(true)},
+					expected: []OptionError{
+						{Option: "BubbleErrors", Reason: "requires ChildOf"},
+					},
+				},
+			} {
+				inst, err := NewChecked(nil, tc.opts...)
+				as.Nil(inst)
+				for _, expected := range tc.expected {
+					as.ErrorIs(err, expected)
+				}
+				var optErr OptionError
+				as.True(errors.As(err, &optErr))
+				as.Equal(tc.expected[0], optErr)
+			}
+
+			as.EqualError(OptionError{Option: "Period", Reason: "requires Recur"},
+				"invalid option Period: requires Recur")
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
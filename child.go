@@ -0,0 +1,131 @@
+package run
+
+import "fmt"
+
+// ChildOf makes an instance a child of the provided parent instance
+// (default: nil, no parent), so that it is stopped once its parent
+// is stopped or terminates, as processes in a tree would be.
+//
+// A child run after its parent has been stopped or has terminated
+// stops immediately. Its errors are only propagated
+// to the parent as well if BubbleErrors is set.
+func ChildOf(parent *Instance) Option {
+	return func(o *options) *options {
+		o.parent = parent
+		return o
+	}
+}
+
+// BubbleErrors indicates whether the errors of a child instance
+// (see ChildOf) are also propagated to its parent as ChildFailed events,
+// and so as ChildError to its error channel (default: false).
+//
+// Errors are only propagated while the parent is running,
+// and are not part of its terminal error.
+func BubbleErrors(bubble bool) Option {
+	return func(o *options) *options {
+		o.bubble = bubble
+		return o
+	}
+}
+
+// ChildFailed is emitted when a child of an instance propagates an error
+// (see BubbleErrors).
+type ChildFailed struct {
+	// Child is the name of the child (see WithName), if any.
+	Child string
+	// Err is the error propagated by the child.
+	Err error
+}
+
+func (ChildFailed) event() {}
+
+// ChildError represents an error propagated by a child of an instance
+// (see BubbleErrors).
+type ChildError struct {
+	// Child is the name of the child (see WithName), if any.
+	Child string
+	// Err is the error propagated by the child.
+	Err error
+}
+
+// Error satisfies error interface for ChildError.
+func (e ChildError) Error() string {
+	if e.Child == "" {
+		return fmt.Sprintf("child: %v", e.Err)
+	}
+	return fmt.Sprintf("child %s: %v", e.Child, e.Err)
+}
+
+// Unwrap returns the error propagated by the child.
+func (e ChildError) Unwrap() error {
+	return e.Err
+}
+
+// parentInstance returns the parent of an instance, if any.
+func (o *options) parentInstance() *Instance {
+	if o == nil {
+		return nil
+	}
+	return o.parent
+}
+
+// follow stops an instance once its parent (if any) is stopped
+// or terminates, until the returned function is called.
+func (i *Instance) follow() func() {
+	parent := i.options().parentInstance()
+	if parent == nil {
+		return func() {}
+	}
+
+	stopping, terminated := parent.stopping(), parent.Done()
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-stopping:
+		case <-terminated:
+		case <-done:
+			return
+		}
+		i.Stop()
+	}()
+	return func() { close(done) }
+}
+
+// bubble propagates an error of an instance to its parent,
+// if it is a child that bubbles its errors.
+func (i *Instance) bubble(err error) {
+	o := i.options()
+	if err == nil || !o.bubbles() {
+		return
+	}
+
+	parent := o.parent
+	parent.mu.Lock()
+	relay := parent.relay
+	parent.mu.Unlock()
+	if relay != nil {
+		relay(ChildFailed{Child: o.name, Err: err})
+	}
+}
+
+// bubbles indicates whether the errors of an instance
+// are propagated to its parent.
+func (o *options) bubbles() bool {
+	return o.parentInstance() != nil && o.bubble
+}
+
+// adopt sets the function the errors of the children of an instance
+// are relayed through while it runs, until the returned function is called.
+func (i *Instance) adopt(relay func(Event)) func() {
+	i.mu.Lock()
+	i.relay = relay
+	i.mu.Unlock()
+
+	return func() {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+
+		i.relay = nil
+	}
+}
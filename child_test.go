@@ -0,0 +1,172 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func testChild(t *testing.T) {
+	blocking := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+	// signalling returns a blocking runnable closing the provided channel
+	// once it runs, so that children are run after their parent.
+	signalling := func(running chan struct{}) Runnable {
+		return func(ctx context.Context) error {
+			close(running)
+			return blocking(ctx)
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"ChildOf and BubbleErrors": func(t *testing.T) {
+			as := newAssertions(t)
+
+			parent := New(nil)
+			opts := apply(t, new(options), []Option{ChildOf(&parent)})
+			as.Same(&parent, opts.parentInstance())
+			as.False(opts.bubbles())
+
+			opts = apply(t, opts, []Option{BubbleErrors(true)})
+			as.True(opts.bubbles())
+			opts = apply(t, opts, []Option{ChildOf(nil)})
+			as.False(opts.bubbles())
+
+			var zero *options
+			as.Nil(zero.parentInstance())
+		},
+		"stopped with parent": func(t *testing.T) {
+			as := newAssertions(t)
+
+			parent := New(blocking)
+			child := New(blocking, ChildOf(&parent))
+			parentErrs, childErrs := parent.Run(context.TODO()), child.Run(context.TODO())
+
+			parent.Stop()
+			as.Empty(waitErrors(childErrs))
+			as.Equal(Stopped, child.TerminationReason())
+			as.Empty(waitErrors(parentErrs))
+		},
+		"stopped once parent terminates": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			parent := New(func(context.Context) error {
+				<-release
+				return nil
+			})
+			child := New(blocking, ChildOf(&parent))
+			parentErrs, childErrs := parent.Run(context.TODO()), child.Run(context.TODO())
+
+			close(release)
+			as.Empty(waitErrors(parentErrs))
+			as.Empty(waitErrors(childErrs))
+			as.Equal(Stopped, child.TerminationReason())
+		},
+		"parent already terminated": func(t *testing.T) {
+			as := newAssertions(t)
+
+			parent := New(func(context.Context) error {
+				return nil
+			})
+			as.Empty(waitErrors(parent.Run(context.TODO())))
+
+			runs := 0
+			child := New(func(ctx context.Context) error {
+				runs++
+				return blocking(ctx)
+			}, ChildOf(&parent))
+			as.Empty(waitErrors(child.Run(context.TODO())))
+			as.Equal(Stopped, child.TerminationReason())
+			as.LessOrEqual(runs, 1)
+		},
+		"child terminated first": func(t *testing.T) {
+			as := newAssertions(t)
+
+			parent := New(blocking)
+			child := New(func(context.Context) error {
+				return nil
+			}, ChildOf(&parent))
+			parentErrs, childErrs := parent.Run(context.TODO()), child.Run(context.TODO())
+
+			as.Empty(waitErrors(childErrs))
+			as.Equal(Completed, child.TerminationReason())
+			parent.Stop()
+			as.Empty(waitErrors(parentErrs))
+		},
+		"errors bubbled": func(t *testing.T) {
+			as := newAssertions(t)
+
+			running := make(chan struct{})
+			parent := New(signalling(running))
+			evCh := parent.Events(context.TODO())
+			bubbled := make(chan []Event)
+			go func() {
+				var evs []Event
+				for ev := range evCh {
+					if _, ok := ev.(ChildFailed); ok {
+						evs = append(evs, ev)
+					}
+				}
+				bubbled <- evs
+			}()
+			<-running
+
+			child := New(func(context.Context) error {
+				return errors.New("failed")
+			}, WithName("worker"), ChildOf(&parent), BubbleErrors(true))
+			as.Equal([]error{errors.New("failed")}, waitErrors(child.Run(context.TODO())))
+
+			parent.Stop()
+			as.Equal([]Event{
+				ChildFailed{Child: "worker", Err: errors.New("failed")},
+			}, <-bubbled)
+			as.False(errors.As(parent.Err(), new(ChildError)))
+		},
+		"errors delivered to parent channel": func(t *testing.T) {
+			as := newAssertions(t)
+
+			running := make(chan struct{})
+			parent := New(signalling(running))
+			parentErrs := parent.Run(context.TODO())
+			<-running
+			child := New(func(context.Context) error {
+				return errors.New("failed")
+			}, ChildOf(&parent), BubbleErrors(true))
+			childErrs := make(chan []error)
+			go func() {
+				childErrs <- waitErrors(child.Run(context.TODO()))
+			}()
+
+			as.Equal(ChildError{Err: errors.New("failed")}, <-parentErrs)
+			as.Equal([]error{errors.New("failed")}, <-childErrs)
+			parent.Stop()
+			as.Empty(waitErrors(parentErrs))
+		},
+		"parent not running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			parent := New(blocking)
+			child := New(func(context.Context) error {
+				return errors.New("failed")
+			}, ChildOf(&parent), BubbleErrors(true))
+
+			as.Equal([]error{errors.New("failed")}, waitErrors(child.Run(context.TODO())))
+			as.Equal(Failed, child.TerminationReason())
+		},
+		"ChildError": func(t *testing.T) {
+			as := newAssertions(t)
+
+			err := ChildError{Child: "worker", Err: context.Canceled}
+			as.EqualError(err, "child worker: context canceled")
+			as.ErrorIs(err, context.Canceled)
+			as.EqualError(ChildError{Err: context.Canceled}, "child: context canceled")
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,60 @@
+package run
+
+// Outcome describes how the error of a failed execution is handled
+// (see ErrClassifier).
+type Outcome int
+
+const (
+	// Retry handles an error according to the restart options
+	// of a runnable (the default).
+	Retry Outcome = iota
+	// Ignore treats an execution as successful, discarding its error.
+	Ignore
+	// Fatal terminates an instance without restarting it.
+	Fatal
+)
+
+// String returns the description of an outcome.
+func (o Outcome) String() string {
+	switch o {
+	case Retry:
+		return "retry"
+	case Ignore:
+		return "ignore"
+	case Fatal:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrClassifier sets a function classifying the errors of failed executions
+// of a runnable (default: nil, retrying all of them).
+//
+// Ignored errors are discarded, with their executions accounted
+// as successful (including towards the run limit),
+// while fatal ones terminate the instance regardless of its restart options.
+// Retried errors are subject to the restart options of the runnable.
+func ErrClassifier(classify func(error) Outcome) Option {
+	return func(o *options) *options {
+		o.classifier = classify
+		return o
+	}
+}
+
+// classify returns the outcome of the provided error of an execution.
+func (o *options) classify(err error) Outcome {
+	if err == nil || o == nil || o.classifier == nil {
+		return Retry
+	}
+	return o.classifier(err)
+}
+
+// ignored returns the provided error of an execution,
+// or nil if it is classified as ignored.
+func (o *options) ignored(err error) error {
+	if o.classify(err) == Ignore {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func testClassify(t *testing.T) {
+	errAuth := errors.New("unauthorized")
+	classifier := func(err error) Outcome {
+		switch {
+		case errors.Is(err, io.EOF):
+			return Ignore
+		case errors.Is(err, errAuth):
+			return Fatal
+		}
+		return Retry
+	}
+
+	subtests := map[string]func(*testing.T){
+		"outcomes": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for outcome, desc := range map[Outcome]string{
+				Retry:  "retry",
+				Ignore: "ignore",
+				Fatal:  "fatal",
+				-1:     "unknown",
+			} {
+				as.Equal(desc, outcome.String())
+			}
+
+			var opts *options
+			as.Equal(Retry, opts.classify(errAuth))
+			opts = apply(t, new(options), []Option{ErrClassifier(classifier)})
+			as.Equal(Retry, opts.classify(nil))
+			as.Equal(Fatal, opts.classify(errAuth))
+		},
+		"retried and ignored": func(t *testing.T) {
+			as := newAssertions(t)
+
+			errs := []error{testError(1), io.EOF, nil}
+			inst := New(func(context.Context) error {
+				err := errs[0]
+				errs = errs[1:]
+				return err
+			}, Restart(true), RestartLimit(0, nil), Recur(true), RunLimit(2),
+				ErrClassifier(classifier))
+
+			as.Equal([]error{testError(1)}, waitErrors(inst.Run(context.TODO())))
+			as.Empty(errs)
+			as.Equal(uint64(2), inst.SuccessfulRuns())
+			as.Equal(RunLimitReached, inst.TerminationReason())
+		},
+		"fatal": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return errAuth
+			}, Restart(true), RestartLimit(0, nil), ErrClassifier(classifier))
+
+			as.Equal([]error{errAuth}, waitErrors(inst.Run(context.TODO())))
+			as.Equal(1, runs)
+			as.Equal(Failed, inst.TerminationReason())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,164 @@
+package run
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers to instances (see WithClock),
+// allowing time to be controlled in tests (see runtest.Clock).
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a timer delivering the current time
+	// on its channel once the provided duration elapses.
+	NewTimer(d time.Duration) Timer
+	// AfterFunc creates a timer calling the provided function
+	// once the provided duration elapses.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer represents a single event, as created by a Clock.
+type Timer interface {
+	// C returns the channel where the timer delivers the time it fires at
+	// (nil, for timers created by AfterFunc).
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, and indicates whether
+	// it was active.
+	Stop() bool
+	// Reset changes the timer to fire once the provided duration elapses,
+	// and indicates whether it was active.
+	Reset(d time.Duration) bool
+}
+
+// SystemClock is the clock of the system, used by default.
+//
+// Within a testing/synctest bubble, it follows the fake time of the bubble,
+// provided that the instances using it are run from within the bubble.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{t: time.NewTimer(d)}
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return systemTimer{t: time.AfterFunc(d, f)}
+}
+
+// systemTimer is a timer of the system clock.
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t systemTimer) Stop() bool {
+	return t.t.Stop()
+}
+
+func (t systemTimer) Reset(d time.Duration) bool {
+	return t.t.Reset(d)
+}
+
+// WithClock sets the clock used for the timing of an instance
+// (default: SystemClock), including its delays, periods, backoff,
+// timeouts and execution statistics.
+func WithClock(c Clock) Option {
+	return func(o *options) *options {
+		o.timing = c
+		return o
+	}
+}
+
+// clock returns the clock used for the timing of a runnable.
+func (o *options) clock() Clock {
+	if o == nil || o.timing == nil {
+		return SystemClock
+	}
+	return o.timing
+}
+
+// rearm returns a timer firing after the provided duration,
+// resetting the provided one (if set) rather than creating a new one,
+// after discarding any expiration it delivered without being received.
+func rearm(c Clock, t Timer, d time.Duration) Timer {
+	if t == nil {
+		return c.NewTimer(d)
+	}
+	if !t.Stop() {
+		select {
+		case <-t.C():
+		default:
+		}
+	}
+	t.Reset(d)
+	return t
+}
+
+// withClockTimeout creates a child of the provided context, which is
+// cancelled with the provided cause once the provided timeout elapses
+// according to the provided clock,
+// and returns it along with its cancellation function.
+func withClockTimeout(ctx context.Context, c Clock, d time.Duration,
+	cause error) (context.Context, context.CancelFunc) {
+
+	if c == SystemClock {
+		return context.WithTimeoutCause(ctx, d, cause)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	dc := &deadlineContext{Context: ctx, deadline: c.Now().Add(d)}
+	timer := c.AfterFunc(d, func() {
+		dc.expire()
+		cancel(cause)
+	})
+	return dc, func() {
+		timer.Stop()
+		cancel(nil)
+	}
+}
+
+// deadlineContext is a context cancelled once its deadline elapses
+// according to a clock other than the system clock.
+type deadlineContext struct {
+	context.Context
+	deadline time.Time
+
+	// mu guards whether the deadline of the context has elapsed.
+	mu      sync.Mutex
+	expired bool
+}
+
+func (c *deadlineContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *deadlineContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.expired {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
+
+// expire marks the deadline of a context as elapsed,
+// unless it is already cancelled.
+func (c *deadlineContext) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Context.Err() == nil {
+		c.expired = true
+	}
+}
@@ -0,0 +1,164 @@
+package run
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock is a system clock, whose timers created by AfterFunc
+// only fire when triggered explicitly.
+type manualClock struct {
+	systemClock
+
+	mu  sync.Mutex
+	fns []func()
+}
+
+func (c *manualClock) AfterFunc(_ time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fns = append(c.fns, f)
+	return systemTimer{t: time.NewTimer(time.Hour)}
+}
+
+// fire calls the functions of all timers created by AfterFunc.
+func (c *manualClock) fire() {
+	c.mu.Lock()
+	fns := c.fns
+	c.fns = nil
+	c.mu.Unlock()
+
+	for _, f := range fns {
+		f()
+	}
+}
+
+// steppingClock is a system clock, whose current time advances
+// by step each time it is read.
+type steppingClock struct {
+	systemClock
+
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func testClock(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"WithClock": func(t *testing.T) {
+			as := newAssertions(t)
+
+			c := new(manualClock)
+			opts := apply(t, new(options), []Option{WithClock(c)})
+			as.Equal(&options{timing: c}, opts)
+			as.Equal(c, opts.clock())
+
+			as.Equal(SystemClock, new(options).clock())
+			opts = nil
+			as.Equal(SystemClock, opts.clock())
+		},
+		"system clock": func(t *testing.T) {
+			as := newAssertions(t)
+
+			before := time.Now()
+			as.False(SystemClock.Now().Before(before))
+
+			timer := SystemClock.NewTimer(time.Hour)
+			as.True(timer.Stop())
+			as.False(timer.Reset(0))
+			as.False((<-timer.C()).Before(before))
+
+			done := make(chan struct{})
+			timer = SystemClock.AfterFunc(0, func() {
+				close(done)
+			})
+			<-done
+			as.Nil(timer.C())
+		},
+		"rearm": func(t *testing.T) {
+			as := newAssertions(t)
+
+			timer := rearm(SystemClock, nil, 0)
+			// Wait for it to fire without receiving from it.
+			time.Sleep(testTimeDelta)
+			as.Equal(timer, rearm(SystemClock, timer, time.Hour))
+			select {
+			case <-timer.C():
+				as.Fail("stale expiration")
+			default:
+			}
+
+			as.Equal(timer, rearm(SystemClock, timer, 0))
+			<-timer.C()
+			timer.Stop()
+		},
+		"deadline": func(t *testing.T) {
+			as := newAssertions(t)
+
+			c := new(manualClock)
+			before := time.Now()
+			ctx, cancel := withClockTimeout(context.TODO(), c, time.Minute, testError(1))
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			as.True(ok)
+			as.False(deadline.Before(before.Add(time.Minute)))
+			as.NoError(ctx.Err())
+
+			c.fire()
+			<-ctx.Done()
+			as.Equal(context.DeadlineExceeded, ctx.Err())
+			as.Equal(testError(1), context.Cause(ctx))
+		},
+		"cancelled before deadline": func(t *testing.T) {
+			as := newAssertions(t)
+
+			c := new(manualClock)
+			ctx, cancel := withClockTimeout(context.TODO(), c, time.Minute, testError(1))
+			cancel()
+			c.fire()
+			as.Equal(context.Canceled, ctx.Err())
+		},
+		"system clock timeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := withClockTimeout(context.TODO(), SystemClock, 0, testError(1))
+			defer cancel()
+			<-ctx.Done()
+			as.Equal(context.DeadlineExceeded, ctx.Err())
+			as.Equal(testError(1), context.Cause(ctx))
+		},
+		"instance timeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			c := new(manualClock)
+			started := make(chan struct{})
+			go func() {
+				<-started
+				c.fire()
+			}()
+			err := Do(context.TODO(), func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			}, WithClock(c), Timeout(time.Minute))
+			as.ErrorIs(err, ErrRunTimeout)
+			as.ErrorIs(err, context.DeadlineExceeded)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,99 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// DefaultKillGrace is the amount of time the process of a command runnable
+// created by Command is given to exit after being terminated,
+// before it is killed.
+const DefaultKillGrace = 5 * time.Second
+
+// ExitError is returned by an execution of a command runnable
+// whose process exits with a non-zero status.
+type ExitError struct {
+	// Code is the exit code of the process
+	// (-1 if it was terminated by a signal).
+	Code int
+	// Err is the underlying error returned by exec.Cmd.Wait.
+	Err error
+}
+
+// Error satisfies error interface for ExitError.
+func (e ExitError) Error() string {
+	return fmt.Sprintf("process exited with code %d", e.Code)
+}
+
+// Unwrap returns the underlying error returned by exec.Cmd.Wait.
+func (e ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Command returns a runnable executing an external command
+// in a new process on each execution, with DefaultKillGrace.
+//
+// See Process for details.
+func Command(name string, args ...string) Runnable {
+	return Process(func() *exec.Cmd {
+		return exec.Command(name, args...)
+	}, DefaultKillGrace)
+}
+
+// Process returns a runnable executing the command created by the provided
+// function in a new process on each execution (allowing its environment,
+// input and output to be set), until the process exits.
+// A process exiting with a non-zero status fails with ExitError,
+// so restart options apply.
+//
+// Once the context of an execution is cancelled, the process is
+// terminated (SIGTERM), and killed if it has not exited within
+// the provided grace period (or immediately, if it cannot be terminated),
+// with the execution returning the context error once it exits.
+func Process(cmd func() *exec.Cmd, grace time.Duration) Runnable {
+	return func(ctx context.Context) error {
+		c := cmd()
+		if err := c.Start(); err != nil {
+			return err
+		}
+
+		exited := make(chan error, 1)
+		go func() {
+			exited <- c.Wait()
+		}()
+
+		select {
+		case err := <-exited:
+			return exitError(err)
+		case <-ctx.Done():
+		}
+
+		if err := c.Process.Signal(syscall.SIGTERM); err == nil {
+			timer := time.NewTimer(grace)
+			defer timer.Stop()
+
+			select {
+			case <-exited:
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		_ = c.Process.Kill()
+		<-exited
+		return ctx.Err()
+	}
+}
+
+// exitError converts the error returned by exec.Cmd.Wait to ExitError,
+// if it concerns the exit status of the process.
+func exitError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return ExitError{Code: exitErr.ExitCode(), Err: err}
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// failingWriter fails all writes.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, testError("write")
+}
+
+func testCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	subtests := map[string]func(*testing.T){
+		"successful process": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.NoError(Do(context.TODO(), Command("sh", "-c", "exit 0")))
+		},
+		"exit codes are errors": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(Command("sh", "-c", "exit 3"),
+				Restart(true), RestartLimit(2, nil))
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.Len(errs, 2)
+
+			var exitErr ExitError
+			as.True(errors.As(errs[0], &exitErr))
+			as.Equal(3, exitErr.Code)
+			as.Equal("process exited with code 3", exitErr.Error())
+
+			var execErr *exec.ExitError
+			as.True(errors.As(errs[0], &execErr))
+		},
+		"start failure": func(t *testing.T) {
+			as := newAssertions(t)
+
+			err := Do(context.TODO(), Command("/nonexistent/command"))
+			as.Error(err)
+			as.False(errors.As(err, new(ExitError)))
+		},
+		"wait failure": func(t *testing.T) {
+			as := newAssertions(t)
+
+			err := Do(context.TODO(), Process(func() *exec.Cmd {
+				cmd := exec.Command("sh", "-c", "echo output")
+				cmd.Stdout = failingWriter{}
+				return cmd
+			}, time.Second))
+			as.Equal(testError("write"), err)
+		},
+		"terminated on cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+
+			start := time.Now()
+			err := Do(ctx, Process(func() *exec.Cmd {
+				return exec.Command("sh", "-c", "exec sleep 10")
+			}, 10*time.Second))
+			as.Equal(context.DeadlineExceeded, err)
+			as.Less(int64(time.Since(start)), int64(5*time.Second))
+		},
+		"killed after grace period": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+
+			start := time.Now()
+			err := Do(ctx, Process(func() *exec.Cmd {
+				return exec.Command("sh", "-c", `trap "" TERM; exec sleep 10`)
+			}, testTimeDelta))
+			as.Equal(context.DeadlineExceeded, err)
+			as.Less(int64(time.Since(start)), int64(5*time.Second))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,32 @@
+package run
+
+// Concurrency runs the provided number of copies
+// of the runnable of an instance concurrently (default: 1).
+// A value of 0 is equivalent to 1.
+//
+// The copies share the execution statistics and the run and restart limits
+// of the instance, which terminates once all of them do.
+// Reaching either limit prevents further executions of all copies,
+// though ongoing ones are allowed to complete.
+// Each copy follows the recurrence and restart options separately,
+// and is wrapped by the middleware of the instance separately.
+//
+// A recovered panic in any copy cancels the rest,
+// terminating the instance as usual.
+// Executions abandoned by AbandonAfter are not accounted against
+// the number of copies, and may overlap with later ones.
+// The state of the instance reflects the latest transition of any copy.
+func Concurrency(n uint) Option {
+	return func(o *options) *options {
+		o.concurrency = n
+		return o
+	}
+}
+
+// workers returns the number of copies of a runnable to execute concurrently.
+func (o *options) workers() uint {
+	if o == nil || o.concurrency == 0 {
+		return 1
+	}
+	return o.concurrency
+}
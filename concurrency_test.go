@@ -0,0 +1,148 @@
+package run
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testConcurrency(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"copies execute concurrently": func(t *testing.T) {
+			as := newAssertions(t)
+
+			// Each copy waits for all of them to start.
+			var wg sync.WaitGroup
+			wg.Add(3)
+			inst := New(func(context.Context) error {
+				wg.Done()
+				wg.Wait()
+				return nil
+			}, Concurrency(3))
+
+			as.Equal([]Event{
+				RunStarted{}, RunStarted{}, RunStarted{},
+				RunSucceeded{}, RunSucceeded{}, RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.Equal(uint64(3), inst.Stats().Runs)
+			as.Equal(StateTerminated, inst.State())
+		},
+		"run limit is shared": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			inst := New(func(context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			}, Concurrency(2), Recur(true), Period(time.Millisecond), RunLimit(5))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			// Ongoing executions complete once the limit is reached.
+			as.GreaterOrEqual(atomic.LoadInt32(&calls), int32(5))
+			as.LessOrEqual(atomic.LoadInt32(&calls), int32(6))
+		},
+		"reaching a limit halts waiting copies": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			failed := make(chan struct{})
+			inst := New(func(context.Context) error {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					<-failed
+					time.Sleep(testTimeDelta)
+					return nil
+				}
+				close(failed)
+				return testError(2)
+			}, Concurrency(2), Recur(true), Period(time.Hour), RunLimit(1),
+				Restart(true), RestartLimit(0, ConstantBackoff(time.Hour)))
+
+			start := time.Now()
+			as.Equal([]error{testError(2)}, waitErrors(inst.Run(context.TODO())))
+			as.Less(time.Since(start), time.Hour)
+		},
+		"reaching a limit halts restarting copies": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			started := make(chan struct{})
+			inst := New(func(context.Context) error {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					<-started
+					return nil
+				}
+				close(started)
+				time.Sleep(testTimeDelta)
+				return testError(2)
+			}, Concurrency(2), Recur(true), Period(time.Hour), RunLimit(1),
+				Restart(true), RestartLimit(0, nil))
+
+			as.Equal([]error{testError(2)}, waitErrors(inst.Run(context.TODO())))
+			as.Equal(int32(2), atomic.LoadInt32(&calls))
+		},
+		"restart limit is shared": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, Concurrency(2), Restart(true),
+				RestartLimit(4, ConstantBackoff(time.Millisecond)))
+
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.GreaterOrEqual(len(errs), 4)
+			as.LessOrEqual(len(errs), 5)
+			for _, err := range errs {
+				as.Equal(testError(1), err)
+			}
+		},
+		"panic cancels other copies": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			inst := New(func(ctx context.Context) error {
+				if atomic.AddInt32(&calls, 1) == 2 {
+					panic("panic message")
+				}
+				<-ctx.Done()
+				return ctx.Err()
+			}, Concurrency(2), Recover(true))
+
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.Equal([]error{
+				context.Canceled,
+				RunnablePanic{Value: "panic message"},
+			}, errs)
+			as.Equal(uint64(2), inst.Stats().FailedRuns)
+		},
+		"cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, Concurrency(3), Recur(true), Period(time.Hour))
+
+			as.Equal([]error{context.DeadlineExceeded}, waitErrors(inst.Run(ctx)))
+			as.Equal(uint64(3), inst.Stats().Runs)
+		},
+		"results of concurrent copies": func(t *testing.T) {
+			as := newAssertions(t)
+
+			res, err := DoValue(context.TODO(), func(context.Context) (int, error) {
+				return 1, nil
+			}, Concurrency(4))
+
+			as.Equal(1, res)
+			as.NoError(err)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,372 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Config describes the options of an instance in a form
+// suitable for configuration files (see OptionsFromConfig).
+//
+// Durations are specified as strings parsed by time.ParseDuration
+// (e.g. "1m30s"), and unset (zero) fields leave the respective option
+// at its default.
+type Config struct {
+	// Name and Labels correspond to WithName and WithLabels.
+	Name   string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Recur, Period, PeriodJitter and FixedRate correspond to
+	// the respective options.
+	Recur        bool    `json:"recur,omitempty" yaml:"recur,omitempty"`
+	Period       string  `json:"period,omitempty" yaml:"period,omitempty"`
+	PeriodJitter float64 `json:"periodJitter,omitempty" yaml:"periodJitter,omitempty"`
+	FixedRate    bool    `json:"fixedRate,omitempty" yaml:"fixedRate,omitempty"`
+	// Cron is a cron expression (see ParseCron),
+	// evaluated in the local time zone unless it specifies one.
+	Cron string `json:"cron,omitempty" yaml:"cron,omitempty"`
+
+	// InitialDelay, StartSplay, Timeout, TotalTimeout, StopGrace
+	// and Concurrency correspond to the respective options.
+	InitialDelay string `json:"initialDelay,omitempty" yaml:"initialDelay,omitempty"`
+	StartSplay   string `json:"startSplay,omitempty" yaml:"startSplay,omitempty"`
+	Timeout      string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	TotalTimeout string `json:"totalTimeout,omitempty" yaml:"totalTimeout,omitempty"`
+	StopGrace    string `json:"stopGrace,omitempty" yaml:"stopGrace,omitempty"`
+	Concurrency  uint   `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+
+	// RunLimit and AttemptLimit correspond to the respective options.
+	RunLimit     uint64 `json:"runLimit,omitempty" yaml:"runLimit,omitempty"`
+	AttemptLimit uint64 `json:"attemptLimit,omitempty" yaml:"attemptLimit,omitempty"`
+
+	// Restart, RestartLimit, ResetOnSuccess and ResetAfterStable
+	// correspond to the respective options.
+	Restart          bool   `json:"restart,omitempty" yaml:"restart,omitempty"`
+	RestartLimit     uint64 `json:"restartLimit,omitempty" yaml:"restartLimit,omitempty"`
+	ResetOnSuccess   bool   `json:"resetOnSuccess,omitempty" yaml:"resetOnSuccess,omitempty"`
+	ResetAfterStable string `json:"resetAfterStable,omitempty" yaml:"resetAfterStable,omitempty"`
+	// Backoff specifies the backoff function (see Backoff) as one of:
+	//  - "none", for no backoff;
+	//  - "constant:<period>", e.g. "constant:1s";
+	//  - "exponential:<base>[:<factor>[:<ceiling>]]", e.g.
+	//    "exponential:100ms:2:30s", for a period starting at base
+	//    and multiplied by factor (default: 2) after each consecutive
+	//    failed execution, up to ceiling (default: none),
+	//    where "exp" can be used in place of "exponential".
+	Backoff string `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	// TimeoutRestartLimit and TimeoutBackoff correspond to the arguments
+	// of TimeoutRestartLimit (set if either is), with the latter
+	// specified as Backoff is.
+	TimeoutRestartLimit uint64 `json:"timeoutRestartLimit,omitempty" yaml:"timeoutRestartLimit,omitempty"`
+	TimeoutBackoff      string `json:"timeoutBackoff,omitempty" yaml:"timeoutBackoff,omitempty"`
+
+	// Recover corresponds to the respective option.
+	Recover bool `json:"recover,omitempty" yaml:"recover,omitempty"`
+
+	// SlowRunThreshold, UnresponsiveGrace, AbandonAfter and DedupeErrors
+	// correspond to the respective options.
+	SlowRunThreshold  string `json:"slowRunThreshold,omitempty" yaml:"slowRunThreshold,omitempty"`
+	UnresponsiveGrace string `json:"unresponsiveGrace,omitempty" yaml:"unresponsiveGrace,omitempty"`
+	AbandonAfter      string `json:"abandonAfter,omitempty" yaml:"abandonAfter,omitempty"`
+	DedupeErrors      bool   `json:"dedupeErrors,omitempty" yaml:"dedupeErrors,omitempty"`
+	// FailureRateThreshold, FailureRateWindow and FailureRateMinRuns
+	// correspond to the arguments of FailureRateAlarm
+	// (set if any of them is).
+	FailureRateThreshold float64 `json:"failureRateThreshold,omitempty" yaml:"failureRateThreshold,omitempty"`
+	FailureRateWindow    string  `json:"failureRateWindow,omitempty" yaml:"failureRateWindow,omitempty"`
+	FailureRateMinRuns   uint    `json:"failureRateMinRuns,omitempty" yaml:"failureRateMinRuns,omitempty"`
+}
+
+// OptionsFromConfig returns the options described by a configuration,
+// to be provided to New (or NewChecked, to validate their combination).
+//
+// It returns an error describing each invalid field (joined,
+// see errors.Join), in which case the options should not be used.
+func OptionsFromConfig(c Config) ([]Option, error) {
+	opts, errs := c.options(nil, func(field, value, reason string) error {
+		return fmt.Errorf("invalid config %s %q: %s", field, value, reason)
+	})
+	if err := joined(errs...); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// options returns the options described by a configuration,
+// appending an error describing each invalid field
+// (through the provided function) to errs.
+func (c Config) options(errs []error,
+	invalid func(field, value, reason string) error) ([]Option, []error) {
+	var opts []Option
+	parse := func(field, value string) (time.Duration, bool) {
+		if value == "" {
+			return 0, true
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			errs = append(errs, invalid(field, value, err.Error()))
+			return 0, false
+		}
+		return d, true
+	}
+	duration := func(field, value string, option func(time.Duration) Option) {
+		if d, ok := parse(field, value); ok && value != "" {
+			opts = append(opts, option(d))
+		}
+	}
+	backoff := func(field, value string) (BackoffFn, bool) {
+		if value == "" {
+			return nil, true
+		}
+		boff, err := parseBackoff(value)
+		if err != nil {
+			errs = append(errs, invalid(field, value, err.Error()))
+			return nil, false
+		}
+		return boff, true
+	}
+
+	if c.Name != "" {
+		opts = append(opts, WithName(c.Name))
+	}
+	if c.Labels != nil {
+		opts = append(opts, WithLabels(c.Labels))
+	}
+	if c.Recur {
+		opts = append(opts, Recur(true))
+	}
+	duration("period", c.Period, Period)
+	if c.PeriodJitter != 0 {
+		opts = append(opts, PeriodJitter(c.PeriodJitter))
+	}
+	if c.FixedRate {
+		opts = append(opts, FixedRate(true))
+	}
+	if c.Cron != "" {
+		sched, err := ParseCron(c.Cron, nil)
+		if err != nil {
+			errs = append(errs, invalid("cron", c.Cron, err.Error()))
+		} else {
+			opts = append(opts, Cron(sched))
+		}
+	}
+	duration("initialDelay", c.InitialDelay, InitialDelay)
+	duration("startSplay", c.StartSplay, StartSplay)
+	duration("timeout", c.Timeout, Timeout)
+	duration("totalTimeout", c.TotalTimeout, TotalTimeout)
+	duration("stopGrace", c.StopGrace, StopGrace)
+	if c.Concurrency != 0 {
+		opts = append(opts, Concurrency(c.Concurrency))
+	}
+	if c.RunLimit != 0 {
+		opts = append(opts, RunLimit(c.RunLimit))
+	}
+	if c.AttemptLimit != 0 {
+		opts = append(opts, AttemptLimit(c.AttemptLimit))
+	}
+	if c.Restart {
+		opts = append(opts, Restart(true))
+	}
+	if c.RestartLimit != 0 {
+		opts = append(opts, RestartLimit(c.RestartLimit, nil))
+	}
+	if boff, ok := backoff("backoff", c.Backoff); ok && boff != nil {
+		opts = append(opts, Backoff(boff))
+	}
+	if c.ResetOnSuccess {
+		opts = append(opts, ResetOnSuccess(true))
+	}
+	duration("resetAfterStable", c.ResetAfterStable, ResetAfterStable)
+	if boff, ok := backoff("timeoutBackoff", c.TimeoutBackoff); ok &&
+		(c.TimeoutRestartLimit != 0 || boff != nil) {
+		opts = append(opts, TimeoutRestartLimit(c.TimeoutRestartLimit, boff))
+	}
+	if c.Recover {
+		opts = append(opts, Recover(true))
+	}
+	duration("slowRunThreshold", c.SlowRunThreshold, SlowRunThreshold)
+	duration("unresponsiveGrace", c.UnresponsiveGrace, UnresponsiveGrace)
+	duration("abandonAfter", c.AbandonAfter, AbandonAfter)
+	if c.DedupeErrors {
+		opts = append(opts, DedupeErrors(true))
+	}
+	if window, ok := parse("failureRateWindow", c.FailureRateWindow); ok &&
+		(c.FailureRateThreshold != 0 || window != 0 || c.FailureRateMinRuns != 0) {
+		opts = append(opts, FailureRateAlarm(c.FailureRateThreshold, window,
+			c.FailureRateMinRuns))
+	}
+
+	return opts, errs
+}
+
+// parseBackoff parses a backoff specification (see Config.Backoff).
+func parseBackoff(spec string) (BackoffFn, error) {
+	kind, args, _ := strings.Cut(spec, ":")
+	params := strings.Split(args, ":")
+	switch {
+	case kind == "none" && args == "":
+		return ConstantBackoff(0), nil
+	case kind == "constant" && len(params) == 1:
+		d, err := time.ParseDuration(params[0])
+		if err != nil {
+			return nil, err
+		}
+		return ConstantBackoff(d), nil
+	case (kind == "exponential" || kind == "exp") && len(params) <= 3:
+		base, err := time.ParseDuration(params[0])
+		if err != nil {
+			return nil, err
+		}
+		factor := 2.0
+		if len(params) > 1 {
+			factor, err = strconv.ParseFloat(params[1], 64)
+			if err != nil {
+				return nil, err
+			}
+			if !(factor >= 1) || math.IsInf(factor, 1) {
+				return nil, errors.New("factor must be finite and at least 1")
+			}
+		}
+		var ceiling time.Duration
+		if len(params) > 2 {
+			ceiling, err = time.ParseDuration(params[2])
+			if err != nil {
+				return nil, err
+			}
+		}
+		return scaledBackoff(base, factor, ceiling), nil
+	}
+	return nil, errors.New("unknown backoff specification")
+}
+
+// OptionsFromEnv returns the options described by environment variables
+// named after the fields of Config in upper snake case, prefixed by
+// the provided prefix and an underscore (e.g. MYJOB_PERIOD=30s,
+// MYJOB_RESTART_LIMIT=5, MYJOB_BACKOFF=exp:1s:2:1m),
+// with labels specified as comma-separated key=value pairs
+// (e.g. MYJOB_LABELS=team=infra,tier=batch).
+//
+// Unset variables leave the respective option at its default.
+// It returns an error describing each invalid variable (joined,
+// see errors.Join), in which case the options should not be used.
+func OptionsFromEnv(prefix string) ([]Option, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	var (
+		c    Config
+		errs []error
+	)
+	invalid := func(field, value, reason string) error {
+		return fmt.Errorf("invalid environment variable %s %q: %s",
+			envKey(prefix, field), value, reason)
+	}
+	lookup := func(field string, parse func(string) error) {
+		value, ok := os.LookupEnv(envKey(prefix, field))
+		if !ok {
+			return
+		}
+		if err := parse(value); err != nil {
+			errs = append(errs, invalid(field, value, err.Error()))
+		}
+	}
+	str := func(dst *string) func(string) error {
+		return func(v string) error {
+			*dst = v
+			return nil
+		}
+	}
+	boolean := func(dst *bool) func(string) error {
+		return func(v string) (err error) {
+			*dst, err = strconv.ParseBool(v)
+			return err
+		}
+	}
+	uint64s := func(dst *uint64) func(string) error {
+		return func(v string) (err error) {
+			*dst, err = strconv.ParseUint(v, 10, 64)
+			return err
+		}
+	}
+	uints := func(dst *uint) func(string) error {
+		return func(v string) error {
+			n, err := strconv.ParseUint(v, 10, 0)
+			*dst = uint(n)
+			return err
+		}
+	}
+	floats := func(dst *float64) func(string) error {
+		return func(v string) (err error) {
+			*dst, err = strconv.ParseFloat(v, 64)
+			return err
+		}
+	}
+
+	lookup("name", str(&c.Name))
+	lookup("labels", func(v string) error {
+		c.Labels = make(map[string]string)
+		for _, pair := range strings.Split(v, ",") {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok || k == "" {
+				return fmt.Errorf("invalid label %q", pair)
+			}
+			c.Labels[k] = val
+		}
+		return nil
+	})
+	lookup("recur", boolean(&c.Recur))
+	lookup("period", str(&c.Period))
+	lookup("periodJitter", floats(&c.PeriodJitter))
+	lookup("fixedRate", boolean(&c.FixedRate))
+	lookup("cron", str(&c.Cron))
+	lookup("initialDelay", str(&c.InitialDelay))
+	lookup("startSplay", str(&c.StartSplay))
+	lookup("timeout", str(&c.Timeout))
+	lookup("totalTimeout", str(&c.TotalTimeout))
+	lookup("stopGrace", str(&c.StopGrace))
+	lookup("concurrency", uints(&c.Concurrency))
+	lookup("runLimit", uint64s(&c.RunLimit))
+	lookup("attemptLimit", uint64s(&c.AttemptLimit))
+	lookup("restart", boolean(&c.Restart))
+	lookup("restartLimit", uint64s(&c.RestartLimit))
+	lookup("resetOnSuccess", boolean(&c.ResetOnSuccess))
+	lookup("resetAfterStable", str(&c.ResetAfterStable))
+	lookup("backoff", str(&c.Backoff))
+	lookup("timeoutRestartLimit", uint64s(&c.TimeoutRestartLimit))
+	lookup("timeoutBackoff", str(&c.TimeoutBackoff))
+	lookup("recover", boolean(&c.Recover))
+	lookup("slowRunThreshold", str(&c.SlowRunThreshold))
+	lookup("unresponsiveGrace", str(&c.UnresponsiveGrace))
+	lookup("abandonAfter", str(&c.AbandonAfter))
+	lookup("dedupeErrors", boolean(&c.DedupeErrors))
+	lookup("failureRateThreshold", floats(&c.FailureRateThreshold))
+	lookup("failureRateWindow", str(&c.FailureRateWindow))
+	lookup("failureRateMinRuns", uints(&c.FailureRateMinRuns))
+
+	opts, errs := c.options(errs, invalid)
+	if err := joined(errs...); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// envKey returns the name of the environment variable
+// corresponding to a configuration field (see OptionsFromEnv).
+func envKey(prefix, field string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, r := range field {
+		if unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,279 @@
+package run
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func testConfig(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"empty config": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts, err := OptionsFromConfig(Config{})
+			as.NoError(err)
+			as.Empty(opts)
+		},
+		"config file": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var c Config
+			as.NoError(json.Unmarshal([]byte(`{
+				"name": "sync",
+				"labels": {"team": "infra"},
+				"recur": true,
+				"period": "1m",
+				"periodJitter": 0.1,
+				"fixedRate": true,
+				"initialDelay": "5s",
+				"timeout": "30s",
+				"totalTimeout": "1h",
+				"stopGrace": "10s",
+				"runLimit": 10,
+				"attemptLimit": 20,
+				"restart": true,
+				"restartLimit": 3,
+				"resetOnSuccess": true,
+				"resetAfterStable": "2m",
+				"backoff": "exponential:100ms:2:30s",
+				"timeoutRestartLimit": 4,
+				"timeoutBackoff": "constant:1m",
+				"recover": true,
+				"startSplay": "3s",
+				"concurrency": 2,
+				"slowRunThreshold": "20s",
+				"unresponsiveGrace": "15s",
+				"abandonAfter": "45s",
+				"dedupeErrors": true,
+				"failureRateThreshold": 0.5,
+				"failureRateWindow": "10m",
+				"failureRateMinRuns": 5
+			}`), &c))
+
+			fromConfig, err := OptionsFromConfig(c)
+			as.NoError(err)
+			actual := apply(t, new(options), fromConfig)
+			expected := apply(t, new(options), []Option{
+				WithName("sync"),
+				WithLabels(map[string]string{"team": "infra"}),
+				Recur(true), Period(time.Minute), PeriodJitter(0.1),
+				FixedRate(true), InitialDelay(5 * time.Second),
+				Timeout(30 * time.Second), TotalTimeout(time.Hour),
+				StopGrace(10 * time.Second), RunLimit(10), AttemptLimit(20),
+				Restart(true), RestartLimit(3, nil), ResetOnSuccess(true),
+				ResetAfterStable(2 * time.Minute), Recover(true),
+				TimeoutRestartLimit(4, nil), StartSplay(3 * time.Second),
+				Concurrency(2), SlowRunThreshold(20 * time.Second),
+				UnresponsiveGrace(15 * time.Second), AbandonAfter(45 * time.Second),
+				DedupeErrors(true), FailureRateAlarm(0.5, 10*time.Minute, 5),
+			})
+
+			backoff, timeoutBackoff := actual.restartable.backoff,
+				actual.restartable.timeoutBackoff
+			actual.restartable.backoff, expected.restartable.backoff = nil, nil
+			actual.restartable.timeoutBackoff, expected.restartable.timeoutBackoff = nil, nil
+			as.Equal(expected, actual)
+			as.Equal(time.Minute, timeoutBackoff(3))
+			as.Equal(100*time.Millisecond, backoff(1))
+			as.Equal(200*time.Millisecond, backoff(2))
+			as.Equal(30*time.Second, backoff(20))
+		},
+		"cron": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts, err := OptionsFromConfig(Config{Recur: true, Cron: "0 3 * * *"})
+			as.NoError(err)
+			as.Equal(apply(t, new(options), []Option{
+				Recur(true), Cron(MustParseCron("0 3 * * *", nil)),
+			}), apply(t, new(options), opts))
+		},
+		"invalid config": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts, err := OptionsFromConfig(Config{
+				Period:            "1 minute",
+				Timeout:           "30s",
+				Cron:              "* *",
+				Backoff:           "linear:1s",
+				TimeoutBackoff:    "constant",
+				FailureRateWindow: "long",
+			})
+			as.Nil(opts)
+			if as.Error(err) {
+				as.Contains(err.Error(), `invalid config period "1 minute"`)
+				as.Contains(err.Error(), `invalid config cron "* *"`)
+				as.Contains(err.Error(),
+					`invalid config backoff "linear:1s": unknown backoff specification`)
+				as.Contains(err.Error(), `invalid config timeoutBackoff "constant"`)
+				as.Contains(err.Error(), `invalid config failureRateWindow "long"`)
+				as.NotContains(err.Error(), `config timeout "`)
+				as.Len(err.(interface{ Unwrap() []error }).Unwrap(), 5)
+			}
+		},
+		"backoff specifications": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for spec, expected := range map[string][]time.Duration{
+				"none":                      {0, 0, 0},
+				"constant:1s":               {time.Second, time.Second, time.Second},
+				"exponential:1s":            {time.Second, 2 * time.Second, 4 * time.Second},
+				"exponential:1s:3":          {time.Second, 3 * time.Second, 9 * time.Second},
+				"exponential:1s:1.5:2s":     {time.Second, 1500 * time.Millisecond, 2 * time.Second},
+				"exponential:1s:1":          {time.Second, time.Second, time.Second},
+				"exponential:0s:2":          {0, 0, 0},
+				"exponential:100ms:2:250ms": {100 * time.Millisecond, 200 * time.Millisecond, 250 * time.Millisecond},
+			} {
+				boff, err := parseBackoff(spec)
+				if !as.NoError(err, spec) {
+					continue
+				}
+				for n, d := range expected {
+					as.Equal(d, boff(uint64(n+1)), spec)
+				}
+			}
+
+			for _, spec := range []string{
+				"", "none:1s", "constant", "constant:1s:2s", "constant:x",
+				"exponential", "exponential:1s:x", "exponential:1s:0.5",
+				"exponential:1s:NaN", "exponential:1s:+Inf",
+				"exponential:1s:2:x", "exponential:1s:2:3s:4s",
+			} {
+				_, err := parseBackoff(spec)
+				as.Error(err, spec)
+			}
+		},
+		"single invalid field": func(t *testing.T) {
+			as := newAssertions(t)
+
+			_, err := OptionsFromConfig(Config{Timeout: "x"})
+			as.Equal(`invalid config timeout "x": time: invalid duration "x"`,
+				err.Error())
+		},
+		"environment": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for key, value := range map[string]string{
+				"MYJOB_NAME":                   "sync",
+				"MYJOB_LABELS":                 "team=infra,tier=",
+				"MYJOB_RECUR":                  "true",
+				"MYJOB_PERIOD":                 "30s",
+				"MYJOB_PERIOD_JITTER":          "0.2",
+				"MYJOB_FIXED_RATE":             "1",
+				"MYJOB_CRON":                   "0 3 * * *",
+				"MYJOB_INITIAL_DELAY":          "1s",
+				"MYJOB_TIMEOUT":                "10s",
+				"MYJOB_TOTAL_TIMEOUT":          "1h",
+				"MYJOB_STOP_GRACE":             "5s",
+				"MYJOB_RUN_LIMIT":              "7",
+				"MYJOB_ATTEMPT_LIMIT":          "9",
+				"MYJOB_RESTART":                "true",
+				"MYJOB_RESTART_LIMIT":          "5",
+				"MYJOB_RESET_ON_SUCCESS":       "true",
+				"MYJOB_RESET_AFTER_STABLE":     "1m",
+				"MYJOB_BACKOFF":                "exp:1s:2:1m",
+				"MYJOB_RECOVER":                "true",
+				"MYJOB_START_SPLAY":            "2s",
+				"MYJOB_CONCURRENCY":            "3",
+				"MYJOB_TIMEOUT_RESTART_LIMIT":  "2",
+				"MYJOB_TIMEOUT_BACKOFF":        "none",
+				"MYJOB_SLOW_RUN_THRESHOLD":     "8s",
+				"MYJOB_UNRESPONSIVE_GRACE":     "3s",
+				"MYJOB_ABANDON_AFTER":          "4s",
+				"MYJOB_DEDUPE_ERRORS":          "true",
+				"MYJOB_FAILURE_RATE_THRESHOLD": "0.25",
+				"MYJOB_FAILURE_RATE_WINDOW":    "5m",
+				"MYJOB_FAILURE_RATE_MIN_RUNS":  "4",
+				"OTHER_TIMEOUT":                "x",
+			} {
+				t.Setenv(key, value)
+			}
+
+			fromEnv, err := OptionsFromEnv("MYJOB")
+			if !as.NoError(err) {
+				return
+			}
+			actual := apply(t, new(options), fromEnv)
+			expected := apply(t, new(options), []Option{
+				WithName("sync"),
+				WithLabels(map[string]string{"team": "infra", "tier": ""}),
+				Recur(true), Period(30 * time.Second), PeriodJitter(0.2),
+				FixedRate(true), Cron(MustParseCron("0 3 * * *", nil)),
+				InitialDelay(time.Second), Timeout(10 * time.Second),
+				TotalTimeout(time.Hour), StopGrace(5 * time.Second),
+				RunLimit(7), AttemptLimit(9), Restart(true), RestartLimit(5, nil),
+				ResetOnSuccess(true), ResetAfterStable(time.Minute), Recover(true),
+				StartSplay(2 * time.Second), Concurrency(3),
+				TimeoutRestartLimit(2, nil), SlowRunThreshold(8 * time.Second),
+				UnresponsiveGrace(3 * time.Second), AbandonAfter(4 * time.Second),
+				DedupeErrors(true), FailureRateAlarm(0.25, 5*time.Minute, 4),
+			})
+
+			backoff, timeoutBackoff := actual.restartable.backoff,
+				actual.restartable.timeoutBackoff
+			actual.restartable.backoff, expected.restartable.backoff = nil, nil
+			actual.restartable.timeoutBackoff, expected.restartable.timeoutBackoff = nil, nil
+			as.Equal(expected, actual)
+			as.Zero(timeoutBackoff(3))
+			as.Equal(time.Second, backoff(1))
+			as.Equal(4*time.Second, backoff(3))
+			as.Equal(time.Minute, backoff(10))
+
+			prefixed, err := OptionsFromEnv("MYJOB_")
+			as.NoError(err)
+			as.Len(prefixed, len(fromEnv))
+		},
+		"empty environment": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts, err := OptionsFromEnv("UNSET_JOB")
+			as.NoError(err)
+			as.Empty(opts)
+
+			t.Setenv("PERIOD", "1s")
+			opts, err = OptionsFromEnv("")
+			as.NoError(err)
+			as.Equal(&options{recurring: recurrenceOptions{period: time.Second}},
+				apply(t, new(options), opts))
+		},
+		"invalid environment": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for key, value := range map[string]string{
+				"BADJOB_LABELS":                 "team",
+				"BADJOB_RECUR":                  "yes please",
+				"BADJOB_PERIOD":                 "soon",
+				"BADJOB_PERIOD_JITTER":          "lots",
+				"BADJOB_RESTART_LIMIT":          "-1",
+				"BADJOB_CONCURRENCY":            "many",
+				"BADJOB_FAILURE_RATE_THRESHOLD": "half",
+				"BADJOB_TIMEOUT":                "1s",
+			} {
+				t.Setenv(key, value)
+			}
+
+			opts, err := OptionsFromEnv("BADJOB")
+			as.Nil(opts)
+			if !as.Error(err) {
+				return
+			}
+			for _, msg := range []string{
+				`invalid environment variable BADJOB_LABELS "team": invalid label "team"`,
+				`invalid environment variable BADJOB_RECUR "yes please"`,
+				`invalid environment variable BADJOB_PERIOD "soon"`,
+				`invalid environment variable BADJOB_PERIOD_JITTER "lots"`,
+				`invalid environment variable BADJOB_RESTART_LIMIT "-1"`,
+				`invalid environment variable BADJOB_CONCURRENCY "many"`,
+				`invalid environment variable BADJOB_FAILURE_RATE_THRESHOLD "half"`,
+			} {
+				as.Contains(err.Error(), msg)
+			}
+			as.NotContains(err.Error(), "TIMEOUT")
+			as.Len(err.(interface{ Unwrap() []error }).Unwrap(), 7)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
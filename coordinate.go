@@ -0,0 +1,159 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotLeader is the reason an execution of a runnable is skipped
+// when another process holds its lock (see Coordinate).
+var ErrNotLeader = errors.New("not leader")
+
+// Locker coordinates the executions of a runnable among processes,
+// such as a fleet of identical ones running the same recurring job.
+type Locker interface {
+	// TryLock acquires the lock with the provided key until the provided
+	// time, unless another process holds it as of now (the current time
+	// of the clock of the runnable), in which case it returns false.
+	// The lock is not released, but expires at that time.
+	TryLock(ctx context.Context, key string, now, until time.Time) (bool, error)
+}
+
+// coordinationOptions coordinates the executions of a runnable
+// among processes.
+type coordinationOptions struct {
+	locker Locker
+	key    string
+	hold   time.Duration
+}
+
+// Coordinate coordinates each execution of a runnable among processes
+// through the provided locker (default: nil, not coordinated),
+// so that only the process acquiring the lock with the provided key
+// executes it, holding the lock for the provided duration
+// after the time the execution was scheduled for
+// (e.g. slightly less than the period of the runnable).
+//
+// Executions of the other processes are skipped (see RunSkipped),
+// with ErrNotLeader as reason, and rescheduled as successful ones would be.
+// Failing to acquire the lock fails the execution with the error
+// of the locker, subject to the restart options of the runnable.
+func Coordinate(l Locker, key string, hold time.Duration) Option {
+	return func(o *options) *options {
+		o.coordination = coordinationOptions{locker: l, key: key, hold: hold}
+		return o
+	}
+}
+
+// lead acquires the lock of a runnable (if any) for an upcoming execution,
+// provided with its context and attempt.
+func (o *options) lead(ctx context.Context, attempt Attempt) (bool, error) {
+	if o == nil || o.coordination.locker == nil {
+		return true, nil
+	}
+
+	c := o.coordination
+	return c.locker.TryLock(ctx, c.key, o.clock().Now(),
+		attempt.ScheduledAt.Add(c.hold))
+}
+
+// KV is a key-value store supporting atomic conditional updates,
+// which backs a KVLocker.
+type KV interface {
+	// Get returns the value of the provided key, or false if it is unset.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// CompareAndSwap sets the provided key to the provided value,
+	// provided that its current value is the expected one
+	// (or that it is unset, if nil), and indicates whether it did.
+	CompareAndSwap(ctx context.Context, key string, expected, value []byte) (bool, error)
+}
+
+// KVLocker is a locker backed by a key-value store,
+// storing the owner of each lock along with the time it expires at.
+//
+// Its processes should have reasonably synchronized clocks,
+// since the expiration of locks is determined by them.
+type KVLocker struct {
+	kv    KV
+	owner string
+}
+
+// NewKVLocker creates a new locker backed by the provided key-value store,
+// acquiring locks as the provided owner, which should be unique
+// for each process.
+func NewKVLocker(kv KV, owner string) *KVLocker {
+	return &KVLocker{kv: kv, owner: owner}
+}
+
+// lease is the value of a lock in the key-value store of a KVLocker.
+type lease struct {
+	Owner string    `json:"owner"`
+	Until time.Time `json:"until"`
+}
+
+// TryLock acquires the lock with the provided key until the provided time,
+// unless another owner holds it as of now.
+// A lock held by the owner of the locker is extended.
+func (l *KVLocker) TryLock(ctx context.Context, key string,
+	now, until time.Time) (bool, error) {
+
+	current, ok, err := l.kv.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		var held lease
+		if err := json.Unmarshal(current, &held); err != nil {
+			return false, err
+		}
+		if held.Owner != l.owner && held.Until.After(now) {
+			return false, nil
+		}
+	}
+
+	// Encoding the lease cannot fail.
+	value, _ := json.Marshal(lease{Owner: l.owner, Until: until})
+	return l.kv.CompareAndSwap(ctx, key, current, value)
+}
+
+// MemoryKV is a key-value store kept in memory,
+// coordinating the instances of a single process (e.g. in tests).
+//
+// The zero value is ready to use.
+type MemoryKV struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// Get returns the value of the provided key, or false if it is unset.
+func (kv *MemoryKV) Get(_ context.Context, key string) ([]byte, bool, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	value, ok := kv.values[key]
+	return value, ok, nil
+}
+
+// CompareAndSwap sets the provided key to the provided value,
+// provided that its current value is the expected one
+// (or that it is unset, if nil), and indicates whether it did.
+func (kv *MemoryKV) CompareAndSwap(_ context.Context, key string,
+	expected, value []byte) (bool, error) {
+
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	current, ok := kv.values[key]
+	if ok != (expected != nil) || !bytes.Equal(current, expected) {
+		return false, nil
+	}
+	if kv.values == nil {
+		kv.values = make(map[string][]byte)
+	}
+	kv.values[key] = value
+	return true, nil
+}
@@ -0,0 +1,119 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// failingKV is a key-value store failing to get values.
+type failingKV struct {
+	MemoryKV
+	err error
+}
+
+func (kv *failingKV) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, kv.err
+}
+
+func testCoordinate(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"single leader": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var kv MemoryKV
+			runs := map[string]int{}
+			job := func(owner string) *Instance {
+				inst := New(func(context.Context) error {
+					runs[owner]++
+					return nil
+				}, Coordinate(NewKVLocker(&kv, owner), "job", time.Hour))
+				return &inst
+			}
+
+			as.Empty(waitErrors(job("first").Run(context.TODO())))
+			second := job("second")
+			as.Equal([]Event{
+				RunSkipped{Reason: ErrNotLeader},
+				Terminated{},
+			}, waitEvents(second.Events(context.TODO())))
+			as.Equal(Completed, second.TerminationReason())
+			as.Equal(map[string]int{"first": 1}, runs)
+
+			// The lock of the owner is extended.
+			as.Empty(waitErrors(job("first").Run(context.TODO())))
+			as.Equal(map[string]int{"first": 2}, runs)
+		},
+		"expired lock": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var kv MemoryKV
+			runs := 0
+			for _, owner := range []string{"first", "second"} {
+				inst := New(func(context.Context) error {
+					runs++
+					return nil
+				}, Coordinate(NewKVLocker(&kv, owner), "job", -time.Second))
+				as.Empty(waitErrors(inst.Run(context.TODO())))
+			}
+			as.Equal(2, runs)
+		},
+		"failed lock": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, Coordinate(NewKVLocker(&failingKV{err: testError(-1)}, "owner"), "job", time.Hour))
+
+			as.Equal([]Event{
+				RunFailed{Err: testError(-1)},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.Zero(runs)
+			as.Equal(Failed, inst.TerminationReason())
+		},
+		"locker": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var kv MemoryKV
+			l := NewKVLocker(&kv, "owner")
+
+			swapped, err := kv.CompareAndSwap(context.TODO(), "job", []byte("{}"), []byte("{"))
+			as.NoError(err)
+			as.False(swapped)
+			swapped, err = kv.CompareAndSwap(context.TODO(), "job", nil, []byte("{"))
+			as.NoError(err)
+			as.True(swapped)
+
+			locked, err := l.TryLock(context.TODO(), "job", time.Now(), time.Now())
+			as.Error(err)
+			as.False(locked)
+
+			// Leases expire as of the provided time.
+			now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			other := NewKVLocker(&kv, "other")
+			locked, err = other.TryLock(context.TODO(), "lease", now, now.Add(time.Hour))
+			as.NoError(err)
+			as.True(locked)
+			locked, err = l.TryLock(context.TODO(), "lease", now.Add(time.Minute),
+				now.Add(2*time.Hour))
+			as.NoError(err)
+			as.False(locked)
+			locked, err = l.TryLock(context.TODO(), "lease", now.Add(time.Hour),
+				now.Add(2*time.Hour))
+			as.NoError(err)
+			as.True(locked)
+
+			var opts *options
+			locked, err = opts.lead(context.TODO(), Attempt{})
+			as.NoError(err)
+			as.True(locked)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
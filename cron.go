@@ -0,0 +1,272 @@
+package run
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule represents a parsed cron expression,
+// determining the wall-clock times a recurring runnable executes at.
+// It implements Schedule.
+type CronSchedule struct {
+	expr string
+	loc  *time.Location
+
+	minute, hour, dom, month, dow bitset
+	// domAny and dowAny indicate whether the respective
+	// day fields are unrestricted, which affects day matching.
+	domAny, dowAny bool
+}
+
+// bitset is a set of small non-negative integers.
+type bitset uint64
+
+func (b bitset) has(n int) bool {
+	return b&(1<<uint(n)) != 0
+}
+
+// cronField describes the valid values of a cron expression field.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week accepts 7 as an alias of Sunday.
+	cronDow = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronDescriptors are the supported shorthand cron expressions.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSearchYears bounds the search for the next matching time
+// of expressions that rarely (or never) match, e.g. "0 0 30 2 *".
+const cronSearchYears = 5
+
+// ParseCron parses a standard cron expression with five fields
+// (minute, hour, day of month, month, day of week),
+// or one of the descriptors @yearly, @annually, @monthly,
+// @weekly, @daily, @midnight and @hourly.
+//
+// Fields support wildcards (*), ranges (1-5), steps (*/15, 0-30/5),
+// lists (1,15,30), as well as month and weekday names (JAN, MON).
+// As in most cron implementations, if both day fields are restricted,
+// a time matches if either of them does.
+//
+// Times are evaluated in the provided location (the local one, if nil),
+// which can be overridden by prefixing the expression
+// with CRON_TZ=<location> (e.g. "CRON_TZ=Europe/Athens 0 3 * * *").
+func ParseCron(expr string, loc *time.Location) (*CronSchedule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		idx := strings.IndexAny(spec, " \t")
+		if idx == -1 {
+			return nil, cronError(expr, "missing fields after time zone")
+		}
+
+		name := spec[strings.Index(spec, "=")+1 : idx]
+		tz, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, cronError(expr, fmt.Sprintf("invalid time zone %q", name))
+		}
+		loc, spec = tz, strings.TrimSpace(spec[idx:])
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		desc, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, cronError(expr, fmt.Sprintf("unknown descriptor %q", spec))
+		}
+		spec = desc
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, cronError(expr,
+			fmt.Sprintf("expected 5 fields, found %d", len(fields)))
+	}
+
+	sched := &CronSchedule{
+		expr:   expr,
+		loc:    loc,
+		domAny: fields[2] == "*" || fields[2] == "?",
+		dowAny: fields[4] == "*" || fields[4] == "?",
+	}
+	targets := []*bitset{
+		&sched.minute, &sched.hour, &sched.dom, &sched.month, &sched.dow,
+	}
+	for idx, f := range []cronField{cronMinute, cronHour, cronDom, cronMonth, cronDow} {
+		bits, err := f.parse(fields[idx])
+		if err != nil {
+			return nil, cronError(expr, err.Error())
+		}
+		*targets[idx] = bits
+	}
+	// Fold Sunday alias.
+	if sched.dow.has(7) {
+		sched.dow = (sched.dow | 1) &^ (1 << 7)
+	}
+
+	return sched, nil
+}
+
+// MustParseCron is like ParseCron, but panics if the expression is invalid.
+func MustParseCron(expr string, loc *time.Location) *CronSchedule {
+	sched, err := ParseCron(expr, loc)
+	if err != nil {
+		panic(err)
+	}
+	return sched
+}
+
+func cronError(expr, reason string) error {
+	return fmt.Errorf("invalid cron expression %q: %s", expr, reason)
+}
+
+// parse parses a cron expression field into the set of its values.
+func (f cronField) parse(field string) (bitset, error) {
+	var bits bitset
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepStr, f.name)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case rng == "*" || rng == "?":
+			lo, hi = f.min, f.max
+		case strings.Contains(rng, "-"):
+			loStr, hiStr, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(loStr); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(hiStr); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q in %s field", rng, f.name)
+			}
+		default:
+			var err error
+			if lo, err = f.value(rng); err != nil {
+				return 0, err
+			}
+			hi = lo
+			// A single value with step denotes a range up to the maximum.
+			if hasStep {
+				hi = f.max
+			}
+		}
+
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << uint(n)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single value (number or name) of a cron expression field.
+func (f cronField) value(s string) (int, error) {
+	if n, ok := f.names[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, f.name)
+	}
+	return n, nil
+}
+
+// String returns the expression a schedule was parsed from.
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// Location returns the location the times of a schedule are evaluated in.
+func (c *CronSchedule) Location() *time.Location {
+	return c.loc
+}
+
+// Next returns the earliest time matching a schedule after the provided one,
+// or the zero time if none is found within the next few years.
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	t := after.In(c.loc)
+	// Start from the beginning of the following minute.
+	t = time.Date(t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), 0, 0, c.loc).Add(time.Minute)
+
+	limit := t.Year() + cronSearchYears
+	for t.Year() <= limit {
+		if !c.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.hour.has(t.Hour()) {
+			t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute)
+			continue
+		}
+		if !c.minute.has(t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches indicates whether the day of the provided time
+// matches the day fields of a schedule.
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom.has(t.Day())
+	dowMatch := c.dow.has(int(t.Weekday()))
+
+	if c.domAny || c.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Cron sets a cron schedule for a recurring runnable,
+// and is equivalent to WithSchedule.
+func Cron(sched *CronSchedule) Option {
+	if sched == nil {
+		return WithSchedule(nil)
+	}
+	return WithSchedule(sched)
+}
@@ -0,0 +1,173 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testCron(t *testing.T) {
+	athens, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	date := func(loc *time.Location, year int, month time.Month,
+		day, hour, min int) time.Time {
+
+		return time.Date(year, month, day, hour, min, 0, 0, loc)
+	}
+
+	subtests := map[string]func(*testing.T){
+		"next matching times": func(t *testing.T) {
+			cases := []struct {
+				expr     string
+				after    time.Time
+				expected time.Time
+			}{
+				{"* * * * *", date(time.UTC, 2022, 1, 1, 0, 0).Add(30 * time.Second),
+					date(time.UTC, 2022, 1, 1, 0, 1)},
+				{"0 3 * * *", date(time.UTC, 2022, 1, 1, 3, 0),
+					date(time.UTC, 2022, 1, 2, 3, 0)},
+				{"*/15 * * * *", date(time.UTC, 2022, 1, 1, 10, 16),
+					date(time.UTC, 2022, 1, 1, 10, 30)},
+				{"0-10/5 9-17 * * *", date(time.UTC, 2022, 1, 1, 17, 10),
+					date(time.UTC, 2022, 1, 2, 9, 0)},
+				{"30 12 1,15 * *", date(time.UTC, 2022, 1, 2, 0, 0),
+					date(time.UTC, 2022, 1, 15, 12, 30)},
+				{"0 0 * * MON-FRI", date(time.UTC, 2022, 1, 1, 0, 0),
+					date(time.UTC, 2022, 1, 3, 0, 0)},
+				{"0 0 * * 7", date(time.UTC, 2022, 1, 3, 0, 0),
+					date(time.UTC, 2022, 1, 9, 0, 0)},
+				{"0 0 13 * FRI", date(time.UTC, 2022, 1, 1, 0, 0),
+					date(time.UTC, 2022, 1, 7, 0, 0)},
+				{"0 0 29 feb *", date(time.UTC, 2022, 1, 1, 0, 0),
+					date(time.UTC, 2024, 2, 29, 0, 0)},
+				{"5/20 * * * *", date(time.UTC, 2022, 1, 1, 0, 6),
+					date(time.UTC, 2022, 1, 1, 0, 25)},
+				{"@monthly", date(time.UTC, 2022, 12, 5, 0, 0),
+					date(time.UTC, 2023, 1, 1, 0, 0)},
+				{"@hourly", date(time.UTC, 2022, 1, 1, 23, 59),
+					date(time.UTC, 2022, 1, 2, 0, 0)},
+				{"0 0 30 2 *", date(time.UTC, 2022, 1, 1, 0, 0),
+					time.Time{}},
+			}
+
+			as := newAssertions(t)
+			for _, tc := range cases {
+				sched, err := ParseCron(tc.expr, time.UTC)
+				if !as.NoError(err, tc.expr) {
+					continue
+				}
+				as.Equalf(tc.expected, sched.Next(tc.after), "next of %q", tc.expr)
+			}
+		},
+		"time zone": func(t *testing.T) {
+			as := newAssertions(t)
+
+			sched, err := ParseCron("0 3 * * *", athens)
+			as.NoError(err)
+			as.Equal(athens, sched.Location())
+			as.Equal(date(athens, 2022, 1, 1, 3, 0),
+				sched.Next(date(athens, 2022, 1, 1, 0, 0)))
+
+			sched, err = ParseCron("CRON_TZ=Europe/Athens 0 3 * * *", time.UTC)
+			as.NoError(err)
+			as.Equal("CRON_TZ=Europe/Athens 0 3 * * *", sched.String())
+			as.Equal(date(athens, 2022, 1, 1, 3, 0),
+				sched.Next(date(time.UTC, 2022, 1, 1, 0, 0)))
+
+			sched, err = ParseCron("* * * * *", nil)
+			as.NoError(err)
+			as.Equal(time.Local, sched.Location())
+		},
+		"daylight saving transition": func(t *testing.T) {
+			as := newAssertions(t)
+
+			// Clocks move from 03:00 to 04:00 on 2022-03-27 in Athens.
+			sched := MustParseCron("30 * * * *", athens)
+
+			as.Equal(date(athens, 2022, 3, 27, 4, 30),
+				sched.Next(date(athens, 2022, 3, 27, 2, 45)))
+		},
+		"invalid expressions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for _, expr := range []string{
+				"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *",
+				"* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *",
+				"*/x * * * *", "5-1 * * * *", "a-5 * * * *", "1-b * * * *",
+				"* * * foo *", "@never", "CRON_TZ=Mars/Base * * * * *",
+				"CRON_TZ=UTC",
+			} {
+				_, err := ParseCron(expr, time.UTC)
+				as.Errorf(err, "expression %q", expr)
+			}
+
+			as.Panics(func() {
+				MustParseCron("invalid", nil)
+			})
+		},
+		"Cron option": func(t *testing.T) {
+			as := newAssertions(t)
+
+			sched := MustParseCron("@daily", time.UTC)
+			opts := apply(t, new(options), []Option{Cron(sched)})
+			as.Equal(&options{
+				recurring: recurrenceOptions{
+					recur:    true,
+					schedule: sched,
+				},
+			}, opts)
+
+			opts = apply(t, opts, []Option{Cron(nil)})
+			as.Nil(opts.recurring.schedule)
+		},
+		"instance without matching times never executes": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, Cron(MustParseCron("0 0 31 4 *", time.UTC)))
+
+			as.Equal([]Event{Terminated{}}, waitEvents(inst.Events(context.TODO())))
+		},
+		"recurrence delays": func(t *testing.T) {
+			as := newAssertions(t)
+
+			now := date(time.UTC, 2022, 1, 1, 23, 0)
+			periodic := recurrenceOptions{recur: true, period: time.Minute}
+			scheduled := recurrenceOptions{
+				recur:    true,
+				schedule: MustParseCron("@daily", time.UTC),
+			}
+			never := recurrenceOptions{
+				recur:    true,
+				schedule: MustParseCron("0 0 31 4 *", time.UTC),
+			}
+
+			delay, ok := periodic.first(now)
+			as.Equal(time.Duration(0), delay)
+			as.True(ok)
+			delay, ok = periodic.next(now)
+			as.Equal(time.Minute, delay)
+			as.True(ok)
+
+			delay, ok = scheduled.first(now)
+			as.Equal(time.Hour, delay)
+			as.True(ok)
+			delay, ok = scheduled.next(now)
+			as.Equal(time.Hour, delay)
+			as.True(ok)
+
+			_, ok = never.first(now)
+			as.False(ok)
+			_, ok = never.next(now)
+			as.False(ok)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
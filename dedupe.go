@@ -0,0 +1,83 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RepeatedError reports the repetitions of an error coalesced
+// into it (see DedupeErrors).
+type RepeatedError struct {
+	// Err is the latest repetition of the error.
+	Err error
+	// Count is the number of repetitions,
+	// following the propagation of the error itself.
+	Count uint64
+}
+
+// Error satisfies error interface for RepeatedError.
+func (e RepeatedError) Error() string {
+	return fmt.Sprintf("%v (repeated %d times)", e.Err, e.Count)
+}
+
+// Unwrap returns the latest repetition of the error.
+func (e RepeatedError) Unwrap() error {
+	return e.Err
+}
+
+// DedupeErrors indicates whether identical consecutive errors of an instance
+// run with Run or RunBlocking are coalesced (default: false), e.g. so that
+// a flapping dependency does not flood the consumer of its errors.
+//
+// An error is propagated as usual, while its repetitions are counted
+// and propagated as a single RepeatedError once a different error
+// is propagated, or the instance terminates.
+// Errors are identical if their messages are, with errors wrapped
+// in a RunError (see WrapErrors) compared by the errors they wrap.
+func DedupeErrors(dedupe bool) Option {
+	return func(o *options) *options {
+		o.dedupeErrors = dedupe
+		return o
+	}
+}
+
+// dedupe returns a function propagating errors through the provided one,
+// coalescing identical consecutive errors if the appropriate option is set,
+// along with a function flushing the repetitions of the latest error.
+func (o *options) dedupe(propagate func(error)) (func(error), func()) {
+	if o == nil || !o.dedupeErrors {
+		return propagate, func() {}
+	}
+
+	var last error
+	var repeats uint64
+	flush := func() {
+		if repeats != 0 {
+			propagate(RepeatedError{Err: last, Count: repeats})
+		}
+		repeats = 0
+	}
+	return func(err error) {
+		switch {
+		case err == nil:
+			return
+		case last != nil && identity(err) == identity(last):
+			last = err
+			repeats++
+			return
+		}
+		flush()
+		last = err
+		propagate(err)
+	}, flush
+}
+
+// identity returns the message identifying an error
+// for the purpose of deduplication.
+func identity(err error) string {
+	var runErr RunError
+	if errors.As(err, &runErr) {
+		err = runErr.Err
+	}
+	return err.Error()
+}
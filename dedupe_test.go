@@ -0,0 +1,93 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func testDedupe(t *testing.T) {
+	// sequence returns a runnable failing with the provided messages in turn,
+	// and succeeding afterwards.
+	sequence := func(msgs ...string) Runnable {
+		runs := 0
+		return func(context.Context) error {
+			if runs++; runs <= len(msgs) {
+				return errors.New(msgs[runs-1])
+			}
+			return nil
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"coalesced": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(sequence("a", "a", "a", "b", "a", "a"),
+				Restart(true), DedupeErrors(true))
+			as.Equal([]error{
+				errors.New("a"),
+				RepeatedError{Err: errors.New("a"), Count: 2},
+				errors.New("b"),
+				errors.New("a"),
+				RepeatedError{Err: errors.New("a"), Count: 1},
+			}, waitErrors(inst.Run(context.TODO())))
+		},
+		"flushed before termination reason": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(sequence("a", "a", "a"), Restart(true),
+				RestartLimit(2, nil), DedupeErrors(true), ReportTermination(true))
+			as.Equal([]error{
+				errors.New("a"),
+				RepeatedError{Err: errors.New("a"), Count: 1},
+				RestartLimitExceeded,
+			}, waitErrors(inst.Run(context.TODO())))
+		},
+		"wrapped errors": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(sequence("a", "a"), Restart(true),
+				WrapErrors(true), DedupeErrors(true))
+			errs := waitErrors(inst.Run(context.TODO()))
+			if as.Len(errs, 2) {
+				var runErr RunError
+				as.ErrorAs(errs[0], &runErr)
+				as.Equal(uint64(1), runErr.Attempt)
+
+				var repeated RepeatedError
+				if as.ErrorAs(errs[1], &repeated) {
+					as.Equal(uint64(1), repeated.Count)
+					as.ErrorAs(repeated.Err, &runErr)
+					as.Equal(uint64(2), runErr.Attempt)
+				}
+			}
+		},
+		"RunBlocking": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var errs []error
+			inst := New(sequence("a", "a", "b"), Restart(true), DedupeErrors(true),
+				OnError(func(err error) {
+					errs = append(errs, err)
+				}))
+			as.NoError(inst.RunBlocking(context.TODO()))
+			as.Equal([]error{
+				errors.New("a"),
+				RepeatedError{Err: errors.New("a"), Count: 1},
+				errors.New("b"),
+			}, errs)
+		},
+		"RepeatedError": func(t *testing.T) {
+			as := newAssertions(t)
+
+			err := RepeatedError{Err: context.Canceled, Count: 3}
+			as.EqualError(err, "context canceled (repeated 3 times)")
+			as.ErrorIs(err, context.Canceled)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
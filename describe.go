@@ -0,0 +1,162 @@
+package run
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Describe returns a human-readable summary of the effective behavior
+// of an instance according to its options, e.g.
+//
+//	recurs every 30s after success, restarts up to 5 times
+//	with backoff growing from 1s to 1m, per-run timeout 10s,
+//	recovers panics
+//
+// prefixed by its name (if any), suitable for logging at startup. Its format is not stable,
+// and it should not be parsed.
+//
+// The backoff is described by sampling the backoff function.
+func (i *Instance) Describe() string {
+	return i.options().describe()
+}
+
+// describe returns a human-readable summary of the behavior of a runnable
+// according to its options (see Instance.Describe).
+func (o *options) describe() string {
+	if o == nil {
+		o = new(options)
+	}
+
+	var parts []string
+	add := func(format string, args ...interface{}) {
+		parts = append(parts, fmt.Sprintf(format, args...))
+	}
+
+	if d := o.starting.delay; d > 0 {
+		add("starts after %v", d)
+	}
+
+	rOpts := o.recurring
+	switch {
+	case rOpts.recur && rOpts.schedule != nil:
+		if s, ok := rOpts.schedule.(fmt.Stringer); ok {
+			add("recurs on schedule %q", s.String())
+		} else {
+			add("recurs on a schedule")
+		}
+	case rOpts.recur && rOpts.fixedRate && rOpts.period > 0:
+		add("recurs every %v at a fixed rate", rOpts.period)
+	case rOpts.recur && rOpts.period > 0:
+		add("recurs every %v after success", rOpts.period)
+	case rOpts.recur:
+		add("recurs immediately after success")
+	case o.awaitsTriggers():
+		add("runs when triggered")
+	default:
+		add("runs once")
+	}
+	if rOpts.recur && rOpts.jitter > 0 {
+		add("jittered by up to %v%%", math.Round(rOpts.jitter*100))
+	}
+	if n := o.constrained.runLimit; n != 0 {
+		add("up to %d successful runs", n)
+	}
+	if n := o.constrained.attemptLimit; n != 0 {
+		add("up to %d attempts", n)
+	}
+
+	if restart := o.restartable; restart.restartOnError {
+		limit := "indefinitely"
+		if restart.restartLimit != 0 {
+			limit = fmt.Sprintf("up to %d times", restart.restartLimit)
+		}
+		add("restarts %s %s", limit, describeBackoff(restart.delay))
+		// Restarted runnables reset their failures on success
+		// regardless of ResetOnSuccess (see Instance.count).
+		if d := restart.stableAfter; d > 0 {
+			add("resets failures on success and after running for %v", d)
+		} else {
+			add("resets failures on success")
+		}
+		switch {
+		case restart.fatalTimeout:
+			add("does not restart timed out runs")
+		case restart.timeoutBackoff != nil:
+			limit := "indefinitely"
+			if restart.timeoutLimit != 0 {
+				limit = fmt.Sprintf("up to %d times", restart.timeoutLimit)
+			}
+			add("restarts timed out runs %s %s", limit,
+				describeBackoff(restart.timeoutBackoff))
+		}
+	}
+
+	switch cOpts := o.constrained; {
+	case cOpts.timeoutFn != nil:
+		add("per-run timeout varying by attempt")
+	case cOpts.timeout > 0:
+		add("per-run timeout %v", cOpts.timeout)
+	}
+	if d := o.totalTimeout; d > 0 {
+		add("total timeout %v", d)
+	}
+	if n := o.workers(); n > 1 {
+		add("%d concurrent copies", n)
+	}
+	if d := o.slowAfter; d > 0 {
+		add("reports runs slower than %v", d)
+	}
+	if d := o.unresponsiveAfter; d > 0 {
+		add("reports runs unresponsive %v after cancellation", d)
+	}
+	if d := o.abandonAfter; d > 0 {
+		add("abandons runs %v after cancellation", d)
+	}
+	if d := o.grace(); d > 0 {
+		add("stop grace %v", d)
+	}
+	// Converted panics are no longer recovered from (see RecoverWith).
+	switch {
+	case o.recoverable.converter != nil:
+		add("converts panics into errors")
+	case o.calm():
+		add("recovers panics")
+	}
+	if alarm := o.alarm; alarm != (alarmOptions{}) {
+		add("alarms above %v%% failures within %v (at least %d runs)",
+			math.Round(alarm.threshold*100), alarm.window, alarm.minRuns)
+	}
+	if o.dedupeErrors {
+		add("deduplicates consecutive errors")
+	}
+	switch {
+	case o.parent != nil && o.bubble:
+		add("stops with its parent, propagating errors to it")
+	case o.parent != nil:
+		add("stops with its parent")
+	}
+
+	desc := strings.Join(parts, ", ")
+	if o.name != "" {
+		desc = o.name + ": " + desc
+	}
+	return desc
+}
+
+// describeBackoff returns a human-readable summary of a backoff,
+// sampling the provided function.
+func describeBackoff(delay func(failedRuns uint64) time.Duration) string {
+	first, last := delay(1), delay(1<<16)
+	switch {
+	case first == last && first == 0:
+		return "without backoff"
+	case first == last:
+		return fmt.Sprintf("with backoff %v", first)
+	case last >= math.MaxInt64/2:
+		// Unbounded backoff periods grow until they can no longer double.
+		return fmt.Sprintf("with backoff growing from %v", first)
+	}
+	return fmt.Sprintf("with backoff growing from %v to %v", first, last)
+}
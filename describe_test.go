@@ -0,0 +1,104 @@
+package run
+
+import (
+	"testing"
+	"time"
+)
+
+func testDescribe(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"descriptions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for expected, opts := range map[string][]Option{
+				"runs once": nil,
+				"recurs every 30s after success, " +
+					"restarts up to 5 times with backoff growing from 1s to 1m0s, " +
+					"resets failures on success, per-run timeout 10s, recovers panics": {
+					Recur(true), Period(30 * time.Second),
+					Restart(true), RestartLimit(5, ExponentialBackoff(time.Second, time.Minute)),
+					Timeout(10 * time.Second), Recover(true),
+				},
+				"sync: starts after 5s, recurs every 1m0s at a fixed rate, " +
+					"jittered by up to 10%, up to 3 successful runs, up to 7 attempts, " +
+					"total timeout 1h0m0s, 4 concurrent copies": {
+					WithName("sync"), InitialDelay(5 * time.Second),
+					Recur(true), Period(time.Minute), FixedRate(true), PeriodJitter(0.1),
+					RunLimit(3), AttemptLimit(7), TotalTimeout(time.Hour), Concurrency(4),
+				},
+				`recurs on schedule "0 3 * * *"`: {
+					Recur(true), Cron(MustParseCron("0 3 * * *", time.UTC)),
+				},
+				"recurs on a schedule": {
+					Recur(true), WithSchedule(ScheduleFunc(func(now time.Time) time.Time {
+						return now
+					})),
+				},
+				"recurs immediately after success, " +
+					"restarts indefinitely without backoff, resets failures on success": {
+					Recur(true), Restart(true), ResetOnSuccess(true),
+				},
+				"runs when triggered, restarts indefinitely with backoff 1s, " +
+					"resets failures on success and after running for 1m0s, " +
+					"per-run timeout varying by attempt": {
+					Triggers(make(chan struct{})), Restart(true),
+					Backoff(ConstantBackoff(time.Second)), ResetAfterStable(time.Minute),
+					TimeoutFn(func(uint64) time.Duration { return time.Second }),
+				},
+				"runs once, restarts indefinitely with backoff growing from 1s, " +
+					"resets failures on success": {
+					Restart(true), Backoff(ExponentialBackoff(time.Second, 0)),
+				},
+				"runs once, restarts indefinitely without backoff, " +
+					"resets failures on success, " +
+					"restarts timed out runs up to 3 times with backoff 1m0s, " +
+					"per-run timeout 10s, reports runs slower than 5s, " +
+					"reports runs unresponsive 1s after cancellation, " +
+					"abandons runs 2s after cancellation, stop grace 3s, " +
+					"converts panics into errors": {
+					Restart(true), TimeoutRestartLimit(3, ConstantBackoff(time.Minute)),
+					Timeout(10 * time.Second), SlowRunThreshold(5 * time.Second),
+					UnresponsiveGrace(time.Second), AbandonAfter(2 * time.Second),
+					StopGrace(3 * time.Second), Recover(true),
+					RecoverWith(func(interface{}, []byte) error { return nil }),
+				},
+				"runs once, restarts indefinitely without backoff, " +
+					"resets failures on success, does not restart timed out runs, " +
+					"alarms above 50% failures within 10m0s (at least 5 runs), " +
+					"deduplicates consecutive errors, " +
+					"stops with its parent, propagating errors to it": {
+					Restart(true), RestartOnTimeout(false),
+					TimeoutRestartLimit(0, nil),
+					FailureRateAlarm(0.5, 10*time.Minute, 5), DedupeErrors(true),
+					ChildOf(new(Instance)), BubbleErrors(true),
+				},
+				"runs once, restarts indefinitely without backoff, " +
+					"resets failures on success, " +
+					"restarts timed out runs indefinitely without backoff, " +
+					"stops with its parent": {
+					Restart(true), TimeoutRestartLimit(0, nil), ChildOf(new(Instance)),
+				},
+			} {
+				inst := New(nil, opts...)
+				as.Equal(expected, inst.Describe())
+			}
+		},
+		"zero instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var inst Instance
+			as.Equal("runs once", inst.Describe())
+		},
+		"updated instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(nil, Recur(true), Period(time.Minute))
+			as.NoError(inst.Update(Period(time.Second)))
+			as.Equal("recurs every 1s after success", inst.Describe())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,63 @@
+package run
+
+import "context"
+
+// DetachValues indicates whether the executions of a runnable
+// inherit the values of the context of their instance, but not its
+// cancellation or deadline (default: false).
+//
+// An execution in progress when the context of its instance is cancelled
+// runs to completion (it is neither interrupted nor abandoned),
+// after which the instance terminates.
+// Executions are still cancelled when the instance is stopped,
+// and subject to their timeout.
+func DetachValues(detach bool) Option {
+	return func(o *options) *options {
+		o.detached = detach
+		return o
+	}
+}
+
+// detach returns the context the executions of an instance derive from,
+// detached from the cancellation of the provided context of the instance
+// (but cancelled once it is stopped) if the appropriate option is set,
+// along with a function releasing its resources.
+func (i *Instance) detach(ctx context.Context) (context.Context, func()) {
+	if i.options() == nil || !i.options().detached {
+		return ctx, func() {}
+	}
+
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	stopping, done := i.stopping(), make(chan struct{})
+	go func() {
+		select {
+		case <-stopping:
+			cancel(ErrStopped)
+		case <-done:
+		}
+	}()
+	return ctx, func() {
+		close(done)
+		cancel(nil)
+	}
+}
+
+// stopping returns a channel closed once an instance is stopped.
+func (i *Instance) stopping() <-chan struct{} {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.initStopping()
+	return i.stopCh
+}
+
+// initStopping creates the stopping channel of an instance (if needed).
+// The lock of the instance should be held.
+func (i *Instance) initStopping() {
+	if i.stopCh == nil {
+		i.stopCh = make(chan struct{})
+		if i.stopped {
+			close(i.stopCh)
+		}
+	}
+}
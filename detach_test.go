@@ -0,0 +1,71 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testDetach(t *testing.T) {
+	type key struct{}
+
+	subtests := map[string]func(*testing.T){
+		"parent cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			var runs int
+			inst := New(func(ctx context.Context) error {
+				runs++
+				as.Equal("value", ctx.Value(key{}))
+				Ready(ctx)
+				<-release
+				return ctx.Err()
+			}, DetachValues(true), AwaitReady(true), Recur(true))
+
+			ctx, cancel := context.WithCancelCause(
+				context.WithValue(context.TODO(), key{}, "value"))
+			errCh := inst.Run(ctx)
+			as.NoError(inst.WaitReady(context.TODO()))
+			cancel(testError(1))
+			close(release)
+
+			// The execution in progress completes,
+			// after which the instance terminates with the cause.
+			as.Equal([]error{testError(1)}, waitErrors(errCh))
+			as.Equal(1, runs)
+			as.Equal(uint64(0), inst.Stats().FailedRuns)
+		},
+		"stop": func(t *testing.T) {
+			as := newAssertions(t)
+
+			causes := make(chan error, 1)
+			inst := New(func(ctx context.Context) error {
+				Ready(ctx)
+				<-ctx.Done()
+				causes <- context.Cause(ctx)
+				return ctx.Err()
+			}, DetachValues(true), AwaitReady(true))
+
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			inst.Stop()
+			as.Equal([]error{context.Canceled}, waitErrors(errCh))
+			as.Equal(ErrStopped, <-causes)
+		},
+		"stopped instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, DetachValues(true))
+			inst.Stop()
+
+			_, ok := <-inst.stopping()
+			as.False(ok)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,68 @@
+package run
+
+import (
+	"context"
+	"sync"
+)
+
+// Do executes a runnable with the provided options on the caller's goroutine,
+// blocking until it terminates, and returns its final error:
+// nil if its last execution was successful,
+// the error of its last failed execution otherwise,
+// or the reason of its termination (context error or recovered panic).
+func Do(ctx context.Context, r Runnable, opts ...Option) error {
+	inst := New(r, opts...)
+
+	var out outcome
+	inst.execute(ctx, out.observe)
+
+	return out.err
+}
+
+// outcome tracks the final error of an instance through its events:
+// nil if its last execution was successful,
+// the error of its last failed execution otherwise,
+// or the reason of its termination (if any).
+type outcome struct {
+	err error
+}
+
+func (o *outcome) observe(ev Event) {
+	switch e := ev.(type) {
+	case RunSucceeded:
+		o.err = nil
+	case RunFailed:
+		o.err = e.Err
+	case RunAbandoned:
+		o.err = ErrRunAbandoned
+	case Terminated:
+		if e.Reason != nil {
+			o.err = e.Reason
+		}
+	}
+}
+
+// DoValue executes a runnable producing a result similarly to Do,
+// and returns the result of its last successful execution along with its final error.
+func DoValue[T any](ctx context.Context, r TypedRunnable[T], opts ...Option) (T, error) {
+	var (
+		mu  sync.Mutex
+		res T
+	)
+
+	var wrapped Runnable
+	if r != nil {
+		wrapped = func(ctx context.Context) error {
+			v, err := r(ctx)
+			if err == nil {
+				mu.Lock()
+				res = v
+				mu.Unlock()
+			}
+			return err
+		}
+	}
+
+	err := Do(ctx, wrapped, opts...)
+	return res, err
+}
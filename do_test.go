@@ -0,0 +1,110 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testDo(t *testing.T) {
+	// failing returns a runnable failing the first n executions.
+	failing := func(n int) (Runnable, *int) {
+		calls := 0
+		return func(context.Context) error {
+			calls++
+			if calls <= n {
+				return testError(calls)
+			}
+			return nil
+		}, &calls
+	}
+
+	subtests := map[string]func(*testing.T){
+		"stable execution resets backoff": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			var counts []uint64
+			err := Do(context.TODO(), func(context.Context) error {
+				calls++
+				if calls == 3 {
+					// Stable for a while before failing.
+					time.Sleep(2 * testTimeDelta)
+				}
+				if calls < 5 {
+					return testError(calls)
+				}
+				return nil
+			}, Restart(true), RestartLimit(3, func(count uint64) time.Duration {
+				counts = append(counts, count)
+				return 0
+			}), ResetAfterStable(testTimeDelta))
+
+			as.NoError(err)
+			as.Equal([]uint64{1, 2, 1, 2}, counts)
+		},
+		"retries until success": func(t *testing.T) {
+			as := newAssertions(t)
+
+			r, calls := failing(2)
+			err := Do(context.TODO(), r, Restart(true), RestartLimit(5, nil))
+
+			as.NoError(err)
+			as.Equal(3, *calls)
+		},
+		"returns last error after restart limit": func(t *testing.T) {
+			as := newAssertions(t)
+
+			r, calls := failing(5)
+			err := Do(context.TODO(), r, Restart(true), RestartLimit(3, nil))
+
+			as.Equal(testError(3), err)
+			as.Equal(3, *calls)
+		},
+		"returns context error on cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			cancel()
+			r, calls := failing(0)
+			err := Do(ctx, r)
+
+			as.Equal(context.Canceled, err)
+			as.Zero(*calls)
+		},
+		"returns recovered panic": func(t *testing.T) {
+			as := newAssertions(t)
+
+			err := Do(context.TODO(), nil, Recover(true))
+
+			as.Equal(RunnablePanic{NilRunnable}, err)
+		},
+		"DoValue returns last result": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			res, err := DoValue(context.TODO(), func(context.Context) (int, error) {
+				calls++
+				if calls == 1 {
+					return -1, testError(calls)
+				}
+				return calls, nil
+			}, Restart(true), RestartLimit(0, nil))
+
+			as.NoError(err)
+			as.Equal(2, res)
+		},
+		"DoValue with nil runnable": func(t *testing.T) {
+			as := newAssertions(t)
+
+			res, err := DoValue[string](context.TODO(), nil, Recover(true))
+
+			as.Equal(RunnablePanic{NilRunnable}, err)
+			as.Zero(res)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
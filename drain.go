@@ -0,0 +1,47 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// ErrorSink handles the errors drained from an error channel
+// (see LogErrors).
+type ErrorSink func(error)
+
+// WriterSink returns a sink writing each error to the provided writer
+// on a line of its own, ignoring write errors.
+func WriterSink(w io.Writer) ErrorSink {
+	return func(err error) {
+		_, _ = fmt.Fprintln(w, err)
+	}
+}
+
+// SlogSink returns a sink logging each error to the provided logger
+// (or the default one, if nil) at error level with the provided message,
+// with the error under the "err" key.
+func SlogSink(logger *slog.Logger, msg string) ErrorSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(err error) {
+		logger.Error(msg, "err", err)
+	}
+}
+
+// LogErrors passes each error received from the provided error channel
+// (e.g. returned by Instance.Run or Group.Run) to the provided sink
+// (discarding them if it is nil), returning once the channel is closed.
+//
+// It is usually run in a goroutine of its own, so that the instance
+// is not blocked by an undrained channel, e.g.
+//
+//	go run.LogErrors(inst.Run(ctx), run.SlogSink(logger, "sync failed"))
+func LogErrors(errCh <-chan error, sink ErrorSink) {
+	for err := range errCh {
+		if sink != nil {
+			sink(err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func testDrain(t *testing.T) {
+	failing := func(n int) Runnable {
+		runs := 0
+		return func(context.Context) error {
+			if runs++; runs <= n {
+				return testError(runs)
+			}
+			return nil
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"function sink": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(failing(2), Restart(true))
+			var errs []error
+			LogErrors(inst.Run(context.TODO()), func(err error) {
+				errs = append(errs, err)
+			})
+			as.Equal([]error{testError(1), testError(2)}, errs)
+		},
+		"nil sink": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(failing(2), Restart(true))
+			LogErrors(inst.Run(context.TODO()), nil)
+			as.Equal(Completed, inst.TerminationReason())
+		},
+		"writer sink": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var buf bytes.Buffer
+			errCh := make(chan error, 2)
+			errCh <- errors.New("first")
+			errCh <- errors.New("second")
+			close(errCh)
+
+			LogErrors(errCh, WriterSink(&buf))
+			as.Equal("first\nsecond\n", buf.String())
+		},
+		"slog sink": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			errCh := make(chan error, 1)
+			errCh <- errors.New("failed")
+			close(errCh)
+
+			LogErrors(errCh, SlogSink(logger, "sync failed"))
+			as.Equal("level=ERROR msg=\"sync failed\" err=failed\n", buf.String())
+		},
+		"default logger": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var buf bytes.Buffer
+			defer slog.SetDefault(slog.Default())
+			slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+			SlogSink(nil, "failed")(errors.New("failed"))
+			as.Contains(buf.String(), "msg=failed err=failed")
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
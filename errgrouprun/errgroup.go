@@ -0,0 +1,60 @@
+// Package errgrouprun adapts between runnables and errgroup task sets
+// (golang.org/x/sync/errgroup).
+package errgrouprun
+
+import (
+	"context"
+
+	"github.com/Ale1ster/run"
+	"golang.org/x/sync/errgroup"
+)
+
+// Runnable returns a runnable executing an errgroup task set.
+//
+// On each execution, a new errgroup.Group is derived from the execution
+// context (cancelled once any task fails), the provided function adds
+// its tasks to it, and the execution returns the first error they return.
+func Runnable(tasks func(ctx context.Context, g *errgroup.Group)) run.Runnable {
+	return func(ctx context.Context) error {
+		g, ctx := errgroup.WithContext(ctx)
+		tasks(ctx, g)
+		return g.Wait()
+	}
+}
+
+// Task returns an errgroup task running an instance under the provided
+// context (typically the one of the errgroup.Group) on the goroutine
+// of the task (see run.Instance.RunBlocking), which returns once
+// the instance terminates with its final error, as run.Do does.
+//
+// If the instance has already been run, the task returns
+// run.ErrAlreadyRunning immediately.
+func Task(ctx context.Context, inst *run.Instance) func() error {
+	return func() error {
+		return inst.RunBlocking(ctx)
+	}
+}
+
+// GroupTask returns an errgroup task running a group under the provided
+// context (typically the one of the errgroup.Group), which returns
+// the first error propagated by the group once it terminates
+// (i.e. once it is stopped, or the context is done, and all of its
+// members terminate).
+//
+// If the group has already been run, the task returns nil immediately.
+func GroupTask(ctx context.Context, g *run.Group) func() error {
+	return func() error {
+		errCh := g.Run(ctx)
+		if errCh == nil {
+			return nil
+		}
+
+		var first error
+		for err := range errCh {
+			if first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+}
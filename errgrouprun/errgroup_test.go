@@ -0,0 +1,102 @@
+package errgrouprun
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ale1ster/run"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestRunnable(t *testing.T) {
+	as := assert.New(t)
+
+	var calls int32
+	failure := errors.New("failed")
+	r := Runnable(func(ctx context.Context, g *errgroup.Group) {
+		attempt, _ := run.AttemptFromContext(ctx)
+
+		g.Go(func() error {
+			atomic.AddInt32(&calls, 1)
+			if attempt.Number < 3 {
+				return failure
+			}
+			return nil
+		})
+		g.Go(func() error {
+			// Cancelled by the failure of the other task.
+			if attempt.Number < 3 {
+				<-ctx.Done()
+			}
+			return nil
+		})
+	})
+
+	err := run.Do(context.TODO(), r, run.Restart(true), run.RestartLimit(0, nil))
+	as.NoError(err)
+	as.Equal(int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestTask(t *testing.T) {
+	as := assert.New(t)
+
+	failure := errors.New("failed")
+	g, ctx := errgroup.WithContext(context.TODO())
+
+	recurring := run.New(func(context.Context) error {
+		return nil
+	}, run.Recur(true), run.Period(time.Hour))
+	failing := run.New(func(context.Context) error {
+		return failure
+	})
+
+	g.Go(Task(ctx, &recurring))
+	g.Go(Task(ctx, &failing))
+
+	// The failure cancels the recurring instance.
+	as.Equal(failure, g.Wait())
+	as.Equal(run.StateTerminated, recurring.State())
+	as.Equal(run.ErrAlreadyRunning, Task(ctx, &recurring)())
+
+	succeeding := run.New(func(context.Context) error {
+		return nil
+	})
+	as.NoError(Task(context.TODO(), &succeeding)())
+}
+
+func TestGroupTask(t *testing.T) {
+	as := assert.New(t)
+
+	failure := errors.New("failed")
+	group := run.NewGroup()
+	as.NoError(group.Add("a", func(context.Context) error {
+		return failure
+	}))
+	as.NoError(group.Add("b", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	g, ctx := errgroup.WithContext(context.TODO())
+	g.Go(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return errors.New("other")
+	})
+	g.Go(GroupTask(ctx, group))
+
+	as.EqualError(g.Wait(), "other")
+
+	group = run.NewGroup()
+	as.NoError(group.Add("a", func(context.Context) error {
+		return failure
+	}))
+	timeout, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	err := GroupTask(timeout, group)()
+	as.Equal(run.MemberError{Member: "a", Err: failure}, err)
+	as.NoError(GroupTask(context.TODO(), group)())
+}
@@ -0,0 +1,20 @@
+module github.com/Ale1ster/run/errgrouprun
+
+go 1.26.0
+
+require (
+	github.com/Ale1ster/run v0.0.0
+	github.com/stretchr/testify v1.11.1
+	golang.org/x/sync v0.23.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The APIs used by this module are not part of a tagged release of run yet,
+// so it is resolved from this tree (which consumers have to do as well)
+// until one is, at which point it should be required instead.
+replace github.com/Ale1ster/run => ../
@@ -0,0 +1,62 @@
+package run
+
+import "errors"
+
+// ErrFailureThreshold is propagated by a group when the number of its members
+// that terminated with errors reaches the threshold of its error policy,
+// in which case it is stopped.
+var ErrFailureThreshold = errors.New("group failure threshold reached")
+
+// ErrorPolicy determines whether a group is stopped
+// once some of its members have failed.
+//
+// A member fails when it terminates with an error and is not restarted
+// (see SupervisorStrategy), unless the group is stopped
+// or its context is cancelled, while removed members never fail.
+type ErrorPolicy struct {
+	// threshold is the number of failed members that stops the group,
+	// if non-zero.
+	threshold uint
+}
+
+var (
+	// ContinueOthers keeps running the rest of the members
+	// regardless of how many of them fail.
+	ContinueOthers = ErrorPolicy{}
+	// GroupFailFast stops the group once any of its members fails
+	// (equivalent to Threshold(1)).
+	GroupFailFast = ErrorPolicy{threshold: 1}
+)
+
+// Threshold stops a group once n of its members have failed,
+// and is equivalent to ContinueOthers if n is 0.
+func Threshold(n uint) ErrorPolicy {
+	return ErrorPolicy{threshold: n}
+}
+
+// WithErrorPolicy sets the error policy of a group
+// (default: ContinueOthers).
+//
+// Once the group is stopped by its error policy,
+// ErrFailureThreshold is propagated.
+func WithErrorPolicy(policy ErrorPolicy) GroupOption {
+	return func(o *groupOptions) *groupOptions {
+		o.errorPolicy = policy
+		return o
+	}
+}
+
+// breach records the failure of a member, stopping the group
+// if the threshold of its error policy is reached,
+// and indicates whether it was.
+// The lock of the group should be held.
+func (s *supervisor) breach() bool {
+	s.failures++
+	threshold := s.g.opts.errorPolicy.threshold
+	if threshold == 0 || s.failures != threshold {
+		return false
+	}
+
+	s.g.stop()
+	return true
+}
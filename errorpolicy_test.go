@@ -0,0 +1,126 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testErrorPolicy(t *testing.T) {
+	failing := func(name string) Runnable {
+		return func(context.Context) error {
+			return testError(name)
+		}
+	}
+	blocking := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	subtests := map[string]func(*testing.T){
+		"continue others by default": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", failing("a")))
+			as.NoError(g.Add("b", failing("b")))
+			as.NoError(g.Add("c", func(context.Context) error {
+				return nil
+			}))
+
+			as.Equal([]error{
+				MemberError{Member: "a", Err: testError("a")},
+				MemberError{Member: "b", Err: testError("b")},
+			}, sortErrors(waitGroup(g, context.TODO())))
+		},
+		"fail fast": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup(WithErrorPolicy(GroupFailFast))
+			as.NoError(g.Add("a", failing("a")))
+			as.NoError(g.Add("b", blocking))
+
+			as.Equal([]error{
+				MemberError{Member: "a", Err: testError("a")},
+				ErrFailureThreshold,
+			}, sortErrors(waitGroup(g, context.TODO())))
+			as.ErrorIs(g.Add("c", blocking), ErrGroupTerminated)
+		},
+		"ordered fail fast": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup(Ordered(true), WithErrorPolicy(Threshold(1)))
+			as.NoError(g.Add("a", blocking))
+			as.NoError(g.Add("b", failing("b")))
+
+			as.Equal([]error{
+				MemberError{Member: "b", Err: testError("b")},
+				ErrFailureThreshold,
+			}, sortErrors(waitGroup(g, context.TODO())))
+		},
+		"threshold": func(t *testing.T) {
+			as := newAssertions(t)
+
+			second := make(chan struct{})
+			g := NewGroup(WithErrorPolicy(Threshold(2)))
+			as.NoError(g.Add("a", func(context.Context) error {
+				defer close(second)
+				return testError("a")
+			}))
+			as.NoError(g.Add("b", func(ctx context.Context) error {
+				<-second
+				return testError("b")
+			}))
+			as.NoError(g.Add("c", blocking))
+
+			as.Equal([]error{
+				MemberError{Member: "a", Err: testError("a")},
+				MemberError{Member: "b", Err: testError("b")},
+				ErrFailureThreshold,
+			}, sortErrors(waitGroup(g, context.TODO())))
+		},
+		"restarted members do not fail": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			g := NewGroup(Supervise(OneForOne), WithErrorPolicy(GroupFailFast))
+			as.NoError(g.Add("a", func(context.Context) error {
+				if runs++; runs == 1 {
+					return testError("a")
+				}
+				return nil
+			}))
+
+			as.Equal([]error{
+				MemberError{Member: "a", Err: testError("a")},
+			}, waitGroup(g, context.TODO()))
+			as.Equal(2, runs)
+		},
+		"cancellation and removal are not failures": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			g := NewGroup(WithErrorPolicy(GroupFailFast))
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}))
+			as.NoError(g.Add("b", blocking, WithMiddleware(func(next Runnable) Runnable {
+				return func(ctx context.Context) error {
+					as.NoError(g.Remove("b"))
+					return testError("b")
+				}
+			})))
+
+			errCh := g.Run(ctx)
+			as.Equal(MemberError{Member: "b", Err: testError("b")}, <-errCh)
+			cancel()
+			as.Equal([]error{
+				MemberError{Member: "a", Err: context.Canceled},
+			}, waitErrors(errCh))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,125 @@
+package run
+
+import "time"
+
+// Event represents an occurrence during the execution of an instance.
+//
+// It is one of RunStarted, RunSucceeded, RunFailed, RunAbandoned,
+// RunSkipped, RunSlow, RunnableUnresponsive, ProgressReported,
+// BackoffStarted, RetryDenied, RunsMissed, Recovered, ChildFailed,
+// FailureRateExceeded, FailureRateRestored or Terminated.
+type Event interface {
+	event()
+}
+
+// RunStarted is emitted before each execution of the runnable.
+type RunStarted struct{}
+
+// RunSucceeded is emitted after an execution of the runnable
+// that returned no error.
+type RunSucceeded struct {
+	// Duration is the duration of the execution.
+	Duration time.Duration
+}
+
+// RunFailed is emitted after an execution of the runnable
+// that returned an error.
+type RunFailed struct {
+	// Err is the error returned by the execution.
+	Err error
+	// Duration is the duration of the execution.
+	Duration time.Duration
+}
+
+// RunAbandoned is emitted after an execution of the runnable
+// that did not return within the stop grace period of its instance
+// (propagating ErrRunAbandoned), which terminates afterwards,
+// or within its abandonment limit after its context was cancelled
+// (see AbandonAfter), in which case the instance keeps running.
+type RunAbandoned struct {
+	// Duration is the duration of the execution until it was abandoned.
+	Duration time.Duration
+}
+
+// RunSkipped is emitted when an execution of the runnable is skipped,
+// since its precondition does not hold (see RunIf)
+// or another process holds its lock (see Coordinate).
+type RunSkipped struct {
+	// Reason is ErrNotLeader if another process holds the lock,
+	// or nil if the precondition does not hold.
+	Reason error
+}
+
+// ProgressReported is emitted when an execution of the runnable
+// reports its progress (see Progress).
+type ProgressReported struct {
+	// Done and Total are the amounts of work done so far
+	// and in total respectively, in units of the runnable's choosing.
+	Done, Total uint64
+	// Message describes the progress.
+	Message string
+}
+
+// BackoffStarted is emitted when a failed execution
+// is about to be restarted after a backoff period.
+type BackoffStarted struct {
+	// Delay is the backoff period preceding the restart.
+	Delay time.Duration
+}
+
+// RetryDenied is emitted when a failed execution is not restarted,
+// since the retry budget of the instance is depleted (see WithRetryBudget).
+type RetryDenied struct{}
+
+// RunsMissed is emitted when executions of a recurring runnable
+// in fixed-rate mode are skipped, since a previous one outlasted its period,
+// or when executions missed while the process was down are skipped
+// (see CatchUp).
+type RunsMissed struct {
+	// Count is the number of skipped executions.
+	Count uint64
+}
+
+// Recovered is emitted when a panic is recovered from.
+type Recovered struct {
+	// Panic is the recovered value.
+	Panic interface{}
+}
+
+// Terminated is the final event emitted by an instance.
+type Terminated struct {
+	// Reason is the error that caused the termination of the instance:
+	// the context error in case of cancellation,
+	// a RunnablePanic in case of recovered panic,
+	// the limiter error in case it prevented an execution,
+	// or nil if the instance completed according to its options.
+	Reason error
+}
+
+func (RunStarted) event()       {}
+func (RunSucceeded) event()     {}
+func (RunFailed) event()        {}
+func (RunAbandoned) event()     {}
+func (RunSkipped) event()       {}
+func (ProgressReported) event() {}
+func (BackoffStarted) event()   {}
+func (RetryDenied) event()      {}
+func (RunsMissed) event()       {}
+func (Recovered) event()        {}
+func (Terminated) event()       {}
+
+// eventError returns the error an event propagates to the error channel,
+// if any.
+func eventError(ev Event) error {
+	switch e := ev.(type) {
+	case RunFailed:
+		return e.Err
+	case RunAbandoned:
+		return ErrRunAbandoned
+	case ChildFailed:
+		return ChildError(e)
+	case Terminated:
+		return e.Reason
+	}
+	return nil
+}
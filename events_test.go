@@ -0,0 +1,133 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitEvents drains an event channel, zeroing durations
+// so that events can be compared.
+func waitEvents(evCh <-chan Event) []Event {
+	evs := make([]Event, 0)
+	for ev := range evCh {
+		switch e := ev.(type) {
+		case RunSucceeded:
+			e.Duration = 0
+			ev = e
+		case RunFailed:
+			e.Duration = 0
+			ev = e
+		case RunAbandoned:
+			e.Duration = 0
+			ev = e
+		case RunSlow:
+			e.Elapsed = 0
+			ev = e
+		case RunnableUnresponsive:
+			e.Overrun = 0
+			ev = e
+		}
+		evs = append(evs, ev)
+	}
+	return evs
+}
+
+func testEvents(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"restarts and successes": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			inst := New(func(context.Context) error {
+				calls++
+				if calls == 1 {
+					return testError(1)
+				}
+				return nil
+			},
+				Recur(true),
+				RunLimit(2),
+				Restart(true),
+				RestartLimit(0, ConstantBackoff(time.Millisecond)),
+			)
+
+			as.Equal([]Event{
+				RunStarted{},
+				RunFailed{Err: testError(1)},
+				BackoffStarted{Delay: time.Millisecond},
+				RunStarted{},
+				RunSucceeded{},
+				RunStarted{},
+				RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+		},
+		"recovered panic": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				panic("panic message")
+			}, Recover(true))
+
+			as.Equal([]Event{
+				RunStarted{},
+				Recovered{Panic: "panic message"},
+				Terminated{Reason: RunnablePanic{"panic message"}},
+			}, waitEvents(inst.Events(context.TODO())))
+		},
+		"cancelled context": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			inst := New(func(context.Context) error {
+				cancel()
+				return nil
+			}, Recur(true), Period(time.Hour))
+
+			as.Equal([]Event{
+				RunStarted{},
+				RunSucceeded{},
+				Terminated{Reason: context.Canceled},
+			}, waitEvents(inst.Events(ctx)))
+		},
+		"instance runs at most once": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			})
+
+			as.NotNil(inst.Events(context.TODO()))
+			as.Nil(inst.Events(context.TODO()))
+			as.Nil(inst.Run(context.TODO()))
+		},
+		"events propagated as errors": func(t *testing.T) {
+			cases := []struct {
+				event    Event
+				expected error
+			}{
+				{RunStarted{}, nil},
+				{RunSucceeded{}, nil},
+				{RunFailed{Err: testError(1)}, testError(1)},
+				{BackoffStarted{}, nil},
+				{Recovered{Panic: 1}, nil},
+				{Terminated{}, nil},
+				{Terminated{Reason: context.Canceled}, context.Canceled},
+			}
+
+			as := assert.New(t)
+			for _, tc := range cases {
+				as.Equalf(tc.expected, eventError(tc.event), "%#v", tc.event)
+				tc.event.event()
+			}
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,77 @@
+package run
+
+import (
+	"context"
+	"fmt"
+)
+
+// Gate guards the executions of runnables, which only execute
+// while holding it, such as in the case of leader election,
+// distributed locks or semaphores.
+type Gate interface {
+	// Acquire blocks until the gate is held,
+	// or returns an error if it cannot be.
+	Acquire(ctx context.Context) error
+	// Release releases the gate, once an execution has finished.
+	Release(ctx context.Context) error
+}
+
+// GateError wraps the error of a gate failing to be acquired or released
+// (see WithGate), distinguishing it from the errors of executions.
+type GateError struct {
+	// Op is the failed operation, either "acquire" or "release".
+	Op string
+	// Err is the error returned by the gate.
+	Err error
+}
+
+// Error returns the error of the gate, annotated with its operation.
+func (e GateError) Error() string {
+	return fmt.Sprintf("gate %s failed: %v", e.Op, e.Err)
+}
+
+// Unwrap returns the error of the gate.
+func (e GateError) Unwrap() error {
+	return e.Err
+}
+
+// WithGate guards each execution of a runnable by the provided gate
+// (default: nil, no gate), which is acquired before and released after it,
+// even if it panics. The gate of an abandoned execution
+// (see StopGrace and AbandonAfter) is released once it eventually returns,
+// so that executions guarded by it never overlap.
+//
+// Acquiring the gate takes place after any limiter or precondition,
+// and does not count towards the duration or timeout of executions.
+// An execution is failed with a GateError if the gate cannot be acquired,
+// or if it succeeds but the gate cannot be released,
+// subject to the restart options of the runnable.
+func WithGate(g Gate) Option {
+	return func(o *options) *options {
+		o.gate = g
+		return o
+	}
+}
+
+// acquire acquires the gate of a runnable (if any) for an execution.
+func (o *options) acquire(ctx context.Context) error {
+	if o == nil || o.gate == nil {
+		return nil
+	}
+	if err := o.gate.Acquire(ctx); err != nil {
+		return GateError{Op: "acquire", Err: err}
+	}
+	return nil
+}
+
+// release releases the gate of a runnable (if any) after an execution,
+// even if its context is done.
+func (o *options) release(ctx context.Context) error {
+	if o == nil || o.gate == nil {
+		return nil
+	}
+	if err := o.gate.Release(context.WithoutCancel(ctx)); err != nil {
+		return GateError{Op: "release", Err: err}
+	}
+	return nil
+}
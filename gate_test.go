@@ -0,0 +1,139 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// testGate is a gate counting its acquisitions and releases,
+// failing the ones for which errors are set.
+type testGate struct {
+	held               bool
+	acquires, releases int
+	acquireErr         func(n int) error
+	releaseErr         func(n int) error
+}
+
+func (g *testGate) Acquire(ctx context.Context) error {
+	g.acquires++
+	if g.acquireErr != nil {
+		if err := g.acquireErr(g.acquires); err != nil {
+			return err
+		}
+	}
+	g.held = true
+	return nil
+}
+
+func (g *testGate) Release(ctx context.Context) error {
+	g.releases++
+	g.held = false
+	if g.releaseErr != nil {
+		return g.releaseErr(g.releases)
+	}
+	return nil
+}
+
+func testGating(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"executions hold the gate": func(t *testing.T) {
+			as := newAssertions(t)
+
+			gate := &testGate{}
+			inst := New(func(context.Context) error {
+				as.True(gate.held)
+				return nil
+			}, Recur(true), RunLimit(3), WithGate(gate))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(3, gate.acquires)
+			as.Equal(3, gate.releases)
+			as.False(gate.held)
+		},
+		"released on panic": func(t *testing.T) {
+			as := newAssertions(t)
+
+			gate := &testGate{}
+			inst := New(func(context.Context) error {
+				panic("panic message")
+			}, Recover(true), WithGate(gate))
+
+			as.Equal([]error{RunnablePanic{"panic message"}},
+				waitErrors(inst.Run(context.TODO())))
+			as.Equal(1, gate.releases)
+			as.False(gate.held)
+		},
+		"failed acquisition is restarted": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			gate := &testGate{acquireErr: func(n int) error {
+				if n == 1 {
+					return testError(-1)
+				}
+				return nil
+			}}
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, Restart(true), RestartLimit(0, nil), WithGate(gate))
+
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.Equal([]error{GateError{Op: "acquire", Err: testError(-1)}}, errs)
+			as.ErrorIs(errs[0], testError(-1))
+			as.EqualError(errs[0], "gate acquire failed: "+testError(-1).Error())
+			as.Equal(1, runs)
+			as.Equal(1, gate.releases)
+		},
+		"failed release": func(t *testing.T) {
+			as := newAssertions(t)
+
+			gate := &testGate{releaseErr: func(int) error {
+				return testError(-1)
+			}}
+			runs := 0
+			inst := New(func(context.Context) error {
+				if runs++; runs == 1 {
+					return testError(1)
+				}
+				return nil
+			}, Restart(true), RestartLimit(2, nil), WithGate(gate))
+
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.Equal([]error{
+				testError(1),
+				GateError{Op: "release", Err: testError(-1)},
+			}, errs)
+			var gerr GateError
+			as.False(errors.As(errs[0], &gerr))
+			as.True(errors.As(errs[1], &gerr))
+			as.Equal(RestartLimitExceeded, inst.TerminationReason())
+		},
+		"cancelled while acquiring": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			gate := &testGate{acquireErr: func(int) error {
+				cancel()
+				return ctx.Err()
+			}}
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, WithGate(gate))
+
+			as.Equal([]error{context.Canceled}, waitErrors(inst.Run(ctx)))
+			as.Zero(runs)
+			as.Zero(gate.releases)
+			as.Equal(ContextCancelled, inst.TerminationReason())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
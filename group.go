@@ -0,0 +1,260 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrDuplicateMember is returned when adding a member to a group
+	// under a name that is already in use.
+	ErrDuplicateMember = errors.New("duplicate group member")
+	// ErrGroupTerminated is returned when adding a member to a group
+	// that has been stopped, or whose context is done.
+	ErrGroupTerminated = errors.New("group already terminated")
+	// ErrUnknownMember is returned when removing a member
+	// that is not part of a group.
+	ErrUnknownMember = errors.New("unknown group member")
+)
+
+// Group runs multiple named instances together under a shared context,
+// propagating their errors to a single channel.
+type Group struct {
+	opts *groupOptions
+
+	// mu guards the members of a group, along with its status.
+	mu      sync.Mutex
+	members []*member
+	started bool
+	stopped bool
+	// sup is the supervisor of a group, once it runs.
+	sup *supervisor
+}
+
+// groupOptions encapsulates the execution options of a group.
+type groupOptions struct {
+	supervision supervisionOptions
+	ordered     bool
+	defaults    []Option
+	errorPolicy ErrorPolicy
+	quorum      uint
+}
+
+// GroupOption represents an execution option for a group.
+type GroupOption func(*groupOptions) *groupOptions
+
+// member represents a named instance of a group.
+type member struct {
+	name string
+	inst *Instance
+	// running indicates whether the instance of the member is running,
+	// while removed indicates whether it has been removed from the group.
+	running bool
+	removed bool
+	// done is closed once the instance of the member terminates.
+	done chan struct{}
+}
+
+// MemberError represents an error propagated by a member of a group.
+type MemberError struct {
+	// Member is the name of the member.
+	Member string
+	// Err is the error propagated by the member.
+	Err error
+}
+
+// Error satisfies error interface for MemberError.
+func (e MemberError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Member, e.Err)
+}
+
+// Unwrap returns the error propagated by the member.
+func (e MemberError) Unwrap() error {
+	return e.Err
+}
+
+// NewGroup creates a new empty group with the provided options.
+//
+// In case of conflicting options, the last one will be applied.
+func NewGroup(opts ...GroupOption) *Group {
+	groupOpts := new(groupOptions)
+	for _, opt := range opts {
+		groupOpts = opt(groupOpts)
+	}
+
+	return &Group{opts: groupOpts}
+}
+
+// MemberDefaults sets the default options of the members
+// added to a group through Add, which are applied
+// before the options each member is added with, so that the latter
+// override them (e.g. a longer timeout for one of the members).
+//
+// They do not apply to instances added through AddInstance,
+// whose options are already set.
+func MemberDefaults(opts ...Option) GroupOption {
+	return func(o *groupOptions) *groupOptions {
+		o.defaults = opts
+		return o
+	}
+}
+
+// Add adds a runnable to a group under the provided name,
+// as a new instance with the default options of the group
+// (see MemberDefaults), overridden by the provided options.
+func (g *Group) Add(name string, r Runnable, opts ...Option) error {
+	inst := New(r, WithOptions(g.opts.defaults...), WithOptions(opts...))
+	return g.AddInstance(name, &inst)
+}
+
+// AddInstance adds an instance to a group under the provided name.
+// If the group is running, the instance starts immediately.
+//
+// The instance is run along with the group, so it should not be run separately.
+// In case the member is restarted by the group, a new instance is created
+// with the same runnable and options.
+func (g *Group) AddInstance(name string, inst *Instance) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped || (g.sup != nil && !g.sup.open()) {
+		return ErrGroupTerminated
+	}
+	if g.lookup(name) != -1 {
+		return fmt.Errorf("%w: %q", ErrDuplicateMember, name)
+	}
+
+	m := &member{name: name, inst: inst}
+	g.members = append(g.members, m)
+	if g.sup != nil {
+		g.sup.launch([]*member{m})
+	}
+	return nil
+}
+
+// Remove removes a member from a group, stopping it without affecting
+// the rest of its members. Its errors are propagated until it terminates,
+// though it is not restarted.
+//
+// Remove does not wait for the member to terminate.
+func (g *Group) Remove(name string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	idx := g.lookup(name)
+	if idx == -1 {
+		return fmt.Errorf("%w: %q", ErrUnknownMember, name)
+	}
+
+	m := g.members[idx]
+	m.removed = true
+	m.inst.Stop()
+	g.members = append(g.members[:idx], g.members[idx+1:]...)
+	return nil
+}
+
+// Members returns the names of the members of a group,
+// in the order they were added.
+func (g *Group) Members() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	names := make([]string, 0, len(g.members))
+	for _, m := range g.members {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// Member returns the instance of the member with the provided name,
+// and whether there is such a member.
+// The instance of a member is replaced whenever it is restarted by the group.
+func (g *Group) Member(name string) (*Instance, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	idx := g.lookup(name)
+	if idx == -1 {
+		return nil, false
+	}
+	return g.members[idx].inst, true
+}
+
+// lookup returns the index of the member with the provided name,
+// or -1 if there is none.
+// The lock of the group should be held.
+func (g *Group) lookup(name string) int {
+	for idx, m := range g.members {
+		if m.name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+// Run runs all members of a group in separate goroutines under the provided
+// context, and returns a channel where any errors they encounter are
+// propagated as MemberError, which is closed once the group is stopped
+// (or the context is done) and all of them terminate
+// (including members added while it runs).
+// A running group without members keeps running, so that members
+// can be added to it later.
+// A group can be run at most once,
+// with subsequent attempts returning a nil channel.
+//
+// Members terminate independently of each other, unless the group
+// is stopped or supervised (in which case errors concerning
+// the supervision itself are also propagated).
+func (g *Group) Run(ctx context.Context) <-chan error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.started {
+		return nil
+	}
+	g.started = true
+
+	s := newSupervisor(ctx, g)
+	g.sup = s
+	s.launch(g.members)
+	go s.supervise()
+	if g.opts.ordered {
+		go s.watch()
+	}
+
+	return s.errCh
+}
+
+// Stop stops all members of a group, as if each of them was stopped
+// (in reverse order, if the group is ordered).
+// Stopping a group before it runs makes its members terminate immediately.
+//
+// Stop does not wait for the members to terminate.
+func (g *Group) Stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.stop()
+}
+
+// stop stops all members of a group (see Stop).
+// The lock of the group should be held.
+func (g *Group) stop() {
+	if g.stopped {
+		return
+	}
+	g.stopped = true
+
+	if g.sup != nil {
+		close(g.sup.stopping)
+	}
+	if g.opts.ordered && g.sup != nil {
+		go g.sup.shutdown()
+		return
+	}
+	for _, m := range g.members {
+		m.inst.Stop()
+	}
+}
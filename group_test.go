@@ -0,0 +1,306 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sortErrors sorts errors by their message,
+// since the errors of group members are propagated in any order.
+func sortErrors(errs []error) []error {
+	sort.Slice(errs, func(a, b int) bool {
+		return errs[a].Error() < errs[b].Error()
+	})
+	return errs
+}
+
+// waitGroup runs a group under the provided context, stopping it
+// once none of its members are running (or are about to be restarted),
+// and returns the errors it propagates.
+func waitGroup(g *Group, ctx context.Context) []error {
+	errCh := g.Run(ctx)
+	go func() {
+		for !g.idle() {
+			time.Sleep(time.Millisecond)
+		}
+		g.Stop()
+	}()
+	return waitErrors(errCh)
+}
+
+// idle indicates whether none of the members of a running group are running.
+func (g *Group) idle() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.sup.active == 0
+}
+
+func testGroup(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"errors are attributed to members": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(context.Context) error {
+				return testError("a")
+			}))
+			as.NoError(g.Add("b", func(context.Context) error {
+				return testError("b")
+			}, Restart(true), RestartLimit(2, nil)))
+			as.NoError(g.Add("c", func(context.Context) error {
+				return nil
+			}))
+
+			errs := sortErrors(waitGroup(g, context.TODO()))
+			as.Equal([]error{
+				MemberError{Member: "a", Err: testError("a")},
+				MemberError{Member: "b", Err: testError("b")},
+				MemberError{Member: "b", Err: testError("b")},
+			}, errs)
+			as.Equal(`a: test error: a`, errs[0].Error())
+
+			var target TestError
+			as.True(errors.As(errs[1], &target))
+			as.Equal(testError("b"), target)
+		},
+		"member defaults": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup(MemberDefaults(Recover(true), Timeout(time.Second)))
+			as.NoError(g.Add("a", nil))
+			as.NoError(g.Add("b", nil, Timeout(time.Minute)))
+			inst := New(func(context.Context) error {
+				return nil
+			})
+			as.NoError(g.AddInstance("c", &inst))
+
+			for name, expected := range map[string]*options{
+				"a": {
+					recoverable: panicOptions{calm: true},
+					constrained: constraintOptions{timeout: time.Second},
+				},
+				"b": {
+					recoverable: panicOptions{calm: true},
+					constrained: constraintOptions{timeout: time.Minute},
+				},
+				"c": {},
+			} {
+				m, ok := g.Member(name)
+				if as.True(ok) {
+					as.Equal(expected, m.opts, name)
+				}
+			}
+
+			errs := sortErrors(waitGroup(g, context.TODO()))
+			as.Equal([]error{
+				MemberError{Member: "a", Err: RunnablePanic{NilRunnable}},
+				MemberError{Member: "b", Err: RunnablePanic{NilRunnable}},
+			}, errs)
+		},
+		"members share the context": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+
+			g := NewGroup()
+			for _, name := range []string{"a", "b"} {
+				as.NoError(g.Add(name, func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}))
+			}
+
+			as.Equal([]error{
+				MemberError{Member: "a", Err: context.DeadlineExceeded},
+				MemberError{Member: "b", Err: context.DeadlineExceeded},
+			}, sortErrors(waitGroup(g, ctx)))
+		},
+		"stopping stops all members": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			a := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			})
+			as.NoError(g.AddInstance("a", &a))
+			as.NoError(g.Add("b", func(context.Context) error {
+				return nil
+			}, Recur(true), Period(time.Hour)))
+
+			errCh := g.Run(context.TODO())
+			time.Sleep(testTimeDelta)
+			g.Stop()
+
+			as.Empty(waitErrors(errCh))
+			as.Equal(StateStopped, a.State())
+		},
+		"stopping before running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(context.Context) error {
+				return testError("a")
+			}))
+			g.Stop()
+
+			as.Empty(waitGroup(g, context.TODO()))
+		},
+		"invalid additions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(context.Context) error {
+				return nil
+			}))
+			as.ErrorIs(g.Add("a", nil), ErrDuplicateMember)
+
+			as.Empty(waitGroup(g, context.TODO()))
+			as.Nil(g.Run(context.TODO()))
+			as.ErrorIs(g.Add("b", nil), ErrGroupTerminated)
+
+			g = NewGroup()
+			g.Stop()
+			as.ErrorIs(g.Add("a", nil), ErrGroupTerminated)
+		},
+		"members added while running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}))
+
+			errCh := g.Run(context.TODO())
+			as.NoError(g.Add("b", func(context.Context) error {
+				return testError("b")
+			}))
+
+			as.Equal(MemberError{Member: "b", Err: testError("b")}, <-errCh)
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"empty groups keep running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			errCh := g.Run(context.TODO())
+			time.Sleep(testTimeDelta)
+			as.NoError(g.Add("a", func(context.Context) error {
+				return testError("a")
+			}))
+			as.Equal(MemberError{Member: "a", Err: testError("a")}, <-errCh)
+
+			// The group keeps running once its members terminate as well.
+			time.Sleep(testTimeDelta)
+			as.NoError(g.Add("b", func(context.Context) error {
+				return nil
+			}))
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+			as.ErrorIs(g.Add("c", nil), ErrGroupTerminated)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			g = NewGroup()
+			errCh = g.Run(ctx)
+			cancel()
+			as.Empty(waitErrors(errCh))
+			as.ErrorIs(g.Add("a", nil), ErrGroupTerminated)
+		},
+		"members removed while running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			g := NewGroup(Supervise(OneForOne))
+			a := New(func(ctx context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			as.NoError(g.AddInstance("a", &a))
+			as.NoError(g.Add("b", func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}))
+
+			errCh := g.Run(context.TODO())
+			as.Eventually(func() bool {
+				return atomic.LoadInt32(&calls) == 1
+			}, time.Second, time.Millisecond)
+			as.NoError(g.Remove("a"))
+			as.ErrorIs(g.Remove("a"), ErrUnknownMember)
+
+			// The removed member is neither restarted, nor affects the rest.
+			as.Equal(MemberError{Member: "a", Err: context.Canceled}, <-errCh)
+			as.Eventually(func() bool {
+				return a.State() == StateStopped
+			}, time.Second, time.Millisecond)
+			as.Equal(int32(1), atomic.LoadInt32(&calls))
+
+			// The name can be reused.
+			as.NoError(g.Add("a", func(context.Context) error {
+				return nil
+			}))
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"members removed before running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(context.Context) error {
+				return testError("a")
+			}))
+			as.NoError(g.Remove("a"))
+
+			as.Empty(waitGroup(g, context.TODO()))
+		},
+		"instances already run are skipped": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			})
+			waitErrors(inst.Run(context.TODO()))
+
+			g := NewGroup()
+			as.NoError(g.AddInstance("a", &inst))
+
+			as.Empty(waitGroup(g, context.TODO()))
+		},
+		"members": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			})
+
+			g := NewGroup()
+			as.Empty(g.Members())
+			as.NoError(g.AddInstance("b", &inst))
+			as.NoError(g.Add("a", func(context.Context) error {
+				return nil
+			}))
+			as.Equal([]string{"b", "a"}, g.Members())
+
+			member, ok := g.Member("b")
+			as.True(ok)
+			as.Same(&inst, member)
+			_, ok = g.Member("c")
+			as.False(ok)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
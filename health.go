@@ -0,0 +1,145 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// errTerminated is reported by NotTerminated.
+var errTerminated = errors.New("terminated")
+
+// HealthRule determines whether an instance is healthy, provided with
+// a snapshot of its statistics along with the time it was taken,
+// returning an error describing the problem if it is not.
+type HealthRule func(stats Stats, now time.Time) error
+
+// HealthRules adds rules determining the health of an instance
+// (default: none, always healthy).
+// It can be provided multiple times, accumulating rules.
+func HealthRules(rules ...HealthRule) Option {
+	return func(o *options) *options {
+		for _, rule := range rules {
+			if rule != nil {
+				o.health = append(o.health, rule)
+			}
+		}
+		return o
+	}
+}
+
+// MaxConsecutiveFailures returns a health rule reporting an instance
+// as unhealthy once its consecutive failed executions reach n.
+func MaxConsecutiveFailures(n uint64) HealthRule {
+	return func(stats Stats, _ time.Time) error {
+		if stats.ConsecutiveFailures >= n {
+			return fmt.Errorf("%d consecutive failed executions",
+				stats.ConsecutiveFailures)
+		}
+		return nil
+	}
+}
+
+// SuccessWithin returns a health rule reporting a running instance
+// as unhealthy if no execution has succeeded during the latest period
+// of the provided duration (since it started running, if none has).
+func SuccessWithin(d time.Duration) HealthRule {
+	return func(stats Stats, now time.Time) error {
+		if stats.StartedAt.IsZero() {
+			return nil
+		}
+
+		since := stats.LastSuccess
+		if since.IsZero() {
+			since = stats.StartedAt
+		}
+		if now.Sub(since) > d {
+			return fmt.Errorf("no successful execution within %v", d)
+		}
+		return nil
+	}
+}
+
+// NotTerminated returns a health rule reporting an instance
+// as unhealthy once it terminates on its own (see StateTerminated).
+func NotTerminated() HealthRule {
+	return func(stats Stats, _ time.Time) error {
+		if stats.State == StateTerminated {
+			return errTerminated
+		}
+		return nil
+	}
+}
+
+// HealthReport represents the health of an instance
+// according to its health rules.
+type HealthReport struct {
+	// Healthy indicates whether all health rules are satisfied.
+	Healthy bool
+	// Problems are the errors reported by the rules that are not.
+	Problems []string
+}
+
+// HealthReport returns the health of an instance according to its health rules.
+//
+// It is safe to call while the instance is running.
+func (i *Instance) HealthReport() HealthReport {
+	stats, now := i.Stats(), i.options().clock().Now()
+
+	report := HealthReport{Healthy: true}
+	if i.options() == nil {
+		return report
+	}
+	for _, rule := range i.options().health {
+		if err := rule(stats, now); err != nil {
+			report.Healthy = false
+			report.Problems = append(report.Problems, err.Error())
+		}
+	}
+	return report
+}
+
+// Healthy indicates whether an instance is healthy
+// according to its health rules.
+func (i *Instance) Healthy() bool {
+	return i.HealthReport().Healthy
+}
+
+// GroupHealthReport represents the health of the members of a group.
+type GroupHealthReport struct {
+	// Healthy indicates whether all members are healthy,
+	// or whether enough of them are running and healthy
+	// if the group has a quorum (see GroupQuorum).
+	Healthy bool
+	// Members holds the health of each member, by name.
+	Members map[string]HealthReport
+}
+
+// HealthReport returns the health of the members of a group,
+// according to the health rules of each one.
+func (g *Group) HealthReport() GroupHealthReport {
+	g.mu.Lock()
+	members := make([]member, 0, len(g.members))
+	for _, m := range g.members {
+		members = append(members, member{name: m.name, inst: m.inst, running: m.running})
+	}
+	g.mu.Unlock()
+
+	report := GroupHealthReport{
+		Healthy: true,
+		Members: make(map[string]HealthReport, len(members)),
+	}
+	quorate := uint(0)
+	for _, m := range members {
+		health := m.inst.HealthReport()
+		report.Healthy = report.Healthy && health.Healthy
+		report.Members[m.name] = health
+		if m.running && health.Healthy {
+			quorate++
+		}
+	}
+	if quorum := g.opts.quorum; quorum != 0 {
+		report.Healthy = quorate >= quorum
+	}
+	return report
+}
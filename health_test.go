@@ -0,0 +1,119 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testHealth(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"HealthRules accumulates rules": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{
+				HealthRules(NotTerminated(), nil),
+				HealthRules(MaxConsecutiveFailures(1)),
+			})
+			as.Len(opts.health, 2)
+		},
+		"MaxConsecutiveFailures": func(t *testing.T) {
+			as := newAssertions(t)
+
+			rule := MaxConsecutiveFailures(2)
+			as.NoError(rule(Stats{ConsecutiveFailures: 1}, time.Now()))
+			as.EqualError(rule(Stats{ConsecutiveFailures: 2}, time.Now()),
+				"2 consecutive failed executions")
+		},
+		"SuccessWithin": func(t *testing.T) {
+			as := newAssertions(t)
+
+			now := time.Now()
+			rule := SuccessWithin(time.Minute)
+			as.NoError(rule(Stats{}, now))
+			as.NoError(rule(Stats{StartedAt: now.Add(-time.Second)}, now))
+			as.EqualError(rule(Stats{StartedAt: now.Add(-time.Hour)}, now),
+				"no successful execution within 1m0s")
+			as.NoError(rule(Stats{
+				StartedAt:   now.Add(-time.Hour),
+				LastSuccess: now.Add(-time.Second),
+			}, now))
+			as.Error(rule(Stats{
+				StartedAt:   now.Add(-time.Hour),
+				LastSuccess: now.Add(-2 * time.Minute),
+			}, now))
+		},
+		"NotTerminated": func(t *testing.T) {
+			as := newAssertions(t)
+
+			rule := NotTerminated()
+			as.NoError(rule(Stats{State: StateStopped}, time.Now()))
+			as.EqualError(rule(Stats{State: StateTerminated}, time.Now()),
+				"terminated")
+		},
+		"instance health": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var unconfigured Instance
+			as.True(unconfigured.Healthy())
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, HealthRules(MaxConsecutiveFailures(1), NotTerminated()))
+			as.Equal(HealthReport{Healthy: true}, inst.HealthReport())
+
+			waitErrors(inst.Run(context.TODO()))
+			as.False(inst.Healthy())
+			as.Equal(HealthReport{
+				Problems: []string{"1 consecutive failed executions", "terminated"},
+			}, inst.HealthReport())
+
+			stats := inst.Stats()
+			as.False(stats.StartedAt.IsZero())
+			as.True(stats.LastSuccess.IsZero())
+		},
+		"last success": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			})
+			before := time.Now()
+			waitErrors(inst.Run(context.TODO()))
+
+			stats := inst.Stats()
+			as.False(stats.LastSuccess.Before(stats.StartedAt))
+			as.False(stats.StartedAt.Before(before))
+		},
+		"group health": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(context.Context) error {
+				return nil
+			}, HealthRules(NotTerminated())))
+			as.NoError(g.Add("b", func(context.Context) error {
+				return nil
+			}))
+			as.Equal(GroupHealthReport{
+				Healthy: true,
+				Members: map[string]HealthReport{
+					"a": {Healthy: true},
+					"b": {Healthy: true},
+				},
+			}, g.HealthReport())
+
+			waitGroup(g, context.TODO())
+			as.Equal(GroupHealthReport{
+				Members: map[string]HealthReport{
+					"a": {Problems: []string{"terminated"}},
+					"b": {Healthy: true},
+				},
+			}, g.HealthReport())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,98 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrHeartbeatTimeout is returned by an execution of a runnable
+// that did not report a heartbeat in time (see HeartbeatTimeout).
+var ErrHeartbeatTimeout = errors.New("heartbeat timeout")
+
+// heartbeatKey is the context key under which the heartbeats
+// of an execution are delivered.
+type heartbeatKey struct{}
+
+// HeartbeatTimeout sets the maximum amount of time between
+// the heartbeats of an execution of a runnable, reported through Heartbeat
+// (default: 0, no heartbeats expected).
+//
+// The first heartbeat is expected within the timeout after the execution starts.
+// If a heartbeat does not arrive in time, the context of the execution
+// is cancelled (with ErrHeartbeatTimeout as its cause),
+// and it fails with ErrHeartbeatTimeout regardless of its outcome.
+func HeartbeatTimeout(d time.Duration) Option {
+	return func(o *options) *options {
+		o.heartbeat = d
+		return o
+	}
+}
+
+// Heartbeat reports that the execution of a runnable
+// with the provided context is alive.
+//
+// It has no effect if the context does not belong to an execution
+// expecting heartbeats.
+func Heartbeat(ctx context.Context) {
+	if w, ok := ctx.Value(heartbeatKey{}).(*watchdog); ok {
+		w.beat()
+	}
+}
+
+// watchdog cancels the context of an execution
+// if its heartbeats do not arrive in time.
+type watchdog struct {
+	timeout time.Duration
+	timer   Timer
+
+	// mu guards the status of the watchdog.
+	mu      sync.Mutex
+	expired bool
+	stopped bool
+}
+
+// watchdog creates a child of the provided context expecting heartbeats,
+// which is cancelled if one does not arrive in time, if applicable.
+// It returns the context along with a function stopping the watchdog,
+// which indicates whether a heartbeat did not arrive in time.
+func (o *options) watchdog(ctx context.Context) (context.Context, func() bool) {
+	if o == nil || o.heartbeat <= 0 {
+		return ctx, func() bool { return false }
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	w := &watchdog{timeout: o.heartbeat}
+	w.timer = o.clock().AfterFunc(w.timeout, func() {
+		w.mu.Lock()
+		w.expired = true
+		w.mu.Unlock()
+		cancel(ErrHeartbeatTimeout)
+	})
+
+	return context.WithValue(ctx, heartbeatKey{}, w), func() bool {
+		defer cancel(nil)
+		return w.stop()
+	}
+}
+
+// beat delays the expiry of a watchdog, unless it has expired or stopped.
+func (w *watchdog) beat() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.expired && !w.stopped {
+		w.timer.Reset(w.timeout)
+	}
+}
+
+// stop stops a watchdog, and indicates whether it has expired.
+func (w *watchdog) stop() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopped = true
+	w.timer.Stop()
+	return w.expired
+}
@@ -0,0 +1,66 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testHeartbeat(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"HeartbeatTimeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{HeartbeatTimeout(time.Second)})
+			as.Equal(&options{heartbeat: time.Second}, opts)
+		},
+		"heartbeats keep executions alive": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var execCtx context.Context
+			err := Do(context.TODO(), func(ctx context.Context) error {
+				execCtx = ctx
+				for n := 0; n < 6; n++ {
+					time.Sleep(testTimeDelta / 3)
+					Heartbeat(ctx)
+					Heartbeat(ctx)
+				}
+				return ctx.Err()
+			}, HeartbeatTimeout(testTimeDelta))
+			as.NoError(err)
+
+			// Heartbeats after the execution have no effect.
+			Heartbeat(execCtx)
+			as.Error(execCtx.Err())
+		},
+		"missing heartbeats fail executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}, HeartbeatTimeout(testTimeDelta),
+				Restart(true), RestartLimit(2, nil))
+
+			as.Equal([]error{ErrHeartbeatTimeout, ErrHeartbeatTimeout},
+				waitErrors(inst.Run(context.TODO())))
+		},
+		"late heartbeats": func(t *testing.T) {
+			as := newAssertions(t)
+
+			err := Do(context.TODO(), func(ctx context.Context) error {
+				time.Sleep(2 * testTimeDelta)
+				Heartbeat(ctx)
+				return nil
+			}, HeartbeatTimeout(testTimeDelta))
+			as.Equal(ErrHeartbeatTimeout, err)
+		},
+		"outside of an execution": func(t *testing.T) {
+			Heartbeat(context.TODO())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
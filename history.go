@@ -0,0 +1,77 @@
+package run
+
+import "time"
+
+// RunRecord represents a single execution of a runnable.
+type RunRecord struct {
+	// Attempt is the (1-based) number of the execution.
+	Attempt uint64
+	// StartedAt is the time the execution started.
+	StartedAt time.Time
+	// Duration is the duration of the execution.
+	Duration time.Duration
+	// Err is the error returned by the execution (if any).
+	Err error
+}
+
+// KeepHistory keeps a record of the latest n executions of a runnable,
+// retrievable through Instance.History (default: 0, keeping no history).
+func KeepHistory(n uint) Option {
+	return func(o *options) *options {
+		o.historySize = n
+		return o
+	}
+}
+
+// runHistory is a ring buffer of execution records.
+type runHistory struct {
+	records []RunRecord
+	// next is the position of the next record.
+	next int
+	// full indicates whether the buffer has wrapped around.
+	full bool
+}
+
+func newRunHistory(size uint) *runHistory {
+	return &runHistory{
+		records: make([]RunRecord, size),
+	}
+}
+
+// add records an execution, overwriting the oldest record if full.
+func (h *runHistory) add(rec RunRecord) {
+	if len(h.records) == 0 {
+		return
+	}
+
+	h.records[h.next] = rec
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns a copy of the records, from oldest to newest.
+func (h *runHistory) list() []RunRecord {
+	if !h.full {
+		return append([]RunRecord(nil), h.records[:h.next]...)
+	}
+
+	res := make([]RunRecord, 0, len(h.records))
+	res = append(res, h.records[h.next:]...)
+	return append(res, h.records[:h.next]...)
+}
+
+// History returns the records of the latest executions of an instance,
+// from oldest to newest, as configured by KeepHistory.
+//
+// It is safe to call while the instance is running.
+func (i *Instance) History() []RunRecord {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.history == nil {
+		return nil
+	}
+	return i.history.list()
+}
@@ -0,0 +1,79 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testHistory(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"ring buffer keeps latest records": func(t *testing.T) {
+			as := newAssertions(t)
+
+			h := newRunHistory(3)
+			as.Empty(h.list())
+
+			for n := uint64(1); n <= 2; n++ {
+				h.add(RunRecord{Attempt: n})
+			}
+			as.Equal([]RunRecord{{Attempt: 1}, {Attempt: 2}}, h.list())
+
+			for n := uint64(3); n <= 5; n++ {
+				h.add(RunRecord{Attempt: n})
+			}
+			as.Equal([]RunRecord{{Attempt: 3}, {Attempt: 4}, {Attempt: 5}}, h.list())
+		},
+		"empty ring buffer keeps nothing": func(t *testing.T) {
+			as := newAssertions(t)
+
+			h := newRunHistory(0)
+			h.add(RunRecord{Attempt: 1})
+
+			as.Empty(h.list())
+		},
+		"no history by default": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			})
+			waitErrors(inst.Run(context.TODO()))
+
+			as.Nil(inst.History())
+		},
+		"instance records executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			inst := New(func(context.Context) error {
+				calls++
+				if calls == 2 {
+					return testError(calls)
+				}
+				return nil
+			},
+				Recur(true),
+				RunLimit(3),
+				Restart(true),
+				RestartLimit(0, nil),
+				KeepHistory(2),
+			)
+
+			before := time.Now()
+			waitErrors(inst.Run(context.TODO()))
+			history := inst.History()
+
+			if as.Len(history, 2) {
+				as.Equal(uint64(3), history[0].Attempt)
+				as.Equal(uint64(4), history[1].Attempt)
+				as.Nil(history[1].Err)
+				as.WithinDuration(before, history[1].StartedAt, testTimeDelta)
+			}
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,230 @@
+// Package httpadmin exposes runnable instances and groups
+// over HTTP for inspection and administration.
+package httpadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Ale1ster/run"
+)
+
+// Status represents the status of a registered instance or group,
+// as reported by a handler.
+type Status struct {
+	// Name is the name the instance or group is registered under.
+	Name string `json:"name"`
+	// Instance is the status of an instance (see run.Instance.Snapshot),
+	// or nil for groups.
+	Instance *run.InstanceSnapshot `json:"instance,omitempty"`
+	// Group is the status of the members of a group
+	// (see run.Group.Snapshot), or nil for instances.
+	Group *run.GroupSnapshot `json:"group,omitempty"`
+}
+
+// Handler is an http.Handler exposing registered instances and groups:
+//
+//	GET  /                lists the status of all of them
+//	GET  /{name}          reports the status of one of them
+//	POST /{name}/stop     stops it
+//	POST /{name}/pause    pauses it (all members, for groups)
+//	POST /{name}/resume   resumes it (all members, for groups)
+//	POST /{name}/trigger  triggers its next execution (all members, for groups)
+//
+// Responses are JSON-encoded, with actions responding with the updated
+// status. Paths are relative to where the handler is mounted
+// (see http.StripPrefix), so names should not contain a slash.
+type Handler struct {
+	mu      sync.Mutex
+	targets map[string]target
+}
+
+// target represents a registered instance or group.
+type target interface {
+	status(name string) Status
+	Stop()
+	Pause()
+	Resume()
+	TriggerNow()
+}
+
+// New creates a new handler with no registered instances or groups.
+func New() *Handler {
+	return &Handler{targets: make(map[string]target)}
+}
+
+// AddInstance registers an instance under the provided name,
+// replacing any instance or group registered under it.
+func (h *Handler) AddInstance(name string, inst *run.Instance) {
+	h.add(name, instanceTarget{inst})
+}
+
+// AddGroup registers a group under the provided name,
+// replacing any instance or group registered under it.
+func (h *Handler) AddGroup(name string, g *run.Group) {
+	h.add(name, groupTarget{g})
+}
+
+// Remove unregisters the instance or group with the provided name.
+func (h *Handler) Remove(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.targets, name)
+}
+
+func (h *Handler) add(name string, t target) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.targets[name] = t
+}
+
+func (h *Handler) lookup(name string) (target, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.targets[name]
+	return t, ok
+}
+
+// ServeHTTP satisfies http.Handler interface for Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	if path == "" {
+		if !allow(w, r, http.MethodGet) {
+			return
+		}
+		h.list(w)
+		return
+	}
+
+	name, action, _ := strings.Cut(path, "/")
+	t, ok := h.lookup(name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown instance %q", name), http.StatusNotFound)
+		return
+	}
+
+	switch action {
+	case "":
+		if !allow(w, r, http.MethodGet) {
+			return
+		}
+	case "stop":
+		if !allow(w, r, http.MethodPost) {
+			return
+		}
+		t.Stop()
+	case "pause":
+		if !allow(w, r, http.MethodPost) {
+			return
+		}
+		t.Pause()
+	case "resume":
+		if !allow(w, r, http.MethodPost) {
+			return
+		}
+		t.Resume()
+	case "trigger":
+		if !allow(w, r, http.MethodPost) {
+			return
+		}
+		t.TriggerNow()
+	default:
+		http.Error(w, fmt.Sprintf("unknown action %q", action), http.StatusNotFound)
+		return
+	}
+	respond(w, t.status(name))
+}
+
+// list responds with the status of all registered instances and groups,
+// ordered by name.
+func (h *Handler) list(w http.ResponseWriter) {
+	h.mu.Lock()
+	names := make([]string, 0, len(h.targets))
+	targets := make(map[string]target, len(h.targets))
+	for name, t := range h.targets {
+		names = append(names, name)
+		targets[name] = t
+	}
+	h.mu.Unlock()
+
+	sort.Strings(names)
+	statuses := make([]Status, 0, len(names))
+	for _, name := range names {
+		statuses = append(statuses, targets[name].status(name))
+	}
+	respond(w, statuses)
+}
+
+// allow indicates whether the method of a request is the provided one,
+// responding with an error otherwise.
+func allow(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+// respond responds with the JSON encoding of the provided value.
+func respond(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// instanceTarget is a registered instance.
+type instanceTarget struct {
+	*run.Instance
+}
+
+func (t instanceTarget) status(name string) Status {
+	snapshot := t.Snapshot()
+	return Status{Name: name, Instance: &snapshot}
+}
+
+// groupTarget is a registered group.
+type groupTarget struct {
+	*run.Group
+}
+
+func (t groupTarget) status(name string) Status {
+	snapshot := t.Snapshot()
+	return Status{Name: name, Group: &snapshot}
+}
+
+func (t groupTarget) Pause() {
+	for _, inst := range t.members() {
+		inst.Pause()
+	}
+}
+
+func (t groupTarget) Resume() {
+	for _, inst := range t.members() {
+		inst.Resume()
+	}
+}
+
+func (t groupTarget) TriggerNow() {
+	for _, inst := range t.members() {
+		inst.TriggerNow()
+	}
+}
+
+// members returns the instances of the members of a group, in order.
+func (t groupTarget) members() []*run.Instance {
+	var members []*run.Instance
+	for _, name := range t.Members() {
+		// Members may be removed in the meantime.
+		if inst, ok := t.Member(name); ok {
+			members = append(members, inst)
+		}
+	}
+	return members
+}
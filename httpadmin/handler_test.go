@@ -0,0 +1,158 @@
+package httpadmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ale1ster/run"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(h http.Handler, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), v))
+}
+
+func status(t *testing.T, rec *httptest.ResponseRecorder) Status {
+	t.Helper()
+	var st Status
+	decode(t, rec, &st)
+	return st
+}
+
+func TestHandlerInstance(t *testing.T) {
+	as := assert.New(t)
+
+	inst := run.New(func(context.Context) error {
+		return errors.New("failed")
+	}, run.Restart(true), run.RestartLimit(0, run.ConstantBackoff(time.Hour)))
+
+	h := New()
+	h.AddInstance("job", &inst)
+
+	var statuses []Status
+	decode(t, serve(h, http.MethodGet, "/"), &statuses)
+	as.Equal([]Status{{Name: "job", Instance: &run.InstanceSnapshot{}}}, statuses)
+
+	errCh := inst.Run(context.TODO())
+	<-errCh
+
+	st := status(t, serve(h, http.MethodGet, "/job"))
+	as.Equal("job", st.Name)
+	as.Nil(st.Group)
+	as.Equal(uint64(1), st.Instance.Runs)
+	as.Equal(uint64(1), st.Instance.FailedRuns)
+	as.Equal("failed", st.Instance.LastError)
+	as.NotZero(st.Instance.LastDuration)
+
+	st = status(t, serve(h, http.MethodPost, "/job/pause"))
+	as.True(st.Instance.Paused)
+	st = status(t, serve(h, http.MethodPost, "/job/resume/"))
+	as.False(st.Instance.Paused)
+
+	// Skips the backoff period.
+	status(t, serve(h, http.MethodPost, "/job/trigger"))
+	<-errCh
+	as.Eventually(func() bool {
+		return inst.State() == run.StateBackingOff
+	}, time.Second, time.Millisecond)
+	st = status(t, serve(h, http.MethodGet, "/job"))
+	as.Equal(uint64(2), st.Instance.Runs)
+
+	status(t, serve(h, http.MethodPost, "/job/stop"))
+	for range errCh {
+	}
+	st = status(t, serve(h, http.MethodGet, "/job"))
+	as.Equal(run.StateStopped, st.Instance.State)
+	as.Nil(st.Instance.NextRun)
+
+	h.Remove("job")
+	as.Equal(http.StatusNotFound, serve(h, http.MethodGet, "/job").Code)
+}
+
+func TestHandlerGroup(t *testing.T) {
+	as := assert.New(t)
+
+	g := run.NewGroup()
+	as.NoError(g.Add("a", func(context.Context) error {
+		return nil
+	}, run.Recur(true), run.Period(time.Hour)))
+	as.NoError(g.Add("b", func(context.Context) error {
+		return nil
+	}))
+
+	h := New()
+	h.AddGroup("group", g)
+
+	errCh := g.Run(context.TODO())
+	a, _ := g.Member("a")
+	b, _ := g.Member("b")
+	as.Eventually(func() bool {
+		return a.State() == run.StateWaitingPeriod &&
+			b.State() == run.StateTerminated
+	}, time.Second, time.Millisecond)
+
+	st := status(t, serve(h, http.MethodPost, "/group/pause"))
+	as.Equal("group", st.Name)
+	as.Nil(st.Instance)
+	if as.NotNil(st.Group) && as.Len(st.Group.Members, 2) {
+		as.Equal("a", st.Group.Members[0].Name)
+		as.True(st.Group.Members[0].Paused)
+		as.NotNil(st.Group.Members[0].NextRun)
+		as.Equal(uint64(1), st.Group.Members[1].Runs)
+		as.NotNil(st.Group.Members[1].LastSuccess)
+	}
+
+	st = status(t, serve(h, http.MethodPost, "/group/resume"))
+	as.False(st.Group.Members[0].Paused)
+
+	status(t, serve(h, http.MethodPost, "/group/trigger"))
+	as.Eventually(func() bool {
+		return a.Stats().Runs == 2
+	}, time.Second, time.Millisecond)
+
+	status(t, serve(h, http.MethodPost, "/group/stop"))
+	for range errCh {
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	as := assert.New(t)
+
+	inst := run.New(func(context.Context) error {
+		return nil
+	})
+	h := New()
+	h.AddInstance("job", &inst)
+
+	rec := serve(h, http.MethodPost, "/")
+	as.Equal(http.StatusMethodNotAllowed, rec.Code)
+	as.Equal(http.MethodGet, rec.Header().Get("Allow"))
+
+	for _, path := range []string{"/job", "/job/stop", "/job/pause", "/job/resume",
+		"/job/trigger"} {
+		method := http.MethodPost
+		if path == "/job" {
+			method = http.MethodGet
+		}
+		rec = serve(h, http.MethodPut, path)
+		as.Equal(http.StatusMethodNotAllowed, rec.Code, path)
+		as.Equal(method, rec.Header().Get("Allow"), path)
+	}
+
+	as.Equal(http.StatusNotFound, serve(h, http.MethodGet, "/other").Code)
+	as.Equal(http.StatusNotFound, serve(h, http.MethodPost, "/job/restart").Code)
+}
@@ -0,0 +1,27 @@
+package httpadmin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Ale1ster/run"
+)
+
+// HealthHandler returns an http.Handler reporting the health of the members
+// of a group (see run.Group.HealthReport), suitable for liveness
+// and readiness probes: it responds with the JSON-encoded report,
+// with status 200 if all members are healthy, or 503 otherwise.
+func HealthHandler(g *run.Group) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allow(w, r, http.MethodGet) {
+			return
+		}
+
+		report := g.HealthReport()
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,43 @@
+package httpadmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Ale1ster/run"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHandler(t *testing.T) {
+	as := assert.New(t)
+
+	g := run.NewGroup()
+	as.NoError(g.Add("a", func(context.Context) error {
+		return errors.New("failed")
+	}, run.HealthRules(run.MaxConsecutiveFailures(1))))
+	h := HealthHandler(g)
+
+	var report run.GroupHealthReport
+	decode(t, serve(h, http.MethodGet, "/"), &report)
+	as.True(report.Healthy)
+
+	errCh := g.Run(context.TODO())
+	<-errCh
+	g.Stop()
+	for range errCh {
+	}
+
+	rec := serve(h, http.MethodGet, "/")
+	as.Equal(http.StatusServiceUnavailable, rec.Code)
+	as.NoError(json.Unmarshal(rec.Body.Bytes(), &report))
+	as.Equal(run.GroupHealthReport{
+		Members: map[string]run.HealthReport{
+			"a": {Problems: []string{"1 consecutive failed executions"}},
+		},
+	}, report)
+
+	as.Equal(http.StatusMethodNotAllowed, serve(h, http.MethodPost, "/").Code)
+}
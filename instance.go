@@ -2,7 +2,10 @@ package run
 
 import (
 	"context"
+	"errors"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -10,130 +13,861 @@ import (
 type Instance struct {
 	r    Runnable
 	opts *options
+	// updated (if set) holds the options of an instance
+	// as updated after its creation, superseding opts (see Update).
+	updated atomic.Pointer[options]
+
+	// mu guards the execution statistics of an instance,
+	// which can be accessed while it is running.
+	mu    sync.Mutex
+	stats Stats
+	// since is the time of the latest state transition of the instance.
+	since   time.Time
+	history *runHistory
+	// rate (if set) holds the recent outcomes of the instance
+	// (see FailureRateAlarm).
+	rate     *failureRate
+	watchers []chan StateTransition
+	// subscribers receive the events or errors of the instance
+	// until it terminates (see Subscribe).
+	subscribers []subscription
 
 	// runs and failedRuns keep track of the number of
 	// successful and failed executions of a runnable respectively.
 	// failedRuns may be reset after a successful execution,
 	// depending on restart options.
-	runs, failedRuns uint64
+	// attempts keeps track of the number of all executions.
+	runs, failedRuns, attempts atomic.Uint64
+	// timeouts keeps track of the number of consecutive timed out executions,
+	// if they are accounted separately (see TimeoutRestartLimit).
+	timeouts atomic.Uint64
+	// leaked is the number of abandoned executions still running,
+	// which is retained across resets (see AbandonAfter).
+	leaked atomic.Uint64
+
+	// stopped indicates whether the instance has been stopped,
+	// with cancel interrupting its execution
+	// and stopCh (if set) closed once it is.
+	stopped bool
+	cancel  context.CancelCauseFunc
+	stopCh  chan struct{}
+
+	// ready and done are closed once the instance
+	// becomes ready and terminates respectively.
+	ready, done chan struct{}
+	// resumed (if set) is closed once the paused instance is resumed.
+	resumed chan struct{}
+	// trigger (if set) holds a pending trigger of the instance,
+	// while lastTriggered is the time the latest execution started
+	// by its trigger channel started (see Throttle).
+	trigger       chan struct{}
+	lastTriggered time.Time
+	// restored is the time of the next execution of the instance
+	// restored from its store (see WithStore).
+	restored time.Time
+
+	// err is the terminal error of the instance, once it terminates,
+	// and ended is the reason it did.
+	err   error
+	ended TerminationReason
+
+	// finalize (if set) is called once the instance terminates.
+	finalize func()
+	// relay (if set) propagates the errors of the children of the instance
+	// while it runs (see BubbleErrors).
+	relay func(Event)
 
 	once sync.Once
 }
 
 // Run runs an instance in a goroutine and returns a channel
 // where any encountered (non-nil) errors are propagated.
-// An instance can be run at most once,
-// with subsequent attempts returning a nil channel.
+// An instance can be run at most once (unless reset, see Reset),
+// with subsequent attempts returning a nil channel (see TryRun).
 func (i *Instance) Run(ctx context.Context) <-chan error {
 	return i.run(ctx)
 }
 
+// RunBlocking runs an instance on the calling goroutine, blocking until
+// it terminates, and returns its final error as Do does.
+// It is an alternative to Run for embedders managing their own goroutines,
+// with the errors Run would propagate delivered to the error handler
+// of the instance (see OnError) instead, or discarded if it has none.
+//
+// Only the additional copies of a runnable executing concurrently
+// (see Concurrency) and executions which may be abandoned (see StopGrace)
+// run on separate goroutines.
+// It returns ErrAlreadyRunning if the instance has already been run.
+func (i *Instance) RunBlocking(ctx context.Context) error {
+	first := false
+	i.once.Do(func() { first = true })
+	if !first {
+		return ErrAlreadyRunning
+	}
+
+	handle := i.options().errorHandler()
+	propagate, flush := i.options().dedupe(func(err error) {
+		if err != nil && handle != nil {
+			handle(err)
+		}
+	})
+	var out outcome
+	i.execute(ctx, func(ev Event) {
+		out.observe(ev)
+		propagate(eventError(ev))
+	})
+	flush()
+	if i.options().reportsTermination() {
+		propagate(i.TerminationReason())
+	}
+	return out.err
+}
+
+// ErrAlreadyRunning is returned when running an instance
+// that has already been run (whether or not it has terminated since).
+var ErrAlreadyRunning = errors.New("instance already running")
+
+// TryRun runs an instance similarly to Run, but returns ErrAlreadyRunning
+// instead of a nil channel if the instance has already been run
+// (using either Run, Events, TryRun or RunBlocking),
+// and it has not been reset since.
+func (i *Instance) TryRun(ctx context.Context) (<-chan error, error) {
+	errCh := i.run(ctx)
+	if errCh == nil {
+		return nil, ErrAlreadyRunning
+	}
+	return errCh, nil
+}
+
+// Events runs an instance in a goroutine and returns a channel
+// where all events of its execution are propagated,
+// with the last one always being Terminated
+// (unless the runnable panics without recovery).
+// It is an alternative to Run, so an instance can be run
+// at most once using either of them (unless reset, see Reset),
+// with subsequent attempts returning a nil channel.
+//
+// The channel buffer size is controlled by WithChanBuffer,
+// similarly to the error channel returned by Run.
+func (i *Instance) Events(ctx context.Context) <-chan Event {
+	var evCh chan Event
+
+	i.once.Do(func() {
+		evCh = make(chan Event, i.options().chanSize())
+
+		go func() {
+			defer close(evCh)
+			i.execute(ctx, func(ev Event) {
+				evCh <- ev
+			})
+		}()
+	})
+
+	return evCh
+}
+
 func (i *Instance) run(ctx context.Context) <-chan error {
 	var errCh chan error
 
 	i.once.Do(func() {
-		var chanSize uint
-		if i.opts != nil {
-			chanSize = i.opts.errChanSize
-		}
-		errCh = make(chan error, chanSize)
+		errCh = make(chan error, i.options().errBufferSize())
 
-		go i.runCh(ctx, errCh)
+		handle := i.options().errorHandler()
+		propagate, flush := i.options().dedupe(func(err error) {
+			switch {
+			case err == nil:
+			case handle != nil:
+				handle(err)
+			default:
+				i.deliver(errCh, err)
+			}
+		})
+		go func() {
+			defer close(errCh)
+			i.execute(ctx, func(ev Event) {
+				propagate(eventError(ev))
+			})
+			flush()
+			if i.options().reportsTermination() {
+				propagate(i.TerminationReason())
+			}
+		}()
 	})
 
 	return errCh
 }
 
-// runCh controls the execution of an instance based on its options
-// and propagates the returned errors to the provided channel.
-func (i *Instance) runCh(ctx context.Context, errCh chan<- error) {
-	defer close(errCh)
-	// Defer recovery if the appropriate option is set.
-	if i.opts.calm() {
+// execute controls the execution of an instance based on its options
+// and propagates its events to the provided function.
+func (i *Instance) execute(ctx context.Context, sink func(Event)) {
+	if i.finalize != nil {
+		defer i.finalize()
+	}
+
+	defer i.markDone()
+	unregister, conflict := i.register()
+	defer unregister()
+
+	ctx, cancel := i.withStop(ctx)
+	defer cancel()
+	defer i.follow()()
+	ctx, expire := i.withTotalTimeout(ctx)
+	defer expire()
+	ctx = context.WithValue(ctx, readyKey{}, i)
+	ctx = context.WithValue(ctx, registryKey{}, i)
+
+	i.mu.Lock()
+	i.stats.StartedAt = i.options().clock().Now()
+	i.mu.Unlock()
+
+	// Events of concurrent copies of the runnable are serialized,
+	// with their errors logged for the terminal error.
+	var mu sync.Mutex
+	var errs errorLog
+	terminated := false
+	metrics := i.options().measure()
+	emit := func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		err := eventError(ev)
+		if err != nil {
+			errs.add(err)
+		}
+		_, terminated = ev.(Terminated)
+		metrics.observe(ev)
+		sink(ev)
+		i.publish(ev)
+		i.bubble(err)
+	}
+	// Errors of children are not part of the terminal error,
+	// and are discarded once the instance terminates.
+	defer i.adopt(func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !terminated {
+			sink(ev)
+			i.publish(ev)
+		}
+	})()
+	// terminate records the terminal error of the instance
+	// before emitting the final event.
+	terminate := func(reason error, ended TerminationReason) {
+		mu.Lock()
+		final := append(errs.errors(), reason, ended)
+		mu.Unlock()
+
+		i.mu.Lock()
+		i.err, i.ended = errors.Join(final...), ended
+		i.mu.Unlock()
+		emit(Terminated{Reason: reason})
+	}
+
+	if conflict != nil {
+		i.schedule(i.finalState(), 0)
+		terminate(conflict, NameConflict)
+		return
+	}
+
+	reason, ended, episode := i.work(ctx, cancel, emit)
+	if episode != nil {
+		i.schedule(i.finalState(), 0)
+		emit(Recovered{Panic: episode})
+		terminate(RunnablePanic{Value: episode}, Panicked)
+		return
+	}
+
+	state := i.finalState()
+	switch {
+	case state == StateStopped:
+		// Stopping an instance is not an error.
+		reason, ended = nil, Stopped
+	case errors.As(reason, new(StoreError)):
+		ended = StoreFailed
+	case reason == ErrTotalTimeout && ctx.Err() != nil:
+		ended = TotalTimeoutExceeded
+	case reason != nil && ctx.Err() != nil:
+		ended = ContextCancelled
+	case reason != nil:
+		ended = Limited
+	}
+	i.schedule(state, 0)
+	terminate(reason, ended)
+}
+
+// worker holds the execution state of a copy of the runnable of an instance.
+type worker struct {
+	r Runnable
+
+	// halted is closed once the limits of the instance are reached,
+	// with halt closing it.
+	halted <-chan struct{}
+	halt   func()
+
+	// tick is the time the latest execution of a recurring runnable
+	// in fixed-rate mode was due at.
+	tick time.Time
+	// behind (if set) is the time the missed execution a restored
+	// runnable is catching up with was due at (see CatchUp).
+	behind time.Time
+	// started is the time the latest execution started,
+	// with attempt describing it.
+	started time.Time
+	attempt Attempt
+	// failures is the number of consecutive failed executions
+	// of the instance, as of the latest execution.
+	failures uint64
+	// denied indicates whether the retry budget of the instance
+	// denied a restart after the latest execution.
+	denied bool
+	// stack is the stack trace of the latest execution,
+	// if it panicked and the panic was converted (see RecoverWith).
+	stack []byte
+	// ended is the reason the copy terminated on its own (if it did),
+	// rather than being halted or interrupted.
+	ended TerminationReason
+}
+
+// work executes the copies of the runnable of an instance concurrently
+// (the first one on the calling goroutine), until all of them terminate.
+// It returns the context error in case of cancellation
+// (or the reason the first copy to terminate on its own did so),
+// or the value of the first panic recovered from, if any,
+// in which case the remaining copies are cancelled.
+func (i *Instance) work(ctx context.Context, cancel context.CancelFunc,
+	emit func(Event)) (reason error, ended TerminationReason,
+	episode interface{}) {
+
+	if size := i.options().keepHistory(); size != 0 {
+		i.mu.Lock()
+		i.history = newRunHistory(size)
+		i.mu.Unlock()
+	}
+	if err := i.restore(ctx); err != nil {
+		return err, 0, nil
+	}
+
+	halted := make(chan struct{})
+	var once sync.Once
+	halt := func() {
+		once.Do(func() { close(halted) })
+	}
+
+	// The runnable is wrapped once, with its copies sharing the middleware.
+	wrapped := i.options().wrap(i.r)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	run := func() {
+		w := &worker{r: wrapped, halted: halted, halt: halt}
+		err, v := i.supervise(ctx, w, emit)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if v != nil && episode == nil {
+			episode = v
+			cancel()
+		}
+		if err != nil && reason == nil {
+			reason = err
+		}
+		if w.ended != 0 && ended == 0 {
+			ended = w.ended
+		}
+	}
+
+	for n := i.options().workers(); n > 1; n-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run()
+		}()
+	}
+	run()
+	wg.Wait()
+
+	return reason, ended, episode
+}
+
+// supervise executes a copy of the runnable of an instance,
+// recovering from panic if the appropriate option is set.
+func (i *Instance) supervise(ctx context.Context, w *worker,
+	emit func(Event)) (reason error, episode interface{}) {
+
+	opts := i.options()
+	if opts.calm() {
 		defer func() {
-			if episode := recover(); episode != nil {
-				errCh <- RunnablePanic{Value: episode}
+			if episode = recover(); episode != nil {
+				elapsed := opts.clock().Now().Sub(w.started)
+				i.account(RunnablePanic{Value: episode}, w.started, elapsed)
+				i.report(ctx, RunnablePanic{Value: episode}, w, elapsed, debug.Stack())
 			}
 		}()
 	}
 
-	var err error
-	var after time.Duration
-	for rerun := true; rerun; rerun, after = i.rerun(err) {
-		// Wait for timeout between executions.
-		// Note: No delay on first execution,
-		//   since initial timeout value is zero.
+	return i.loop(ctx, w, emit), nil
+}
+
+// loop executes a copy of the runnable of an instance for as long as
+// its options dictate, emitting the events of each execution,
+// and returns the context error in case of cancellation
+// (or the limiter error, in case it prevents an execution).
+func (i *Instance) loop(ctx context.Context, w *worker, emit func(Event)) error {
+	opts := i.options()
+	clock := opts.clock()
+	// Note: No delay on first execution, unless delayed or scheduled.
+	after, ok := opts.firstRun(clock.Now())
+	switch next, reached := i.restoredRun(); {
+	case reached:
+		w.ended = RunLimitReached
+		return nil
+	case !next.IsZero():
+		// Resume the schedule of a restored instance,
+		// catching up with the executions it missed.
+		var missed uint64
+		after, w.behind, missed, ok = i.catchUp(clock.Now(), next)
+		if missed != 0 {
+			emit(RunsMissed{Count: missed})
+		}
+	}
+	if !ok {
+		w.ended = Completed
+		return nil
+	}
+	switch {
+	case opts.awaitsTriggers():
+		after = untimed
+	default:
+		after = opts.windowed(clock.Now(), after)
+	}
+	attempt := Attempt{
+		Number:      1,
+		ScheduledAt: i.schedule(StateIdle, after),
+	}
+	w.tick = attempt.ScheduledAt
+	// The timer of the waits between executions is reused.
+	var timer Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for {
+		// Options updated while running apply from the next iteration.
+		opts = i.options()
+		// Avoid executing if already cancelled or halted,
+		// since select does not prioritise between ready cases.
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		select {
+		case <-w.halted:
+			return nil
+		default:
+		}
+		// Wait for timeout between executions, unless triggered.
+		var expiry <-chan time.Time
+		if after != untimed {
+			timer = rearm(clock, timer, after)
+			expiry = timer.C()
+		}
 		select {
 		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-w.halted:
+			return nil
+		case <-i.triggered():
+		case _, ok := <-opts.triggers():
+			if !ok {
+				w.ended = Completed
+				return nil
+			}
+			opts.coalesce(opts.triggers())
+			if proceed, err := i.settle(ctx, w); !proceed {
+				return err
+			}
+		case <-expiry:
+		}
+		if after == untimed {
+			attempt.ScheduledAt = clock.Now()
+		}
+		// Wait for resumption, if paused.
+		if resumed := i.resumption(); resumed != nil {
+			select {
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			case <-w.halted:
+				return nil
+			case <-resumed:
+			}
+		}
+		if err := opts.limit(ctx); err != nil {
 			if ctx.Err() != nil {
-				errCh <- ctx.Err()
+				return context.Cause(ctx)
 			}
-			return
-		case <-time.After(after):
+			return err
 		}
 
-		// Anonymous function to allow for immediate execution
-		// of deferred context cancellation.
-		err = func() error {
-			ctxt, cancel := i.withContextTimeout(ctx)
-			defer cancel()
+		// Skipped executions are rescheduled as successful ones would be,
+		// while a failed precondition or lock fails the execution.
+		var skip error
+		admitted, err := opts.admit(withAttempt(ctx, attempt))
+		if err == nil && admitted {
+			admitted, err = opts.lead(withAttempt(ctx, attempt), attempt)
+			skip = ErrNotLeader
+		}
+		if err == nil && !admitted {
+			emit(RunSkipped{Reason: skip})
+			var rerun bool
+			if rerun, after, _ = i.next(w); !rerun {
+				w.ended = Completed
+				return nil
+			}
+			if after != untimed {
+				after = opts.windowed(clock.Now(), after)
+			}
+			attempt.ScheduledAt = i.schedule(StateWaitingPeriod, after)
+			continue
+		}
+		if err == nil {
+			err = opts.acquire(withAttempt(ctx, attempt))
+		}
+		if err != nil && ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
 
-			return i.r.run(ctxt)
-		}()
-		if err != nil {
-			errCh <- err
+		w.started, w.attempt = clock.Now(), attempt
+		abandoned := false
+		if err == nil {
+			i.schedule(StateRunning, 0)
+			emit(RunStarted{})
+			if !opts.awaitsReady() {
+				i.markReady()
+			}
+			err, abandoned = i.execution(ctx, w, attempt, emit)
 		}
+		elapsed := clock.Now().Sub(w.started)
+		if abandoned && ctx.Err() != nil {
+			emit(RunAbandoned{Duration: elapsed})
+			return context.Cause(ctx)
+		}
+		if abandoned {
+			err = ErrRunAbandoned
+		}
+
+		var rerun bool
+		var missed uint64
+		rerun, after, missed = i.rerun(err, elapsed, w)
+		i.account(err, w.started, elapsed)
+
+		switch {
+		case err == nil:
+			emit(RunSucceeded{Duration: elapsed})
+		case abandoned:
+			emit(RunAbandoned{Duration: elapsed})
+			i.report(ctx, err, w, elapsed, nil)
+		default:
+			failure := opts.annotate(err, attempt, w.started, elapsed)
+			emit(RunFailed{Err: failure, Duration: elapsed})
+			i.report(ctx, failure, w, elapsed, w.stack)
+		}
+		i.monitor(err, w.started.Add(elapsed), emit)
+
+		if !rerun {
+			if serr := i.persist(ctx, w.started); serr != nil {
+				return serr
+			}
+			w.ended = i.termination(err, w)
+			if w.denied {
+				emit(RetryDenied{})
+			}
+			if i.exhausted() {
+				w.halt()
+			}
+			return nil
+		}
+		if missed != 0 {
+			emit(RunsMissed{Count: missed})
+		}
+		if after != untimed {
+			after = opts.windowed(clock.Now(), after)
+		}
+
+		var next time.Time
+		switch err {
+		case nil:
+			next = i.schedule(StateWaitingPeriod, after)
+		default:
+			next = i.schedule(StateBackingOff, after)
+			emit(BackoffStarted{Delay: after})
+		}
+		if serr := i.persist(ctx, w.started); serr != nil {
+			return serr
+		}
+
+		attempt = Attempt{
+			Number:              attempt.Number + 1,
+			ConsecutiveFailures: w.failures,
+			PreviousErr:         err,
+			ScheduledAt:         next,
+		}
+	}
+}
+
+// execution executes a copy of the runnable of an instance once,
+// as the provided attempt, emitting the progress it reports,
+// and returns its error and whether it was abandoned,
+// releasing its gate afterwards.
+func (i *Instance) execution(ctx context.Context, w *worker,
+	attempt Attempt, emit func(Event)) (err error, abandoned bool) {
+
+	opts := i.options()
+	base, release := i.detach(ctx)
+	defer release()
+	var returned <-chan struct{}
+	defer func() {
+		if abandoned {
+			// The gate is held until an abandoned execution returns,
+			// so that it does not overlap with the ones guarded by it
+			// (discarding the error of releasing it).
+			go func() {
+				<-returned
+				_ = opts.release(base)
+			}()
+			return
+		}
+		// The gate is released even if the execution panics,
+		// while its error fails only successful executions.
+		if gerr := opts.release(base); err == nil {
+			err = gerr
+		}
+	}()
+	ctxt, cancel := i.withContextTimeout(
+		opts.runContext(withAttempt(base, attempt), attempt), attempt)
+	defer cancel()
+	ctxt, expired := opts.watchdog(ctxt)
+	site := &panicSite{}
+	ctxt, spawned := withTasks(ctxt, site)
+	ctxt, ended := i.withProgress(ctxt, emit)
+	defer ended()
+
+	finished := opts.watchSlow(attempt, w.started, emit)
+	responded := opts.watchUnresponsive(ctxt, attempt, emit)
+	w.stack = nil
+	opts.profile(ctxt, attempt, func(ctxt context.Context) {
+		// Panics propagated by the goroutines of the execution
+		// are converted alike.
+		defer opts.convert(w, site, emit, &err)
+		if err, returned = i.invoke(base, ctxt, w, site); returned == nil {
+			err = spawned.join(err)
+		}
+		abandoned = returned != nil
+	})
+	responded()
+	finished()
+	if !abandoned {
+		err = opts.validate(ctxt, err)
+	}
+	switch {
+	case expired() && !abandoned:
+		err = ErrHeartbeatTimeout
+	case !abandoned:
+		err = timedOut(err, ctxt)
 	}
+	return opts.ignored(err), abandoned
 }
 
-// rerun indicates whether a runnable should run again after termination
-// according to its options, as well as the delay after which it will.
-// It should be provided with the return value of the previous execution.
-func (i *Instance) rerun(err error) (rerun bool, after time.Duration) {
-	if i.opts == nil {
+// rerun indicates whether a copy of a runnable should run again
+// after termination according to its options, as well as the delay
+// after which it will and the number of executions skipped in fixed-rate mode.
+// It should be provided with the return value and duration
+// of the previous execution.
+func (i *Instance) rerun(err error, elapsed time.Duration, w *worker) (
+	rerun bool, after time.Duration, missed uint64) {
+
+	opts := i.options()
+	if opts == nil {
 		return
 	}
 
+	runs, failedRuns := i.count(err, elapsed)
+	w.failures = i.failedRuns.Load() + i.timeouts.Load()
+	if i.attemptsExhausted() {
+		return false, 0, 0
+	}
+
 	switch err {
 	case nil:
-		// Account for the current execution.
-		i.runs++
-		// If applicable, reset failure count.
-		if i.opts.restartable.restartOnError {
-			i.failedRuns = 0
-		}
 		// Check recurrence options, since execution was successful.
-		if i.opts.recurring.recur {
-			rerun, after = true, i.opts.recurring.period
-		}
-		// Run limit makes sense only if recurring.
-		cOpts := i.opts.constrained
-		if cOpts.runLimit != 0 && i.runs >= cOpts.runLimit {
-			return false, 0
+		rerun, after, missed = i.next(w)
+		// Run limit makes sense only if rerunning.
+		cOpts := opts.constrained
+		if cOpts.runLimit != 0 && runs >= cOpts.runLimit {
+			return false, 0, 0
 		}
 	default:
-		// Account for the failed execution.
-		i.failedRuns++
 		// Only restart options are applicable after failed execution.
-		if rOpts := i.opts.restartable; rOpts.restartOnError {
-			failLimit := rOpts.restartLimit
-			if failLimit == 0 || i.failedRuns < failLimit {
-				return true, rOpts.backoff(i.failedRuns)
+		if rOpts := opts.restartable; opts.restarts(err) {
+			failLimit, timeout := rOpts.restartLimit, rOpts.separates(err)
+			if timeout {
+				failLimit = rOpts.timeoutLimit
+			}
+			if failLimit == 0 || failedRuns < failLimit {
+				if !opts.allowRetry() {
+					w.denied = true
+					return false, 0, 0
+				}
+				if timeout {
+					return true, rOpts.timeoutBackoff(failedRuns), 0
+				}
+				return true, rOpts.delay(failedRuns), 0
 			}
 		}
 	}
 	return
 }
 
+// next indicates whether a copy of a runnable is due to run again
+// according to its recurrence options, as well as the delay after which
+// it will and the number of executions skipped in fixed-rate mode.
+// It should only be called for instances with options.
+func (i *Instance) next(w *worker) (rerun bool, after time.Duration, missed uint64) {
+	opts := i.options()
+	switch rOpts := opts.recurring; {
+	case rOpts.recur && !w.behind.IsZero():
+		after, w.behind, rerun = rOpts.caughtUp(opts.clock().Now(), w.behind)
+	case rOpts.recur && rOpts.fixedRate && rOpts.schedule == nil:
+		rerun = true
+		after, w.tick, missed = rOpts.nextTick(opts.clock().Now(), w.tick)
+	case rOpts.recur:
+		after, rerun = rOpts.next(opts.clock().Now())
+	case opts.awaitsTriggers():
+		rerun, after = true, untimed
+	}
+	return
+}
+
+// count accounts for an execution of the runnable of an instance,
+// provided with its return value and duration, and returns the updated number
+// of successful and (consecutive) failed executions,
+// the latter being timed out ones if accounted separately.
+func (i *Instance) count(err error, elapsed time.Duration) (
+	runs, failedRuns uint64) {
+
+	opts := i.options()
+	i.attempts.Add(1)
+	switch err {
+	case nil:
+		runs = i.runs.Add(1)
+		// If applicable, reset failure count.
+		if opts.restartable.restartOnError {
+			i.failedRuns.Store(0)
+			i.timeouts.Store(0)
+		}
+		return runs, i.failedRuns.Load()
+	default:
+		failures := &i.failedRuns
+		if opts.restartable.separates(err) {
+			failures = &i.timeouts
+		}
+		// If applicable, a stable execution resets the failure count.
+		if stable := opts.restartable.stableAfter; stable > 0 &&
+			elapsed >= stable {
+			failures.Store(0)
+		}
+		return i.runs.Load(), failures.Add(1)
+	}
+}
+
+// exhausted indicates whether the run, attempt or restart limit
+// of an instance has been reached.
+func (i *Instance) exhausted() bool {
+	opts := i.options()
+	if opts == nil {
+		return false
+	}
+
+	cOpts, rOpts := opts.constrained, opts.restartable
+	return (cOpts.runLimit != 0 && i.runs.Load() >= cOpts.runLimit) ||
+		(cOpts.attemptLimit != 0 && i.attempts.Load() >= cOpts.attemptLimit) ||
+		(rOpts.restartOnError && rOpts.restartLimit != 0 &&
+			i.failedRuns.Load() >= rOpts.restartLimit) ||
+		(rOpts.restartOnError && rOpts.timeoutLimit != 0 &&
+			rOpts.timeoutBackoff != nil && i.timeouts.Load() >= rOpts.timeoutLimit)
+}
+
+// attemptsExhausted indicates whether the attempt limit
+// of an instance has been reached.
+func (i *Instance) attemptsExhausted() bool {
+	opts := i.options()
+	if opts == nil {
+		return false
+	}
+
+	limit := opts.constrained.attemptLimit
+	return limit != 0 && i.attempts.Load() >= limit
+}
+
 // withContextTimeout creates a child of the provided context,
-// applying timeout if applicable,
+// applying the timeout of the provided attempt if applicable,
 // and returns it along with its cancellation function.
-func (i *Instance) withContextTimeout(ctx context.Context) (
+func (i *Instance) withContextTimeout(ctx context.Context, attempt Attempt) (
 	context.Context, context.CancelFunc) {
 
-	if i.opts != nil && i.opts.constrained.timeout != 0 {
-		timeout := i.opts.constrained.timeout
-		return context.WithTimeout(ctx, timeout)
+	opts := i.options()
+	if timeout := opts.runTimeout(attempt); timeout != 0 {
+		return withClockTimeout(ctx, opts.clock(), timeout, ErrRunTimeout)
 	}
 	return context.WithCancel(ctx)
 }
+
+// ErrStopped is the cause of the cancellation of the context
+// of an instance that is stopped (see context.Cause).
+var ErrStopped = errors.New("instance stopped")
+
+// Stop stops an instance, cancelling the context of any ongoing execution
+// (with ErrStopped as its cause)
+// and terminating it without error, with StateStopped as its final state.
+// Stopping an instance before it runs makes it terminate immediately.
+//
+// Stop does not wait for the instance to terminate.
+func (i *Instance) Stop() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.stopped && i.stopCh != nil {
+		close(i.stopCh)
+	}
+	i.stopped = true
+	if i.cancel != nil {
+		i.cancel(ErrStopped)
+	}
+}
+
+// withStop creates a child of the provided context,
+// which is cancelled when the instance is stopped.
+func (i *Instance) withStop(ctx context.Context) (
+	context.Context, context.CancelFunc) {
+
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.cancel = cancel
+	if i.stopped {
+		cancel(ErrStopped)
+	}
+	return ctx, func() { cancel(nil) }
+}
+
+// finalState returns the state of an instance once it terminates.
+func (i *Instance) finalState() State {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.stopped {
+		return StateStopped
+	}
+	return StateTerminated
+}
@@ -0,0 +1,34 @@
+package run
+
+import "context"
+
+// Limiter gates the executions of runnables.
+//
+// It is satisfied by rate.Limiter (golang.org/x/time/rate).
+type Limiter interface {
+	// Wait blocks until an execution is permitted,
+	// or returns an error if it cannot be.
+	Wait(ctx context.Context) error
+}
+
+// WithLimiter gates each execution of a runnable by the provided limiter
+// (default: nil, no limiter), which can be shared among instances
+// (or the copies of a runnable) to limit their combined rate of executions.
+//
+// Waiting for the limiter takes place after any period or backoff,
+// and does not count towards the duration or timeout of executions.
+// If the limiter returns an error, the instance terminates with it as reason.
+func WithLimiter(l Limiter) Option {
+	return func(o *options) *options {
+		o.limiter = l
+		return o
+	}
+}
+
+// limit waits for the limiter of a runnable (if any) to permit an execution.
+func (o *options) limit(ctx context.Context) error {
+	if o == nil || o.limiter == nil {
+		return nil
+	}
+	return o.limiter.Wait(ctx)
+}
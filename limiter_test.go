@@ -0,0 +1,106 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingLimiter permits all executions, counting them.
+type countingLimiter struct {
+	waits int32
+}
+
+func (l *countingLimiter) Wait(context.Context) error {
+	atomic.AddInt32(&l.waits, 1)
+	return nil
+}
+
+// tokenLimiter permits an execution for each token received,
+// failing once its tokens are closed.
+type tokenLimiter chan struct{}
+
+func (l tokenLimiter) Wait(ctx context.Context) error {
+	select {
+	case _, ok := <-l:
+		if !ok {
+			return errors.New("limiter closed")
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func testLimiter(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"each execution is gated": func(t *testing.T) {
+			as := newAssertions(t)
+
+			l := new(countingLimiter)
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, WithLimiter(l), Restart(true), RestartLimit(3, nil))
+
+			as.Len(waitErrors(inst.Run(context.TODO())), 3)
+			as.Equal(int32(3), atomic.LoadInt32(&l.waits))
+		},
+		"executions wait for the limiter": func(t *testing.T) {
+			as := newAssertions(t)
+
+			l := make(tokenLimiter)
+			go func() {
+				for n := 0; n < 2; n++ {
+					time.Sleep(testTimeDelta)
+					l <- struct{}{}
+				}
+			}()
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, WithLimiter(l), Recur(true), RunLimit(2))
+
+			start := time.Now()
+			as.Equal([]Event{
+				RunStarted{}, RunSucceeded{},
+				RunStarted{}, RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.GreaterOrEqual(time.Since(start), 2*testTimeDelta)
+			// Waiting does not count towards the duration of executions.
+			as.Less(inst.Stats().LastDuration, testTimeDelta)
+		},
+		"limiter error terminates the instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			l := make(tokenLimiter)
+			close(l)
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, WithLimiter(l))
+
+			as.Equal([]error{errors.New("limiter closed")},
+				waitErrors(inst.Run(context.TODO())))
+			as.Zero(inst.Stats().Runs)
+		},
+		"cancellation while waiting": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, WithLimiter(make(tokenLimiter)))
+
+			as.Equal([]error{context.DeadlineExceeded}, waitErrors(inst.Run(ctx)))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
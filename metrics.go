@@ -0,0 +1,94 @@
+package run
+
+import "time"
+
+// Metrics defines a hook for collecting measurements
+// about the executions of a runnable instance.
+//
+// Implementations are called from the goroutine running the instance,
+// and should not block.
+type Metrics interface {
+	// RunStarted is called before each execution of the runnable.
+	RunStarted()
+	// RunFinished is called after each execution of the runnable
+	// that did not panic, with its duration and returned error.
+	RunFinished(d time.Duration, err error)
+	// Restarted is called when a failed execution is about to be restarted,
+	// with the backoff period preceding the restart.
+	Restarted(backoff time.Duration)
+	// Panicked is called when a panic is recovered from.
+	Panicked(v interface{})
+	// Terminated is called once, when the instance stops executing.
+	Terminated()
+}
+
+// MissedMetrics can be implemented by a metrics hook
+// to be notified of executions skipped in fixed-rate mode
+// or after downtime (see RunsMissed).
+type MissedMetrics interface {
+	RunsMissed(count uint64)
+}
+
+// ProgressMetrics can be implemented by a metrics hook
+// to be notified of the progress reported by executions (see Progress).
+type ProgressMetrics interface {
+	Progress(done, total uint64, msg string)
+}
+
+// WithMetrics registers a metrics hook for a runnable.
+//
+// Unlike most options, metrics hooks accumulate:
+// every registered hook is called, in order of registration.
+func WithMetrics(m Metrics) Option {
+	return func(o *options) *options {
+		if m != nil {
+			o.metrics = append(o.metrics, m)
+		}
+		return o
+	}
+}
+
+// multiMetrics fans out measurements to a list of metrics hooks.
+type multiMetrics []Metrics
+
+// observe translates an event to the measurements of the metrics hooks.
+func (ms multiMetrics) observe(ev Event) {
+	for _, m := range ms {
+		switch e := ev.(type) {
+		case RunStarted:
+			m.RunStarted()
+		case RunSucceeded:
+			m.RunFinished(e.Duration, nil)
+		case RunFailed:
+			m.RunFinished(e.Duration, e.Err)
+		case RunAbandoned:
+			m.RunFinished(e.Duration, ErrRunAbandoned)
+		case BackoffStarted:
+			m.Restarted(e.Delay)
+		case RunsMissed:
+			if mm, ok := m.(MissedMetrics); ok {
+				mm.RunsMissed(e.Count)
+			}
+		case RunSlow:
+			if sm, ok := m.(SlowMetrics); ok {
+				sm.RunSlow(e.Attempt, e.Elapsed)
+			}
+		case ProgressReported:
+			if pm, ok := m.(ProgressMetrics); ok {
+				pm.Progress(e.Done, e.Total, e.Message)
+			}
+		case Recovered:
+			m.Panicked(e.Panic)
+		case Terminated:
+			m.Terminated()
+		}
+	}
+}
+
+// measure returns the metrics hooks of the provided options.
+func (o *options) measure() multiMetrics {
+	if o == nil {
+		return nil
+	}
+	return o.metrics
+}
@@ -0,0 +1,100 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics records the calls of a metrics hook in order.
+type recordingMetrics struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (m *recordingMetrics) record(format string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, fmt.Sprintf(format, args...))
+}
+
+func (m *recordingMetrics) RunStarted() {
+	m.record("started")
+}
+
+func (m *recordingMetrics) RunFinished(_ time.Duration, err error) {
+	m.record("finished: %v", err)
+}
+
+func (m *recordingMetrics) Restarted(backoff time.Duration) {
+	m.record("restarted: %v", backoff)
+}
+
+func (m *recordingMetrics) Panicked(v interface{}) {
+	m.record("panicked: %v", v)
+}
+
+func (m *recordingMetrics) Terminated() {
+	m.record("terminated")
+}
+
+func testMetrics(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"WithMetrics accumulates hooks": func(t *testing.T) {
+			as := newAssertions(t)
+
+			m1, m2 := &recordingMetrics{}, &recordingMetrics{}
+			opts := apply(t, new(options), []Option{
+				WithMetrics(m1), WithMetrics(nil), WithMetrics(m2),
+			})
+
+			as.Equal([]Metrics{m1, m2}, opts.metrics)
+		},
+		"hook observes restarts": func(t *testing.T) {
+			as := newAssertions(t)
+
+			m := &recordingMetrics{}
+			calls := 0
+			inst := New(func(context.Context) error {
+				calls++
+				if calls < 3 {
+					return testError(calls)
+				}
+				return nil
+			},
+				Restart(true),
+				RestartLimit(0, ConstantBackoff(time.Millisecond)),
+				WithMetrics(m),
+			)
+
+			waitErrors(inst.Run(context.TODO()))
+
+			as.Equal([]string{
+				"started", "finished: test error: 1", "restarted: 1ms",
+				"started", "finished: test error: 2", "restarted: 1ms",
+				"started", "finished: <nil>",
+				"terminated",
+			}, m.calls)
+		},
+		"hook observes panics": func(t *testing.T) {
+			as := newAssertions(t)
+
+			m := &recordingMetrics{}
+			inst := New(func(context.Context) error {
+				panic("panic message")
+			}, Recover(true), WithMetrics(m))
+
+			waitErrors(inst.Run(context.TODO()))
+
+			as.Equal([]string{
+				"started", "panicked: panic message", "terminated",
+			}, m.calls)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
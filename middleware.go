@@ -0,0 +1,39 @@
+package run
+
+// Middleware wraps a runnable, decorating each of its executions.
+//
+// A middleware is applied once, when an instance starts running,
+// so any state it captures is scoped to that instance's executions
+// (including those of its concurrent copies, see Concurrency).
+type Middleware func(next Runnable) Runnable
+
+// WithMiddleware registers middleware wrapping the executions of a runnable.
+//
+// Unlike most options, middleware accumulates:
+// the first registered middleware is the outermost one.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(o *options) *options {
+		for _, m := range mw {
+			if m != nil {
+				o.middleware = append(o.middleware, m)
+			}
+		}
+		return o
+	}
+}
+
+// wrap applies the middleware of the provided options to a runnable,
+// after deduplicating its executions (see Singleflight).
+func (o *options) wrap(r Runnable) Runnable {
+	// Wrap the method value to preserve nil runnable semantics.
+	wrapped := Runnable(r.run)
+	if o == nil {
+		return wrapped
+	}
+
+	wrapped = o.share(wrapped)
+	for idx := len(o.middleware) - 1; idx >= 0; idx-- {
+		wrapped = o.middleware[idx](wrapped)
+	}
+	return wrapped
+}
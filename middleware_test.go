@@ -0,0 +1,82 @@
+package run
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func testMiddleware(t *testing.T) {
+	tagging := func(tag string, trace *[]string) Middleware {
+		return func(next Runnable) Runnable {
+			return func(ctx context.Context) error {
+				*trace = append(*trace, tag)
+				return next(ctx)
+			}
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"WithMiddleware accumulates middleware": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var trace []string
+			opts := apply(t, new(options), []Option{
+				WithMiddleware(tagging("a", &trace), nil),
+				WithMiddleware(tagging("b", &trace)),
+			})
+
+			as.Len(opts.middleware, 2)
+		},
+		"first middleware is outermost": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var trace []string
+			inst := New(func(context.Context) error {
+				trace = append(trace, "runnable")
+				return nil
+			}, WithMiddleware(tagging("a", &trace), tagging("b", &trace)))
+
+			waitErrors(inst.Run(context.TODO()))
+
+			as.Equal([]string{"a", "b", "runnable"}, trace)
+		},
+		"applied once per instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var wraps, runs atomic.Int32
+			inst := New(func(context.Context) error {
+				runs.Add(1)
+				return nil
+			}, Concurrency(3), WithMiddleware(func(next Runnable) Runnable {
+				wraps.Add(1)
+				return next
+			}))
+
+			waitErrors(inst.Run(context.TODO()))
+
+			as.Equal(int32(3), runs.Load())
+			as.Equal(int32(1), wraps.Load())
+		},
+		"wrapped nil runnable panics": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var trace []string
+			var opts *options
+			r := apply(t, new(options), []Option{
+				WithMiddleware(tagging("a", &trace)),
+			}).wrap(nil)
+
+			as.PanicsWithValue(NilRunnable, func() {
+				_ = r(context.TODO())
+			})
+			as.PanicsWithValue(NilRunnable, func() {
+				_ = opts.wrap(nil)(context.TODO())
+			})
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,25 @@
+package run
+
+// OnError sets a function called with each error of an instance run with Run
+// (default: nil, errors are propagated to the error channel).
+//
+// When set, errors are passed to the function instead of the error channel,
+// which is only closed once the instance terminates,
+// so that it does not need to be drained.
+// The function is called synchronously during the execution of the instance,
+// so it should not block.
+func OnError(handle func(error)) Option {
+	return func(o *options) *options {
+		o.onError = handle
+		return o
+	}
+}
+
+// errorHandler returns the function errors of a runnable are passed to,
+// if any.
+func (o *options) errorHandler() func(error) {
+	if o == nil {
+		return nil
+	}
+	return o.onError
+}
@@ -0,0 +1,46 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testOnError(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"OnError": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var handled []error
+			opts := apply(t, new(options), []Option{OnError(func(err error) {
+				handled = append(handled, err)
+			})})
+			opts.errorHandler()(testError(1))
+			as.Equal([]error{testError(1)}, handled)
+
+			opts = nil
+			as.Nil(opts.errorHandler())
+		},
+		"errors are handled instead of propagated": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			var handled []error
+			inst := New(func(context.Context) error {
+				if runs++; runs <= 2 {
+					return testError(runs)
+				}
+				return nil
+			}, Restart(true), RestartLimit(0, nil), OnError(func(err error) {
+				handled = append(handled, err)
+			}))
+
+			// The unbuffered error channel is only closed on termination.
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal([]error{testError(1), testError(2)}, handled)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
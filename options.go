@@ -1,19 +1,86 @@
 package run
 
-import "time"
+import (
+	"context"
+	"math"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"time"
+)
 
 // options encapsulates a runnable's execution options.
 type options struct {
-	errChanSize uint
-	recurring   recurrenceOptions
-	constrained constraintOptions
-	restartable restartOptions
-	recoverable panicOptions
+	errChanSize       uint
+	starting          startOptions
+	recurring         recurrenceOptions
+	window            windowOptions
+	constrained       constraintOptions
+	restartable       restartOptions
+	recoverable       panicOptions
+	metrics           []Metrics
+	middleware        []Middleware
+	historySize       uint
+	concurrency       uint
+	limiter           Limiter
+	stopTimeout       time.Duration
+	stopGrace         time.Duration
+	awaitReady        bool
+	name              string
+	labels            map[string]string
+	registry          *Registry
+	health            []HealthRule
+	heartbeat         time.Duration
+	budget            *Budget
+	timing            Clock
+	overflow          OverflowPolicy
+	onError           func(error)
+	reportEnd         bool
+	wrapErrors        bool
+	contextFn         ContextFactory
+	detached          bool
+	triggering        triggerOptions
+	totalTimeout      time.Duration
+	classifier        func(error) Outcome
+	validator         func(context.Context) error
+	precondition      func(context.Context) (bool, error)
+	gate              Gate
+	flights           *Flights
+	flightKey         string
+	store             Store
+	coordination      coordinationOptions
+	profiling         bool
+	parent            *Instance
+	bubble            bool
+	reporter          Reporter
+	alarm             alarmOptions
+	dedupeErrors      bool
+	slowAfter         time.Duration
+	unresponsiveAfter time.Duration
+	abandonAfter      time.Duration
 }
 
 // Option represents an execution option for a runnable.
 type Option func(*options) *options
 
+// WithOptions returns an option applying the provided options in order
+// (skipping nil ones), so that a group of options can be passed around
+// as one, e.g. to layer defaults under overrides:
+//
+//	New(r, WithOptions(defaults...), WithOptions(overrides...))
+//
+// Options following it override the ones it groups, as usual.
+func WithOptions(opts ...Option) Option {
+	return func(o *options) *options {
+		for _, opt := range opts {
+			if opt != nil {
+				o = opt(o)
+			}
+		}
+		return o
+	}
+}
+
 // WithChanBuffer controls the buffer size of the error channel
 // (Default: unbuffered, equivalent to setting size to 0).
 //
@@ -27,6 +94,68 @@ func WithChanBuffer(sz uint) Option {
 	}
 }
 
+// chanSize returns the buffer size of the channel
+// where the outcomes of a runnable are propagated.
+func (o *options) chanSize() uint {
+	if o == nil {
+		return 0
+	}
+	return o.errChanSize
+}
+
+// keepHistory returns the number of execution records to keep.
+func (o *options) keepHistory() uint {
+	if o == nil {
+		return 0
+	}
+	return o.historySize
+}
+
+// startOptions defines options for the first execution of a runnable.
+type startOptions struct {
+	// delay is the amount of time before the first execution.
+	delay time.Duration
+	// splay is the maximum amount of time by which
+	// the first execution is randomly delayed (in addition to delay).
+	splay time.Duration
+}
+
+// InitialDelay delays the first execution of a runnable
+// by the provided amount of time (default: 0, no delay).
+//
+// For runnables with a schedule, the first execution occurs
+// at the first scheduled time after the delay.
+func InitialDelay(d time.Duration) Option {
+	return func(o *options) *options {
+		o.starting.delay = d
+		return o
+	}
+}
+
+// StartSplay randomly delays the first execution of a runnable
+// by up to the provided amount of time (default: 0, no splay),
+// in addition to any initial delay,
+// staggering the executions of instances started together.
+func StartSplay(max time.Duration) Option {
+	return func(o *options) *options {
+		o.starting.splay = max
+		return o
+	}
+}
+
+// firstRun returns the delay before the first execution of a runnable
+// starting at the provided time, and whether it should execute at all.
+func (o *options) firstRun(now time.Time) (time.Duration, bool) {
+	if o == nil {
+		return 0, true
+	}
+
+	wait := o.starting.delay +
+		time.Duration(rand.Float64()*float64(o.starting.splay))
+	after, ok := o.recurring.first(now.Add(wait))
+	return wait + after, ok
+}
+
 // recurrenceOptions defines periodic options.
 type recurrenceOptions struct {
 	// recur denotes whether a runnable
@@ -36,6 +165,45 @@ type recurrenceOptions struct {
 	// a successful termination of a runnable and
 	// the start of its next execution.
 	period time.Duration
+	// schedule (if set) determines the times of the executions
+	// of a runnable, taking precedence over period.
+	schedule Schedule
+	// jitter is the fraction of the period by which
+	// each wait between executions is randomized.
+	jitter float64
+	// fixedRate denotes whether the period is measured
+	// between the starts of consecutive executions.
+	fixedRate bool
+	// overrun determines how executions missed in fixed-rate mode
+	// are handled.
+	overrun OverrunPolicy
+	// catchUp determines how executions missed while the process
+	// was down are handled, once restored from a store.
+	catchUp CatchUpPolicy
+}
+
+// first returns the delay before the first execution of a runnable
+// starting at the provided time, and whether it should execute at all.
+func (r recurrenceOptions) first(now time.Time) (time.Duration, bool) {
+	if !r.recur || r.schedule == nil {
+		return 0, true
+	}
+	return r.next(now)
+}
+
+// next returns the delay before the next execution of a recurring runnable,
+// after a successful one that terminated at the provided time,
+// and whether there is a next execution.
+func (r recurrenceOptions) next(now time.Time) (time.Duration, bool) {
+	if r.schedule == nil {
+		return jittered(r.period, r.jitter, rand.Float64()), true
+	}
+
+	at := r.schedule.Next(now)
+	if at.IsZero() {
+		return 0, false
+	}
+	return at.Sub(now), true
 }
 
 // Recur indicates whether to rerun a runnable after successful executions.
@@ -54,16 +222,55 @@ func Period(period time.Duration) Option {
 	}
 }
 
+// PeriodJitter randomizes each wait between executions of a recurring runnable
+// by up to ±fraction of its period (default: 0, no randomization),
+// spreading the executions of instances started together.
+//
+// The fraction is clamped to [0, 1].
+// It does not apply to runnables with a schedule, or in fixed-rate mode.
+func PeriodJitter(fraction float64) Option {
+	switch {
+	case fraction < 0:
+		fraction = 0
+	case fraction > 1:
+		fraction = 1
+	}
+
+	return func(o *options) *options {
+		o.recurring.jitter = fraction
+		return o
+	}
+}
+
+// jittered randomizes a duration by ±fraction of it,
+// according to the provided random value in [0, 1).
+func jittered(d time.Duration, fraction, rnd float64) time.Duration {
+	if fraction == 0 {
+		return d
+	}
+	return d + time.Duration((2*rnd-1)*fraction*float64(d))
+}
+
 // constraintOptions defines execution constraint options.
 type constraintOptions struct {
 	// timeout is the maximum amount of time
-	// a runnable can execute for (in a single run).
-	timeout time.Duration
+	// a runnable can execute for (in a single run),
+	// unless determined per attempt by timeoutFn (if set).
+	timeout   time.Duration
+	timeoutFn func(attempt uint64) time.Duration
 	// runLimit limits the amount of successful executions of a runnable.
 	runLimit uint64
+	// attemptLimit limits the amount of executions of a runnable,
+	// regardless of their outcome.
+	attemptLimit uint64
 }
 
 // Timeout sets the execution timeout for a runnable.
+//
+// The context of an execution exceeding it is cancelled
+// with ErrRunTimeout as its cause (see context.Cause),
+// and the errors of executions that fail after exceeding it
+// are wrapped in ErrRunTimeout (see RestartOnTimeout).
 func Timeout(timeout time.Duration) Option {
 	return func(o *options) *options {
 		o.constrained.timeout = timeout
@@ -71,6 +278,36 @@ func Timeout(timeout time.Duration) Option {
 	}
 }
 
+// TimeoutFn sets the execution timeout for each execution of a runnable
+// (see Timeout) as returned by the provided function, taking precedence
+// over Timeout, with a non-positive one representing no timeout.
+//
+// The function is provided with the number of the attempt among
+// the consecutive ones since the latest successful execution
+// (1 for the first one, incremented with each restart after a failure),
+// so that restarts can be allowed progressively longer timeouts.
+func TimeoutFn(fn func(attempt uint64) time.Duration) Option {
+	return func(o *options) *options {
+		o.constrained.timeoutFn = fn
+		return o
+	}
+}
+
+// runTimeout returns the execution timeout of the provided attempt,
+// or zero if there is none.
+func (o *options) runTimeout(attempt Attempt) time.Duration {
+	if o == nil {
+		return 0
+	}
+	if fn := o.constrained.timeoutFn; fn != nil {
+		if d := fn(attempt.ConsecutiveFailures + 1); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return o.constrained.timeout
+}
+
 // RunLimit sets the limit of successful executions for a runnable
 // (applicable only when execution is recurring, with default value 0).
 //
@@ -83,6 +320,18 @@ func RunLimit(limit uint64) Option {
 	}
 }
 
+// AttemptLimit sets the limit of executions for a runnable,
+// whether successful or failed (with default value 0),
+// independently of its run and restart limits.
+//
+// A value of 0 represents no limit.
+func AttemptLimit(limit uint64) Option {
+	return func(o *options) *options {
+		o.constrained.attemptLimit = limit
+		return o
+	}
+}
+
 // BackoffFn represents the signature of a backoff function.
 type BackoffFn func(count uint64) time.Duration
 
@@ -94,6 +343,40 @@ func ConstantBackoff(d time.Duration) BackoffFn {
 	}
 }
 
+// ExponentialBackoff returns a backoff function whose period
+// starts at the provided base duration and doubles after each
+// consecutive failed execution, up to the provided ceiling
+// (if positive).
+func ExponentialBackoff(base, ceiling time.Duration) BackoffFn {
+	return scaledBackoff(base, 2, ceiling)
+}
+
+// scaledBackoff returns a backoff function whose period
+// starts at the provided base duration and is multiplied by factor
+// after each consecutive failed execution, up to the provided ceiling
+// (if positive).
+func scaledBackoff(base time.Duration, factor float64, ceiling time.Duration) BackoffFn {
+	return func(count uint64) time.Duration {
+		d := float64(base)
+		for n := uint64(1); n < count && d > 0 && factor > 1; n++ {
+			if ceiling > 0 && d >= float64(ceiling) {
+				break
+			}
+			if d *= factor; d >= math.MaxInt64 {
+				d = math.MaxInt64
+				break
+			}
+		}
+		switch {
+		case ceiling > 0 && d > float64(ceiling):
+			return ceiling
+		case d >= math.MaxInt64:
+			return math.MaxInt64
+		}
+		return time.Duration(d)
+	}
+}
+
 // restartOptions sets restart options
 // for failed executions of a runnable (executions that terminated with error).
 type restartOptions struct {
@@ -110,6 +393,17 @@ type restartOptions struct {
 	// after the n-th (continuous) failed execution of a runnable.
 	// If unset, the runnable is restarted immediately after a failure.
 	backoff BackoffFn
+	// fatalTimeout indicates whether executions that failed
+	// after exceeding their timeout should not be restarted.
+	fatalTimeout bool
+	// stableAfter (if set) is the duration after which a failed execution
+	// is considered stable, resetting the failure count of a runnable.
+	stableAfter time.Duration
+	// timeoutLimit and timeoutBackoff are the restart limit and backoff
+	// of timed out executions, which are accounted separately
+	// if the latter is set.
+	timeoutLimit   uint64
+	timeoutBackoff BackoffFn
 }
 
 // Restart indicates whether to restart a runnable after failed executions.
@@ -139,11 +433,42 @@ func RestartLimit(limit uint64, backoffFn BackoffFn) Option {
 	}
 }
 
+// Backoff sets the backoff function of a runnable, determining
+// the backoff period after each failed execution before it is restarted
+// independently of its restart limit.
+//
+// If nil is provided as the backoff function, no backoff is applied.
+// RestartLimit sets the backoff function too,
+// overriding any set by a preceding Backoff.
+func Backoff(backoffFn BackoffFn) Option {
+	boff := ConstantBackoff(0)
+	if backoffFn != nil {
+		boff = backoffFn
+	}
+
+	return func(o *options) *options {
+		o.restartable.backoff = boff
+		return o
+	}
+}
+
+// delay returns the backoff period after the provided number
+// of consecutive failed executions of a runnable,
+// which is zero if no backoff function is set
+// (such as when Restart is used without RestartLimit or Backoff).
+func (r restartOptions) delay(failedRuns uint64) time.Duration {
+	if r.backoff == nil {
+		return 0
+	}
+	return r.backoff(failedRuns)
+}
+
 // ResetOnSuccess resets the failure count of runnable
 // upon successful execution (default: false).
 //
-// If this option is unset and a runnable has a restart limit n > 0,
-// it will terminate upon reaching n total failed executions.
+// Since the failure count of a runnable that restarts on error
+// is reset upon successful execution regardless (see FailedRuns),
+// and the option requires Restart, it only makes that explicit.
 func ResetOnSuccess(reset bool) Option {
 	return func(o *options) *options {
 		o.restartable.resetOnSuccess = reset
@@ -151,12 +476,29 @@ func ResetOnSuccess(reset bool) Option {
 	}
 }
 
+// ResetAfterStable resets the failure count of a runnable (and thus
+// its backoff) upon a failed execution that ran for at least the provided
+// duration before failing, which then counts as the first of its
+// consecutive failed executions (default: 0, no reset).
+//
+// It allows long-running runnables that fail after having been stable
+// for a while to be restarted as if they failed for the first time.
+func ResetAfterStable(d time.Duration) Option {
+	return func(o *options) *options {
+		o.restartable.stableAfter = d
+		return o
+	}
+}
+
 // panicOptions defines recovery options in case
 // panic is encountered during a runnable's execution.
 type panicOptions struct {
 	// calm indicates whether panic during execution
 	// should be recovered from and returned as an error.
 	calm bool
+	// converter (if set) maps panics during execution
+	// into the errors of the respective executions.
+	converter func(v interface{}, stack []byte) error
 }
 
 // Recover allows a runnable to recover from a panic
@@ -175,3 +517,71 @@ func Recover(r bool) Option {
 func (o *options) calm() bool {
 	return (o != nil) && o.recoverable.calm
 }
+
+// RecoverWith recovers from panics of a runnable, converting
+// the recovered value (along with the stack trace of the goroutine
+// that panicked) into the error of the respective execution
+// through the provided function (default: nil, no conversion).
+//
+// Unlike Recover, the instance does not terminate upon panic:
+// the converted error is handled as that of any failed execution,
+// subject to ErrClassifier (e.g. to make some of them Fatal)
+// and the restart options of the runnable.
+// A nil converted error is replaced with a RunnablePanic,
+// so that panics are never accounted as successful executions,
+// while Recovered is emitted before the failure either way.
+// Panics of goroutines started by Go are converted alike.
+func RecoverWith(convert func(v interface{}, stack []byte) error) Option {
+	return func(o *options) *options {
+		o.recoverable.converter = convert
+		return o
+	}
+}
+
+// convert recovers from a panic of an execution of the runnable
+// of a worker if a converter is set, replacing the provided error
+// with the converted one and recording the stack trace
+// of the goroutine that panicked (as captured by the provided site,
+// or the current one otherwise), while emitting Recovered.
+// It should be deferred.
+func (o *options) convert(w *worker, site *panicSite,
+	emit func(Event), err *error) {
+
+	if o == nil || o.recoverable.converter == nil {
+		return
+	}
+	if episode := recover(); episode != nil {
+		site.record(debug.Stack())
+		w.stack = site.trace()
+		emit(Recovered{Panic: episode})
+		if *err = o.recoverable.converter(episode, w.stack); *err == nil {
+			*err = RunnablePanic{Value: episode}
+		}
+	}
+}
+
+// panicSite records the stack trace of the first panic of an execution,
+// as captured by the goroutine it happened in, since panics
+// of other goroutines are propagated from where they are waited for.
+type panicSite struct {
+	mu    sync.Mutex
+	stack []byte
+}
+
+// record records the provided stack trace, unless one is already recorded.
+func (p *panicSite) record(stack []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stack == nil {
+		p.stack = stack
+	}
+}
+
+// trace returns the recorded stack trace.
+func (p *panicSite) trace() []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.stack
+}
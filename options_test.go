@@ -1,6 +1,7 @@
 package run
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -10,23 +11,80 @@ import (
 var (
 	defaultOptions = &options{
 		errChanSize: 0,
+		starting: startOptions{
+			delay: 0,
+			splay: 0,
+		},
 		recurring: recurrenceOptions{
-			recur:  false,
-			period: 0,
+			recur:     false,
+			period:    0,
+			schedule:  nil,
+			jitter:    0,
+			fixedRate: false,
+			overrun:   OverrunSkip,
+			catchUp:   CatchUpRunOnce,
+		},
+		window: windowOptions{
+			restricted: false,
 		},
 		constrained: constraintOptions{
-			timeout:  0,
-			runLimit: 0,
+			timeout:      0,
+			timeoutFn:    nil,
+			runLimit:     0,
+			attemptLimit: 0,
 		},
 		restartable: restartOptions{
 			restartOnError: false,
 			restartLimit:   0,
 			resetOnSuccess: false,
 			backoff:        nil,
+			fatalTimeout:   false,
+			stableAfter:    0,
 		},
 		recoverable: panicOptions{
 			calm: false,
 		},
+		metrics:     nil,
+		middleware:  nil,
+		historySize: 0,
+		concurrency: 0,
+		limiter:     nil,
+		stopTimeout: 0,
+		stopGrace:   0,
+		awaitReady:  false,
+		name:        "",
+		labels:      nil,
+		registry:    nil,
+		health:      nil,
+		heartbeat:   0,
+		budget:      nil,
+		timing:      nil,
+		overflow:    OverflowBlock,
+		onError:     nil,
+		reportEnd:   false,
+		wrapErrors:  false,
+		contextFn:   nil,
+		detached:    false,
+		triggering: triggerOptions{
+			source:   nil,
+			coalesce: false,
+			debounce: 0,
+			throttle: 0,
+		},
+		totalTimeout: 0,
+		classifier:   nil,
+		validator:    nil,
+		precondition: nil,
+		gate:         nil,
+		flights:      nil,
+		flightKey:    "",
+		store:        nil,
+		coordination: coordinationOptions{
+			locker: nil,
+			key:    "",
+			hold:   0,
+		},
+		profiling: false,
 	}
 )
 
@@ -62,6 +120,24 @@ func testOptions(t *testing.T) {
 				as.Equal(defaultOptions, opts)
 			},
 		},
+		{
+			name: "WithOptions",
+			options: []Option{
+				WithOptions(Recur(true), nil, Period(time.Second)),
+				WithOptions(Period(time.Minute)),
+				WithOptions(),
+			},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					recurring: recurrenceOptions{
+						recur:  true,
+						period: time.Minute,
+					},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
 		{
 			name:    "WithChanBuffer",
 			options: []Option{WithChanBuffer(3)},
@@ -73,6 +149,64 @@ func testOptions(t *testing.T) {
 				as.Equal(expected, opts)
 			},
 		},
+		{
+			name:    "InitialDelay",
+			options: []Option{InitialDelay(time.Minute)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					starting: startOptions{
+						delay: time.Minute,
+					},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "StartSplay",
+			options: []Option{StartSplay(time.Minute)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					starting: startOptions{
+						splay: time.Minute,
+					},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "first execution delay",
+			options: []Option{InitialDelay(time.Minute), StartSplay(time.Minute)},
+			verify: func(as *assert.Assertions, opts *options) {
+				for n := 0; n < 10; n++ {
+					delay, ok := opts.firstRun(time.Now())
+					as.True(ok)
+					as.GreaterOrEqual(delay, time.Minute)
+					as.Less(delay, 2*time.Minute)
+				}
+
+				var nilOpts *options
+				delay, ok := nilOpts.firstRun(time.Now())
+				as.True(ok)
+				as.Zero(delay)
+			},
+		},
+		{
+			name: "first scheduled execution after delay",
+			options: []Option{
+				InitialDelay(90 * time.Minute),
+				WithSchedule(MustParseCron("@hourly", time.UTC)),
+			},
+			verify: func(as *assert.Assertions, opts *options) {
+				now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+				delay, ok := opts.firstRun(now)
+
+				as.True(ok)
+				as.Equal(2*time.Hour, delay)
+			},
+		},
 		{
 			name:    "Recur",
 			options: []Option{Recur(true)},
@@ -99,6 +233,45 @@ func testOptions(t *testing.T) {
 				as.Equal(expected, opts)
 			},
 		},
+		{
+			name:    "PeriodJitter",
+			options: []Option{PeriodJitter(0.25)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					recurring: recurrenceOptions{
+						jitter: 0.25,
+					},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "PeriodJitter is clamped",
+			options: []Option{PeriodJitter(-1)},
+			verify: func(as *assert.Assertions, opts *options) {
+				as.Zero(opts.recurring.jitter)
+				as.Equal(1.0, PeriodJitter(3)(opts).recurring.jitter)
+			},
+		},
+		{
+			name: "jittered period",
+			verify: func(as *assert.Assertions, _ *options) {
+				d := 10 * time.Second
+
+				as.Equal(d, jittered(d, 0, 0.9))
+				as.Equal(8*time.Second, jittered(d, 0.2, 0))
+				as.Equal(d, jittered(d, 0.2, 0.5))
+				as.Equal(11*time.Second, jittered(d, 0.2, 0.75))
+
+				r := recurrenceOptions{recur: true, period: d, jitter: 0.5}
+				for n := 0; n < 10; n++ {
+					delay, _ := r.next(time.Now())
+					as.GreaterOrEqual(delay, 5*time.Second)
+					as.Less(delay, 15*time.Second)
+				}
+			},
+		},
 		{
 			name:    "Timeout",
 			options: []Option{Timeout(3 * time.Second)},
@@ -112,6 +285,24 @@ func testOptions(t *testing.T) {
 				as.Equal(expected, opts)
 			},
 		},
+		{
+			name: "TimeoutFn",
+			options: []Option{
+				Timeout(time.Minute),
+				TimeoutFn(func(attempt uint64) time.Duration {
+					return time.Duration(attempt-1) * time.Second
+				}),
+			},
+			verify: func(as *assert.Assertions, opts *options) {
+				as.Zero(opts.runTimeout(Attempt{}))
+				as.Equal(2*time.Second, opts.runTimeout(Attempt{ConsecutiveFailures: 2}))
+
+				opts.constrained.timeoutFn = nil
+				as.Equal(time.Minute, opts.runTimeout(Attempt{}))
+				opts = nil
+				as.Zero(opts.runTimeout(Attempt{}))
+			},
+		},
 		{
 			name:    "RunLimit",
 			options: []Option{RunLimit(42)},
@@ -125,6 +316,19 @@ func testOptions(t *testing.T) {
 				as.Equal(expected, opts)
 			},
 		},
+		{
+			name:    "AttemptLimit",
+			options: []Option{AttemptLimit(7)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					constrained: constraintOptions{
+						attemptLimit: 7,
+					},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
 		{
 			name:    "Restart",
 			options: []Option{Restart(true)},
@@ -162,6 +366,51 @@ func testOptions(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:    "Backoff with nil backoff",
+			options: []Option{Backoff(nil)},
+			verify: func(as *assert.Assertions, opts *options) {
+				backoff := opts.restartable.backoff
+				opts.restartable.backoff = nil
+
+				as.Equal(&options{}, opts)
+				for _, count := range sampleBackoffCounts {
+					as.Zero(backoff(count))
+				}
+			},
+		},
+		{
+			name: "Backoff preserves restart limit",
+			options: []Option{
+				RestartLimit(3, nil),
+				Backoff(func(c uint64) time.Duration {
+					return time.Duration(c) * time.Second
+				}),
+			},
+			verify: func(as *assert.Assertions, opts *options) {
+				for _, count := range sampleBackoffCounts {
+					expected := time.Duration(count) * time.Second
+					as.Equal(expected, opts.restartable.delay(count))
+				}
+				opts.restartable.backoff = nil
+
+				expected := &options{
+					restartable: restartOptions{
+						restartLimit: 3,
+					},
+				}
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "Restart without backoff",
+			options: []Option{Restart(true)},
+			verify: func(as *assert.Assertions, opts *options) {
+				for _, count := range sampleBackoffCounts {
+					as.Zero(opts.restartable.delay(count))
+				}
+			},
+		},
 		{
 			name: "RestartLimit with custom backoff",
 			options: []Option{
@@ -190,6 +439,33 @@ func testOptions(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "ExponentialBackoff",
+			verify: func(as *assert.Assertions, _ *options) {
+				backoff := ExponentialBackoff(time.Second, time.Minute)
+				for count, expected := range map[uint64]time.Duration{
+					0:    time.Second,
+					1:    time.Second,
+					2:    2 * time.Second,
+					4:    8 * time.Second,
+					7:    time.Minute,
+					1000: time.Minute,
+				} {
+					as.Equal(expected, backoff(count))
+				}
+
+				unbounded := ExponentialBackoff(time.Second, 0)
+				as.Equal(1024*time.Second, unbounded(11))
+				as.Equal(time.Duration(math.MaxInt64), unbounded(1000))
+				as.Zero(ExponentialBackoff(0, 0)(1000))
+
+				scaled := scaledBackoff(time.Second, 10, 0)
+				as.Equal(100*time.Second, scaled(3))
+				as.Equal(time.Duration(math.MaxInt64), scaled(100))
+				as.Equal(time.Duration(math.MaxInt64), scaled(math.MaxUint64))
+				as.Equal(time.Hour, scaledBackoff(time.Second, 10, time.Hour)(100))
+			},
+		},
 		{
 			name:    "ResetOnSuccess",
 			options: []Option{ResetOnSuccess(true)},
@@ -203,6 +479,19 @@ func testOptions(t *testing.T) {
 				as.Equal(expected, opts)
 			},
 		},
+		{
+			name:    "ResetAfterStable",
+			options: []Option{ResetAfterStable(time.Hour)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					restartable: restartOptions{
+						stableAfter: time.Hour,
+					},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
 		{
 			name:    "Recover",
 			options: []Option{Recover(true)},
@@ -216,6 +505,138 @@ func testOptions(t *testing.T) {
 				as.Equal(expected, opts)
 			},
 		},
+		{
+			name:    "KeepHistory",
+			options: []Option{KeepHistory(10)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					historySize: 10,
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "Concurrency",
+			options: []Option{Concurrency(4)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					concurrency: 4,
+				}
+
+				as.Equal(expected, opts)
+				as.Equal(uint(4), opts.workers())
+				as.Equal(uint(1), Concurrency(0)(opts).workers())
+			},
+		},
+		{
+			name:    "WithLimiter",
+			options: []Option{WithLimiter(&countingLimiter{})},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					limiter: &countingLimiter{},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "CatchUp",
+			options: []Option{CatchUp(CatchUpRunAll)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					recurring: recurrenceOptions{
+						catchUp: CatchUpRunAll,
+					},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "DetachValues",
+			options: []Option{DetachValues(true)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					detached: true,
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name: "Triggers",
+			options: []Option{
+				Triggers(nil),
+				CoalesceTriggers(true),
+				Debounce(time.Second),
+				Throttle(time.Minute),
+			},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					triggering: triggerOptions{
+						coalesce: true,
+						debounce: time.Second,
+						throttle: time.Minute,
+					},
+				}
+
+				as.Equal(expected, opts)
+
+				var nilOpts *options
+				as.Nil(nilOpts.triggers())
+				as.False(nilOpts.awaitsTriggers())
+			},
+		},
+		{
+			name:    "Singleflight",
+			options: []Option{Singleflight(&Flights{}, "key")},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					flights:   &Flights{},
+					flightKey: "key",
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "WithStore",
+			options: []Option{WithStore(&MemoryStore{})},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					store: &MemoryStore{},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "Coordinate",
+			options: []Option{Coordinate(&KVLocker{}, "job", time.Minute)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					coordination: coordinationOptions{
+						locker: &KVLocker{},
+						key:    "job",
+						hold:   time.Minute,
+					},
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
+		{
+			name:    "Profiling",
+			options: []Option{Profiling(true)},
+			verify: func(as *assert.Assertions, opts *options) {
+				expected := &options{
+					profiling: true,
+				}
+
+				as.Equal(expected, opts)
+			},
+		},
 		{
 			name: "allow panic with default options",
 			verify: func(as *assert.Assertions, _ *options) {
@@ -0,0 +1,109 @@
+package run
+
+import (
+	"context"
+	"time"
+)
+
+// Ordered indicates whether the members of a group are started
+// in the order they were added and stopped in reverse (default: false).
+//
+// Each member is started once the previous one is ready
+// (see AwaitReady) or has terminated, and is stopped once the next one has terminated
+// (or its stop timeout has elapsed).
+// Members run under a context carrying the values of the group context,
+// whose cancellation stops the group instead.
+func Ordered(ordered bool) GroupOption {
+	return func(o *groupOptions) *groupOptions {
+		o.ordered = ordered
+		return o
+	}
+}
+
+// StopTimeout sets the maximum amount of time an ordered group
+// waits for an instance to terminate after stopping it,
+// before proceeding to stop the previous member (default: 0, no limit),
+// as timed by the clock of the instance (see WithClock).
+//
+// The instance is not waited for afterwards, though the group
+// does not terminate until it does.
+func StopTimeout(d time.Duration) Option {
+	return func(o *options) *options {
+		o.stopTimeout = d
+		return o
+	}
+}
+
+// sequence starts the provided members in order, each once the previous one
+// is ready (or terminated), unless the group is stopped in the meantime.
+func (s *supervisor) sequence(members []*member) {
+	for idx, m := range members {
+		s.g.mu.Lock()
+		if s.g.stopped {
+			s.g.mu.Unlock()
+			// Account for the members that will not start.
+			for _, skipped := range members[idx:] {
+				s.exits <- exit{m: skipped}
+			}
+			return
+		}
+		started := s.start(m)
+		if started {
+			// Accounted for by start.
+			s.active--
+		}
+		inst := m.inst
+		s.g.mu.Unlock()
+
+		if !started {
+			s.exits <- exit{m: m}
+			continue
+		}
+		// Proceed once ready, or terminated.
+		_ = inst.WaitReady(context.Background())
+	}
+}
+
+// shutdown stops the members of a group in reverse order,
+// waiting for each to terminate (up to its stop timeout)
+// before stopping the previous one.
+func (s *supervisor) shutdown() {
+	s.g.mu.Lock()
+	members := append([]*member(nil), s.g.members...)
+	s.g.mu.Unlock()
+
+	for idx := len(members) - 1; idx >= 0; idx-- {
+		s.g.mu.Lock()
+		m := members[idx]
+		m.inst.Stop()
+		running, done := m.running, m.done
+		timeout := m.inst.options().stopTimeout
+		clock := m.inst.options().clock()
+		s.g.mu.Unlock()
+
+		if !running {
+			continue
+		}
+
+		if timeout <= 0 {
+			<-done
+			continue
+		}
+		timer := clock.NewTimer(timeout)
+		select {
+		case <-done:
+		case <-timer.C():
+		}
+		timer.Stop()
+	}
+}
+
+// watch stops an ordered group once its context is cancelled,
+// since its members run under a context detached from it.
+func (s *supervisor) watch() {
+	select {
+	case <-s.ctx.Done():
+		s.g.Stop()
+	case <-s.finished:
+	}
+}
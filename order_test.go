@@ -0,0 +1,208 @@
+package run
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// orderLog records the order of occurrences concurrently.
+type orderLog struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *orderLog) add(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func (l *orderLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.entries...)
+}
+
+func (l *orderLog) has(n int) func() bool {
+	return func() bool {
+		return len(l.list()) >= n
+	}
+}
+
+func testOrder(t *testing.T) {
+	// logging returns a runnable logging its start and stop,
+	// which ignores cancellation for the provided amount of time.
+	logging := func(l *orderLog, name string, linger time.Duration) Runnable {
+		return func(ctx context.Context) error {
+			l.add("start " + name)
+			<-ctx.Done()
+			time.Sleep(linger)
+			l.add("stop " + name)
+			return nil
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"options": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.Equal(&groupOptions{ordered: true}, NewGroup(Ordered(true)).opts)
+			as.Equal(&options{stopTimeout: time.Second},
+				apply(t, new(options), []Option{StopTimeout(time.Second)}))
+		},
+		"ordered start and reverse shutdown": func(t *testing.T) {
+			as := newAssertions(t)
+
+			l := new(orderLog)
+			g := NewGroup(Ordered(true))
+			as.NoError(g.Add("a", logging(l, "a", 0), InitialDelay(testTimeDelta)))
+			as.NoError(g.Add("b", logging(l, "b", 0)))
+			as.NoError(g.Add("c", logging(l, "c", 0)))
+
+			errCh := g.Run(context.TODO())
+			as.Eventually(l.has(3), time.Second, time.Millisecond)
+			g.Stop()
+
+			as.Empty(waitErrors(errCh))
+			as.Equal([]string{
+				"start a", "start b", "start c",
+				"stop c", "stop b", "stop a",
+			}, l.list())
+		},
+		"stop timeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			l, clock := new(orderLog), new(recordingClock)
+			g := NewGroup(Ordered(true))
+			as.NoError(g.Add("a", logging(l, "a", 0)))
+			as.NoError(g.Add("b", logging(l, "b", 3*testTimeDelta),
+				StopTimeout(testTimeDelta), WithClock(clock)))
+
+			errCh := g.Run(context.TODO())
+			as.Eventually(l.has(2), time.Second, time.Millisecond)
+			g.Stop()
+
+			as.Empty(waitErrors(errCh))
+			as.Equal([]string{
+				"start a", "start b",
+				"stop a", "stop b",
+			}, l.list())
+			// The stop timeout is timed by the clock of the member.
+			clock.mu.Lock()
+			as.Contains(clock.timers, testTimeDelta)
+			clock.mu.Unlock()
+		},
+		"cancellation stops the group in order": func(t *testing.T) {
+			as := newAssertions(t)
+
+			type key struct{}
+			ctx, cancel := context.WithCancel(context.WithValue(context.TODO(), key{}, "value"))
+			defer cancel()
+
+			l := new(orderLog)
+			g := NewGroup(Ordered(true))
+			as.NoError(g.Add("a", logging(l, "a", 0)))
+			as.NoError(g.Add("b", func(ctx context.Context) error {
+				l.add("value " + ctx.Value(key{}).(string))
+				return logging(l, "b", 0)(ctx)
+			}))
+
+			errCh := g.Run(ctx)
+			as.Eventually(l.has(3), time.Second, time.Millisecond)
+			cancel()
+
+			as.Empty(waitErrors(errCh))
+			as.Equal([]string{
+				"start a", "value value", "start b",
+				"stop b", "stop a",
+			}, l.list())
+		},
+		"stopping during startup": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			g := NewGroup(Ordered(true))
+			as.NoError(g.Add("a", func(context.Context) error {
+				return nil
+			}, InitialDelay(time.Hour)))
+			as.NoError(g.Add("b", func(context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			}))
+
+			errCh := g.Run(context.TODO())
+			time.Sleep(testTimeDelta)
+			g.Stop()
+			g.Stop()
+
+			as.Empty(waitErrors(errCh))
+			as.Zero(atomic.LoadInt32(&calls))
+		},
+		"terminated members are skipped during shutdown": func(t *testing.T) {
+			as := newAssertions(t)
+
+			l := new(orderLog)
+			g := NewGroup(Ordered(true))
+			as.NoError(g.Add("a", logging(l, "a", 0)))
+			as.NoError(g.Add("b", func(context.Context) error {
+				l.add("done b")
+				return nil
+			}))
+
+			errCh := g.Run(context.TODO())
+			as.Eventually(l.has(2), time.Second, time.Millisecond)
+			g.Stop()
+
+			as.Empty(waitErrors(errCh))
+			as.Equal([]string{"start a", "done b", "stop a"}, l.list())
+		},
+		"restarts are ordered": func(t *testing.T) {
+			as := newAssertions(t)
+
+			l := new(orderLog)
+			var calls int32
+			g := NewGroup(Ordered(true), Supervise(OneForAll))
+			as.NoError(g.Add("a", logging(l, "a", 0)))
+			as.NoError(g.Add("b", func(ctx context.Context) error {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					return testError("b")
+				}
+				return logging(l, "b", 0)(ctx)
+			}))
+
+			errCh := g.Run(context.TODO())
+			go func() {
+				as.Eventually(l.has(4), time.Second, time.Millisecond)
+				g.Stop()
+			}()
+
+			as.Equal([]error{MemberError{Member: "b", Err: testError("b")}},
+				waitErrors(errCh))
+			as.Equal([]string{
+				"start a", "stop a",
+				"start a", "start b",
+				"stop b", "stop a",
+			}, l.list())
+		},
+		"members already run are skipped": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			})
+			waitErrors(inst.Run(context.TODO()))
+
+			g := NewGroup(Ordered(true))
+			as.NoError(g.AddInstance("a", &inst))
+
+			as.Empty(waitGroup(g, context.TODO()))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
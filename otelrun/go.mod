@@ -0,0 +1,27 @@
+module github.com/Ale1ster/run/otelrun
+
+go 1.25.0
+
+require (
+	github.com/Ale1ster/run v0.0.0
+	github.com/stretchr/testify v1.12.1
+	go.opentelemetry.io/otel v1.46.0
+	go.opentelemetry.io/otel/sdk v1.46.0
+	go.opentelemetry.io/otel/trace v1.46.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/go-logr/logr v1.4.4 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
+	go.opentelemetry.io/otel/metric v1.46.0 // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+)
+
+// The APIs used by this module are not part of a tagged release of run yet,
+// so it is resolved from this tree (which consumers have to do as well)
+// until one is, at which point it should be required instead.
+replace github.com/Ale1ster/run => ../
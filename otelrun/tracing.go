@@ -0,0 +1,143 @@
+// Package otelrun traces the executions of runnable instances
+// with OpenTelemetry.
+package otelrun
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Ale1ster/run"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanName is the name of the span wrapping each execution.
+const SpanName = "run.attempt"
+
+// Attribute keys set on execution spans.
+const (
+	// AttemptKey is the (1-based) number of the execution,
+	// as provided by run.AttemptFromContext.
+	AttemptKey = attribute.Key("run.attempt")
+	// OutcomeKey is the outcome of the execution
+	// (one of OutcomeSuccess, OutcomeFailure, or OutcomePanic).
+	OutcomeKey = attribute.Key("run.outcome")
+	// BackoffKey is the delay (in seconds) between the preceding
+	// failed execution and the current one.
+	BackoffKey = attribute.Key("run.backoff")
+	// NameKey is the name of the instance, if named
+	// (see run.WithName).
+	NameKey = attribute.Key("run.name")
+	// LabelKeyPrefix prefixes the key of each label of the instance
+	// (see run.WithLabels), e.g. "run.label.team".
+	LabelKeyPrefix = "run.label."
+)
+
+// Execution outcomes.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomePanic   = "panic"
+)
+
+// Option represents an option of the tracing middleware.
+type Option func(*tracing)
+
+// WithClock sets the clock the timestamps of execution spans
+// (and backoff delays) are taken from (default: run.SystemClock),
+// which should be the clock of the instance (see run.WithClock).
+func WithClock(clock run.Clock) Option {
+	return func(t *tracing) {
+		t.clock = clock
+	}
+}
+
+// Middleware returns a middleware wrapping each execution
+// of a runnable in a span created by the provided tracer.
+//
+// All execution spans of an instance after the first one
+// are linked to the span of the first execution.
+func Middleware(tracer trace.Tracer, opts ...Option) run.Middleware {
+	return func(next run.Runnable) run.Runnable {
+		t := &tracing{tracer: tracer, next: next, clock: run.SystemClock}
+		for _, opt := range opts {
+			opt(t)
+		}
+		return t.run
+	}
+}
+
+// tracing holds the tracing state of a single instance.
+//
+// Executions of an instance may overlap (see run.Concurrency
+// and run.AbandonAfter), so its state is guarded by mu.
+type tracing struct {
+	tracer trace.Tracer
+	next   run.Runnable
+	clock  run.Clock
+
+	mu       sync.Mutex
+	first    trace.SpanContext
+	returned time.Time
+}
+
+func (t *tracing) run(ctx context.Context) (err error) {
+	attempt, _ := run.AttemptFromContext(ctx)
+	started := t.clock.Now()
+
+	opts := []trace.SpanStartOption{
+		trace.WithTimestamp(started),
+		trace.WithAttributes(AttemptKey.Int64(int64(attempt.Number))),
+	}
+	if name, _ := run.NameFromContext(ctx); name != "" {
+		opts = append(opts, trace.WithAttributes(NameKey.String(name)))
+	}
+	for k, v := range run.LabelsFromContext(ctx) {
+		opts = append(opts, trace.WithAttributes(attribute.String(LabelKeyPrefix+k, v)))
+	}
+
+	// The span is started under the lock, so that the spans of concurrent
+	// copies of the runnable are linked to the first one.
+	t.mu.Lock()
+	if t.first.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: t.first}))
+	}
+	if attempt.PreviousErr != nil && !t.returned.IsZero() {
+		backoff := started.Sub(t.returned)
+		opts = append(opts, trace.WithAttributes(BackoffKey.Float64(backoff.Seconds())))
+	}
+	ctx, span := t.tracer.Start(ctx, SpanName, opts...)
+	if !t.first.IsValid() {
+		t.first = span.SpanContext()
+	}
+	t.mu.Unlock()
+
+	defer func() {
+		ended := t.clock.Now()
+		t.mu.Lock()
+		t.returned = ended
+		t.mu.Unlock()
+
+		if v := recover(); v != nil {
+			span.SetAttributes(OutcomeKey.String(OutcomePanic))
+			span.SetStatus(codes.Error, fmt.Sprintf("panic: %v", v))
+			span.End(trace.WithTimestamp(ended))
+			panic(v)
+		}
+
+		switch err {
+		case nil:
+			span.SetAttributes(OutcomeKey.String(OutcomeSuccess))
+		default:
+			span.SetAttributes(OutcomeKey.String(OutcomeFailure))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End(trace.WithTimestamp(ended))
+	}()
+
+	return t.next(ctx)
+}
@@ -0,0 +1,185 @@
+package otelrun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ale1ster/run"
+	"github.com/Ale1ster/run/runtest"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func drain(errCh <-chan error) {
+	for range errCh {
+	}
+}
+
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestMiddleware(t *testing.T) {
+	as := assert.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var spanCtx []trace.SpanContext
+	calls := 0
+	inst := run.New(func(ctx context.Context) error {
+		spanCtx = append(spanCtx, trace.SpanContextFromContext(ctx))
+		calls++
+		if calls == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	},
+		run.Restart(true),
+		run.RestartLimit(0, run.ConstantBackoff(10*time.Millisecond)),
+		run.WithMiddleware(Middleware(tp.Tracer("test"))),
+		run.WithName("job"),
+		run.WithRegistry(run.NewRegistry()),
+		run.WithLabels(map[string]string{"team": "core"}),
+	)
+	drain(inst.Run(context.TODO()))
+
+	spans := recorder.Ended()
+	if !as.Len(spans, 2) {
+		return
+	}
+
+	first, second := spans[0], spans[1]
+	as.Equal(SpanName, first.Name())
+	as.Equal(spanCtx[0], first.SpanContext(), "runnable context carries span")
+	as.Equal(spanCtx[1], second.SpanContext(), "runnable context carries span")
+
+	attempt, _ := attr(first, AttemptKey)
+	as.Equal(int64(1), attempt.AsInt64())
+	outcome, _ := attr(first, OutcomeKey)
+	as.Equal(OutcomeFailure, outcome.AsString())
+	as.Equal(codes.Error, first.Status().Code)
+	as.Len(first.Events(), 1, "error is recorded")
+	name, _ := attr(first, NameKey)
+	as.Equal("job", name.AsString())
+	team, _ := attr(first, LabelKeyPrefix+"team")
+	as.Equal("core", team.AsString())
+	_, hasBackoff := attr(first, BackoffKey)
+	as.False(hasBackoff)
+
+	attempt, _ = attr(second, AttemptKey)
+	as.Equal(int64(2), attempt.AsInt64())
+	outcome, _ = attr(second, OutcomeKey)
+	as.Equal(OutcomeSuccess, outcome.AsString())
+	backoff, _ := attr(second, BackoffKey)
+	as.GreaterOrEqual(backoff.AsFloat64(), (10 * time.Millisecond).Seconds())
+	if as.Len(second.Links(), 1) {
+		as.Equal(first.SpanContext(), second.Links()[0].SpanContext)
+	}
+}
+
+func TestMiddlewarePanics(t *testing.T) {
+	as := assert.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	inst := run.New(func(ctx context.Context) error {
+		panic("panic message")
+	},
+		run.Recover(true),
+		run.WithMiddleware(Middleware(tp.Tracer("test"))),
+	)
+	drain(inst.Run(context.TODO()))
+
+	spans := recorder.Ended()
+	if !as.Len(spans, 1) {
+		return
+	}
+	outcome, _ := attr(spans[0], OutcomeKey)
+	as.Equal(OutcomePanic, outcome.AsString())
+	as.Equal(codes.Error, spans[0].Status().Code)
+}
+
+func TestMiddlewareClock(t *testing.T) {
+	as := assert.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := runtest.NewClock(start)
+	inst := run.New(func(ctx context.Context) error {
+		clock.Advance(time.Second)
+		if attempt, _ := run.AttemptFromContext(ctx); attempt.Number == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	},
+		run.Restart(true),
+		run.RestartLimit(0, run.ConstantBackoff(2*time.Second)),
+		run.WithClock(clock),
+		run.WithMiddleware(Middleware(tp.Tracer("test"), WithClock(clock))),
+	)
+	done := make(chan struct{})
+	go func() {
+		drain(inst.Run(context.TODO()))
+		close(done)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(2 * time.Second)
+	<-done
+
+	spans := recorder.Ended()
+	if !as.Len(spans, 2) {
+		return
+	}
+	as.Equal(start, spans[0].StartTime())
+	as.Equal(start.Add(time.Second), spans[0].EndTime())
+	as.Equal(start.Add(3*time.Second), spans[1].StartTime())
+	as.Equal(start.Add(4*time.Second), spans[1].EndTime())
+	backoff, _ := attr(spans[1], BackoffKey)
+	as.Equal((2 * time.Second).Seconds(), backoff.AsFloat64())
+}
+
+func TestMiddlewareConcurrency(t *testing.T) {
+	as := assert.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	inst := run.New(func(ctx context.Context) error {
+		if attempt, _ := run.AttemptFromContext(ctx); attempt.Number == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	},
+		run.Concurrency(4),
+		run.Restart(true),
+		run.WithMiddleware(Middleware(tp.Tracer("test"))),
+	)
+	drain(inst.Run(context.TODO()))
+
+	// The copies of the runnable share the tracing state of the instance,
+	// so only the span of the first execution is not linked.
+	spans := recorder.Ended()
+	as.Len(spans, 8)
+	unlinked := 0
+	for _, span := range spans {
+		if len(span.Links()) == 0 {
+			unlinked++
+		}
+	}
+	as.Equal(1, unlinked)
+}
@@ -0,0 +1,86 @@
+package run
+
+// OverflowPolicy determines how errors are propagated
+// to the error channel of an instance (see Instance.Run) when it is full,
+// so that a slow consumer does not necessarily stall its execution.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for the channel to have room for the error.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest undelivered error
+	// to make room for the new one
+	// (or the new one, if the channel is unbuffered).
+	OverflowDropOldest
+	// OverflowDropNewest drops the new error.
+	OverflowDropNewest
+	// OverflowLatestOnly keeps only the latest error in the channel,
+	// replacing any undelivered one,
+	// ignoring the buffer size set by WithChanBuffer.
+	OverflowLatestOnly
+)
+
+// Overflow sets the overflow policy of the error channel of an instance
+// (default: OverflowBlock).
+//
+// Dropped errors are counted in the statistics of the instance.
+func Overflow(policy OverflowPolicy) Option {
+	return func(o *options) *options {
+		o.overflow = policy
+		return o
+	}
+}
+
+// overflowPolicy returns the overflow policy of the error channel.
+func (o *options) overflowPolicy() OverflowPolicy {
+	if o == nil {
+		return OverflowBlock
+	}
+	return o.overflow
+}
+
+// errBufferSize returns the buffer size of the error channel of an instance.
+func (o *options) errBufferSize() uint {
+	if o.overflowPolicy() == OverflowLatestOnly {
+		return 1
+	}
+	return o.chanSize()
+}
+
+// deliver propagates an error to the error channel of an instance,
+// according to its overflow policy.
+func (i *Instance) deliver(errCh chan error, err error) {
+	policy := i.options().overflowPolicy()
+	if policy == OverflowBlock {
+		errCh <- err
+		return
+	}
+
+	for {
+		select {
+		case errCh <- err:
+			return
+		default:
+		}
+
+		if policy == OverflowDropNewest || cap(errCh) == 0 {
+			i.drop()
+			return
+		}
+		// Make room for the error,
+		// unless the consumer received one in the meantime.
+		select {
+		case <-errCh:
+			i.drop()
+		default:
+		}
+	}
+}
+
+// drop counts an error dropped from the error channel of an instance.
+func (i *Instance) drop() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.stats.DroppedErrors++
+}
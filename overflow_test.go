@@ -0,0 +1,82 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testOverflow(t *testing.T) {
+	// failingThrice fails on its first three executions.
+	failingThrice := func() Runnable {
+		runs := 0
+		return func(context.Context) error {
+			if runs++; runs <= 3 {
+				return testError(runs)
+			}
+			return nil
+		}
+	}
+	// drained runs an instance to completion before receiving its errors.
+	drained := func(t *testing.T, opts ...Option) ([]error, Stats) {
+		inst := New(failingThrice(),
+			append([]Option{Restart(true), RestartLimit(0, nil)}, opts...)...)
+		errCh := inst.Run(context.TODO())
+		newAssertions(t).Eventually(func() bool {
+			return inst.State().Final()
+		}, time.Second, time.Millisecond)
+		return waitErrors(errCh), inst.Stats()
+	}
+
+	subtests := map[string]func(*testing.T){
+		"Overflow": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{Overflow(OverflowDropNewest)})
+			as.Equal(&options{overflow: OverflowDropNewest}, opts)
+			as.Equal(OverflowDropNewest, opts.overflowPolicy())
+
+			opts = apply(t, new(options), []Option{
+				WithChanBuffer(5), Overflow(OverflowLatestOnly),
+			})
+			as.Equal(uint(1), opts.errBufferSize())
+			opts.overflow = OverflowDropOldest
+			as.Equal(uint(5), opts.errBufferSize())
+
+			opts = nil
+			as.Equal(OverflowBlock, opts.overflowPolicy())
+		},
+		"drop newest": func(t *testing.T) {
+			as := newAssertions(t)
+
+			errs, stats := drained(t, WithChanBuffer(1), Overflow(OverflowDropNewest))
+			as.Equal([]error{testError(1)}, errs)
+			as.Equal(uint64(2), stats.DroppedErrors)
+		},
+		"drop oldest": func(t *testing.T) {
+			as := newAssertions(t)
+
+			errs, stats := drained(t, WithChanBuffer(2), Overflow(OverflowDropOldest))
+			as.Equal([]error{testError(2), testError(3)}, errs)
+			as.Equal(uint64(1), stats.DroppedErrors)
+		},
+		"drop oldest unbuffered": func(t *testing.T) {
+			as := newAssertions(t)
+
+			errs, stats := drained(t, Overflow(OverflowDropOldest))
+			as.Empty(errs)
+			as.Equal(uint64(3), stats.DroppedErrors)
+		},
+		"latest only": func(t *testing.T) {
+			as := newAssertions(t)
+
+			errs, stats := drained(t, WithChanBuffer(5), Overflow(OverflowLatestOnly))
+			as.Equal([]error{testError(3)}, errs)
+			as.Equal(uint64(2), stats.DroppedErrors)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
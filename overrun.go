@@ -0,0 +1,70 @@
+package run
+
+import "time"
+
+// FixedRate indicates whether the period of a recurring runnable
+// is measured between the scheduled starts of consecutive executions,
+// rather than from the termination of one to the start of the next
+// (default: false).
+//
+// Executions that outlast the period are handled according to Overrun,
+// while restarts after failed executions do not shift the rate.
+// It does not apply to runnables with a schedule.
+func FixedRate(fixed bool) Option {
+	return func(o *options) *options {
+		o.recurring.fixedRate = fixed
+		return o
+	}
+}
+
+// OverrunPolicy determines how a recurring runnable in fixed-rate mode
+// handles the executions missed while a previous one outlasted its period.
+type OverrunPolicy int
+
+const (
+	// OverrunSkip skips missed executions,
+	// waiting for the next one on schedule.
+	OverrunSkip OverrunPolicy = iota
+	// OverrunRunOnce executes once immediately
+	// in place of all missed executions.
+	OverrunRunOnce
+	// OverrunRunAll executes each missed execution
+	// immediately one after the other, until caught up.
+	OverrunRunAll
+)
+
+// Overrun sets the overrun policy of a recurring runnable
+// in fixed-rate mode (default: OverrunSkip).
+//
+// Skipped executions are reported through a RunsMissed event.
+func Overrun(policy OverrunPolicy) Option {
+	return func(o *options) *options {
+		o.recurring.overrun = policy
+		return o
+	}
+}
+
+// nextTick returns the delay before the next execution of a recurring
+// runnable in fixed-rate mode, after a successful one that was due at tick
+// and terminated at the provided time, along with the time the next
+// execution is due at and the number of skipped executions.
+func (r recurrenceOptions) nextTick(now, tick time.Time) (
+	after time.Duration, next time.Time, missed uint64) {
+
+	next = tick.Add(r.period)
+	if r.period <= 0 || !now.After(next) {
+		return next.Sub(now), next, 0
+	}
+
+	// Number of executions that were due while running.
+	due := uint64(now.Sub(next)/r.period) + 1
+	switch r.overrun {
+	case OverrunRunOnce:
+		return 0, next.Add(time.Duration(due-1) * r.period), due - 1
+	case OverrunRunAll:
+		return 0, next, 0
+	default:
+		next = next.Add(time.Duration(due) * r.period)
+		return next.Sub(now), next, due
+	}
+}
@@ -0,0 +1,155 @@
+package run
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type missedMetrics struct {
+	recordingMetrics
+	missed uint64
+}
+
+func (m *missedMetrics) RunsMissed(count uint64) {
+	atomic.AddUint64(&m.missed, count)
+}
+
+func testOverrun(t *testing.T) {
+	// overrunning returns a runnable whose first execution
+	// outlasts the provided period by the provided factor.
+	overrunning := func(period time.Duration, factor float64) Runnable {
+		var calls int32
+		return func(context.Context) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				time.Sleep(time.Duration(factor * float64(period)))
+			}
+			return nil
+		}
+	}
+	period := 2 * testTimeDelta
+
+	subtests := map[string]func(*testing.T){
+		"options": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{
+				FixedRate(true), Overrun(OverrunRunAll),
+			})
+			as.Equal(&options{
+				recurring: recurrenceOptions{
+					fixedRate: true,
+					overrun:   OverrunRunAll,
+				},
+			}, opts)
+		},
+		"next tick": func(t *testing.T) {
+			tick := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			at := func(secs int) time.Time {
+				return tick.Add(time.Duration(secs) * time.Second)
+			}
+
+			cases := []struct {
+				name   string
+				policy OverrunPolicy
+				period time.Duration
+				now    time.Time
+				after  time.Duration
+				next   time.Time
+				missed uint64
+			}{
+				{"on time", OverrunSkip, 10 * time.Second, at(4), 6 * time.Second, at(10), 0},
+				{"exactly on tick", OverrunSkip, 10 * time.Second, at(10), 0, at(10), 0},
+				{"zero period", OverrunSkip, 0, at(4), -4 * time.Second, tick, 0},
+				{"skip", OverrunSkip, 10 * time.Second, at(35), 5 * time.Second, at(40), 3},
+				{"run once", OverrunRunOnce, 10 * time.Second, at(35), 0, at(30), 2},
+				{"run all", OverrunRunAll, 10 * time.Second, at(35), 0, at(10), 0},
+			}
+
+			as := newAssertions(t)
+			for _, tc := range cases {
+				r := recurrenceOptions{period: tc.period, overrun: tc.policy}
+				after, next, missed := r.nextTick(tc.now, tick)
+
+				as.Equal(tc.after, after, tc.name)
+				as.Equal(tc.next, next, tc.name)
+				as.Equal(tc.missed, missed, tc.name)
+			}
+		},
+		"skip missed executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			m := new(missedMetrics)
+			inst := New(overrunning(period, 2.5),
+				Recur(true), Period(period), FixedRate(true), RunLimit(2),
+				WithMetrics(m))
+
+			as.Equal([]Event{
+				RunStarted{}, RunSucceeded{},
+				RunsMissed{Count: 2},
+				RunStarted{}, RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.Equal(uint64(2), atomic.LoadUint64(&m.missed))
+		},
+		"run once for missed executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(overrunning(period, 2.5),
+				Recur(true), Period(period), FixedRate(true), RunLimit(2),
+				Overrun(OverrunRunOnce))
+
+			start := time.Now()
+			as.Equal([]Event{
+				RunStarted{}, RunSucceeded{},
+				RunsMissed{Count: 1},
+				RunStarted{}, RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.Less(time.Since(start), 3*period)
+		},
+		"run all missed executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(overrunning(period, 2.5),
+				Recur(true), Period(period), FixedRate(true), RunLimit(3),
+				Overrun(OverrunRunAll))
+
+			start := time.Now()
+			as.Equal([]Event{
+				RunStarted{}, RunSucceeded{},
+				RunStarted{}, RunSucceeded{},
+				RunStarted{}, RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.Less(time.Since(start), 3*period)
+		},
+		"restarts do not shift the rate": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			inst := New(func(context.Context) error {
+				if atomic.AddInt32(&calls, 1) == 2 {
+					return testError(2)
+				}
+				return nil
+			},
+				Recur(true), Period(period), FixedRate(true), RunLimit(3),
+				Restart(true), RestartLimit(0, ConstantBackoff(period/4)))
+
+			start := time.Now()
+			waitEvents(inst.Events(context.TODO()))
+			elapsed := time.Since(start)
+
+			// Executions are due at 0, period and 2*period,
+			// with the restart taking place in between.
+			as.GreaterOrEqual(elapsed, 2*period)
+			as.Less(elapsed, 3*period)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -1,10 +1,19 @@
 package run
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 )
 
+// panicking panics with the provided value, marking the stack trace.
+func panicking(v interface{}) {
+	panic(v)
+}
+
 func testConstants(t *testing.T) {
 	as := newAssertions(t)
 
@@ -30,6 +39,112 @@ func testRunnablePanic(t *testing.T) {
 
 			as.EqualError(p, expected)
 		},
+		"RecoverWith converts panics": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var stacks [][]byte
+			inst := New(func(ctx context.Context) error {
+				attempt, _ := AttemptFromContext(ctx)
+				if attempt.Number < 3 {
+					panic(attempt.Number)
+				}
+				return nil
+			}, Restart(true), RecoverWith(func(v interface{}, stack []byte) error {
+				stacks = append(stacks, stack)
+				return testError(int(v.(uint64)))
+			}))
+
+			as.Equal([]error{testError(1), testError(2)},
+				waitErrors(inst.Run(context.TODO())))
+			as.Len(stacks, 2)
+			as.NotEmpty(stacks[0])
+			as.Equal(uint64(1), inst.SuccessfulRuns())
+		},
+		"RecoverWith with fatal errors": func(t *testing.T) {
+			as := newAssertions(t)
+
+			fatal := errors.New("fatal")
+			var meta []ReportMeta
+			inst := New(func(context.Context) error {
+				panic("boom")
+			}, Restart(true), RecoverWith(func(interface{}, []byte) error {
+				return fatal
+			}), ErrClassifier(func(err error) Outcome {
+				if err == fatal {
+					return Fatal
+				}
+				return Retry
+			}), WithReporter(ReporterFunc(func(_ context.Context, _ error, m ReportMeta) {
+				meta = append(meta, m)
+			})))
+
+			as.Equal([]error{fatal}, waitErrors(inst.Run(context.TODO())))
+			as.Equal(Failed, inst.TerminationReason())
+			if as.Len(meta, 1) {
+				as.True(meta[0].Panicked)
+				as.NotEmpty(meta[0].Stack)
+			}
+		},
+		"RecoverWith without error": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				panic("boom")
+			}, RecoverWith(func(interface{}, []byte) error {
+				return nil
+			}))
+
+			as.Equal([]error{RunnablePanic{Value: "boom"}},
+				waitErrors(inst.Run(context.TODO())))
+			as.Equal(uint64(1), inst.Stats().FailedRuns)
+		},
+		"RecoverWith with goroutines": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var stack []byte
+			inst := New(func(ctx context.Context) error {
+				Go(ctx, func(context.Context) error {
+					panicking("task")
+					return nil
+				})
+				return nil
+			}, RecoverWith(func(v interface{}, s []byte) error {
+				stack = s
+				return testError(1)
+			}))
+
+			as.Equal([]error{testError(1)}, waitErrors(inst.Run(context.TODO())))
+			as.Equal(Failed, inst.TerminationReason())
+			as.True(bytes.Contains(stack, []byte("panicking")))
+		},
+		"RecoverWith with stop grace": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var stack []byte
+			m := &recordingMetrics{}
+			inst := New(func(context.Context) error {
+				panicking("boom")
+				return nil
+			}, StopGrace(time.Hour), WithMetrics(m),
+				RecoverWith(func(v interface{}, s []byte) error {
+					stack = s
+					return testError(1)
+				}))
+
+			as.Equal([]Event{
+				RunStarted{},
+				Recovered{Panic: "boom"},
+				RunFailed{Err: testError(1)},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.True(bytes.Contains(stack, []byte("panicking")))
+			as.Equal([]string{
+				"started",
+				"panicked: boom",
+				"finished: " + testError(1).Error(),
+				"terminated",
+			}, m.calls)
+		},
 	}
 
 	for name, test := range subtests {
@@ -0,0 +1,156 @@
+package run
+
+import (
+	"context"
+	"strings"
+)
+
+// ParallelPolicy determines the outcome of runnables executed by Parallel,
+// and when the remaining ones are cancelled.
+type ParallelPolicy struct {
+	failFast bool
+	// quorum is the number of successful runnables required, if non-zero.
+	quorum int
+}
+
+var (
+	// FailFast fails on the first runnable that fails,
+	// cancelling the rest.
+	FailFast = ParallelPolicy{failFast: true}
+	// WaitAll waits for all runnables to terminate,
+	// failing if any of them fails.
+	WaitAll = ParallelPolicy{}
+)
+
+// Quorum succeeds once n runnables succeed, and fails once
+// that is no longer possible, cancelling the rest in either case.
+// A value of n less than 1 is treated as 1, while a value greater than
+// the number of runnables always fails.
+func Quorum(n int) ParallelPolicy {
+	if n < 1 {
+		n = 1
+	}
+	return ParallelPolicy{quorum: n}
+}
+
+// ParallelError is returned by an execution of parallel runnables
+// when more than one of them may have failed.
+type ParallelError struct {
+	// Errs are the errors of the runnables that failed,
+	// in the order they were provided.
+	Errs []error
+}
+
+// Error satisfies error interface for ParallelError.
+func (e ParallelError) Error() string {
+	if len(e.Errs) == 0 {
+		return "parallel runnables failed"
+	}
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of the runnables that failed.
+func (e ParallelError) Unwrap() []error {
+	return e.Errs
+}
+
+// Parallel returns a runnable executing the provided runnables concurrently
+// under a shared context within a single execution, whose outcome
+// is determined by the provided policy once they all terminate:
+//   - FailFast fails with the error of the first runnable that fails.
+//   - WaitAll fails with ParallelError if any runnable fails.
+//   - Quorum fails with ParallelError (holding the errors that made
+//     the quorum impossible) if not enough runnables succeed.
+//
+// A panic in any of the runnables cancels the rest,
+// and is propagated once they all terminate.
+func Parallel(policy ParallelPolicy, rs ...Runnable) Runnable {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type result struct {
+			idx      int
+			err      error
+			panicked bool
+			episode  interface{}
+		}
+		resCh := make(chan result, len(rs))
+		for idx, r := range rs {
+			go func(idx int, r Runnable) {
+				panicked := true
+				defer func() {
+					if panicked {
+						resCh <- result{idx: idx, panicked: true, episode: recover()}
+					}
+				}()
+
+				err := r(ctx)
+				panicked = false
+				resCh <- result{idx: idx, err: err}
+			}(idx, r)
+		}
+
+		var (
+			errs              = make([]error, len(rs))
+			first             error
+			decided, panicked bool
+			episode           interface{}
+			succeeded, failed int
+		)
+		for range rs {
+			res := <-resCh
+
+			switch {
+			case res.panicked:
+				if !panicked {
+					panicked, episode = true, res.episode
+				}
+				decided = true
+			case decided:
+				// Outcomes after cancellation are irrelevant.
+			case res.err != nil:
+				errs[res.idx] = res.err
+				if first == nil {
+					first = res.err
+				}
+				failed++
+				decided = policy.failFast ||
+					(policy.quorum != 0 && len(rs)-failed < policy.quorum)
+			default:
+				succeeded++
+				decided = policy.quorum != 0 && succeeded >= policy.quorum
+			}
+			if decided {
+				cancel()
+			}
+		}
+
+		switch {
+		case panicked:
+			panic(episode)
+		case policy.quorum != 0:
+			if succeeded >= policy.quorum {
+				return nil
+			}
+		case policy.failFast, failed == 0:
+			return first
+		}
+		return ParallelError{Errs: compact(errs)}
+	}
+}
+
+// compact returns the non-nil errors of the provided ones, in order.
+func compact(errs []error) []error {
+	res := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			res = append(res, err)
+		}
+	}
+	return res
+}
@@ -0,0 +1,107 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testParallel(t *testing.T) {
+	succeed := func(context.Context) error {
+		return nil
+	}
+	fail := func(val interface{}) Runnable {
+		return func(context.Context) error {
+			return testError(val)
+		}
+	}
+	// block waits for cancellation, recording whether it was cancelled.
+	block := func(cancelled *bool) Runnable {
+		return func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				*cancelled = true
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"Quorum": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.Equal(ParallelPolicy{quorum: 1}, Quorum(0))
+			as.Equal(ParallelPolicy{quorum: 2}, Quorum(2))
+		},
+		"ParallelError": func(t *testing.T) {
+			as := newAssertions(t)
+
+			err := ParallelError{Errs: []error{testError(1), testError(2)}}
+			as.Equal("test error: 1; test error: 2", err.Error())
+			as.True(errors.Is(err, testError(2)))
+			as.Equal("parallel runnables failed", ParallelError{}.Error())
+		},
+		"FailFast": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var cancelled bool
+			err := Do(context.TODO(),
+				Parallel(FailFast, block(&cancelled), fail(1)))
+			as.Equal(testError(1), err)
+			as.True(cancelled)
+
+			as.NoError(Do(context.TODO(), Parallel(FailFast, succeed, succeed)))
+		},
+		"WaitAll": func(t *testing.T) {
+			as := newAssertions(t)
+
+			err := Do(context.TODO(),
+				Parallel(WaitAll, fail(1), succeed, fail(2)))
+			as.Equal(ParallelError{Errs: []error{testError(1), testError(2)}}, err)
+
+			as.NoError(Do(context.TODO(), Parallel(WaitAll, succeed, succeed)))
+			as.NoError(Do(context.TODO(), Parallel(WaitAll)))
+		},
+		"Quorum reached": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var cancelled bool
+			err := Do(context.TODO(),
+				Parallel(Quorum(2), succeed, fail(1), succeed, block(&cancelled)))
+			as.NoError(err)
+			as.True(cancelled)
+		},
+		"Quorum impossible": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var cancelled bool
+			err := Do(context.TODO(),
+				Parallel(Quorum(2), fail(1), fail(2), block(&cancelled)))
+			as.Equal(ParallelError{Errs: []error{testError(1), testError(2)}}, err)
+			as.True(cancelled)
+
+			as.Equal(ParallelError{Errs: []error{}},
+				Do(context.TODO(), Parallel(Quorum(3), succeed, succeed)))
+		},
+		"panic is propagated": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var cancelled bool
+			err := Do(context.TODO(), Parallel(WaitAll, block(&cancelled), func(context.Context) error {
+				panic("parallel")
+			}, func(context.Context) error {
+				time.Sleep(testTimeDelta)
+				panic("late")
+			}), Recover(true))
+			as.Equal(RunnablePanic{Value: "parallel"}, err)
+			as.True(cancelled)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
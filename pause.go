@@ -0,0 +1,47 @@
+package run
+
+// Pause pauses an instance, deferring any execution that becomes due
+// until it is resumed, without interrupting an ongoing one.
+// Pausing an instance that is already paused has no effect.
+//
+// The period, schedule and backoff of the instance keep elapsing
+// while paused, with deferred executions starting once resumed.
+func (i *Instance) Pause() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.resumed == nil {
+		i.resumed = make(chan struct{})
+	}
+}
+
+// Resume resumes a paused instance.
+// Resuming an instance that is not paused has no effect.
+func (i *Instance) Resume() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.resumed != nil {
+		close(i.resumed)
+		i.resumed = nil
+	}
+}
+
+// Paused indicates whether an instance is paused.
+//
+// It is safe to call while the instance is running.
+func (i *Instance) Paused() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.resumed != nil
+}
+
+// resumption returns a channel which is closed once a paused instance
+// is resumed, or nil if the instance is not paused.
+func (i *Instance) resumption() <-chan struct{} {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.resumed
+}
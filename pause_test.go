@@ -0,0 +1,91 @@
+package run
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// limiterFunc adapts a function to a limiter.
+type limiterFunc func(context.Context) error
+
+func (f limiterFunc) Wait(ctx context.Context) error {
+	return f(ctx)
+}
+
+func testPause(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"executions are deferred while paused": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			inst := New(func(context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			}, Recur(true), Period(testTimeDelta/3), RunLimit(3))
+
+			inst.Pause()
+			inst.Pause()
+			as.True(inst.Paused())
+
+			errCh := inst.Run(context.TODO())
+			time.Sleep(testTimeDelta)
+			as.Equal(int32(0), atomic.LoadInt32(&calls))
+
+			inst.Resume()
+			inst.Resume()
+			as.False(inst.Paused())
+			as.Empty(waitErrors(errCh))
+			as.Equal(int32(3), atomic.LoadInt32(&calls))
+		},
+		"cancellation while paused": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			})
+			inst.Pause()
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+			as.Equal([]error{context.DeadlineExceeded}, waitErrors(inst.Run(ctx)))
+		},
+		"halted while paused": func(t *testing.T) {
+			as := newAssertions(t)
+
+			// The second worker waits for the limiter
+			// until the first one pauses the instance,
+			// and then reaches the run limit while the first one is paused.
+			var waits, calls int32
+			reached, release := make(chan struct{}), make(chan struct{})
+			limiter := limiterFunc(func(context.Context) error {
+				if atomic.AddInt32(&waits, 1) == 2 {
+					close(reached)
+					<-release
+				}
+				return nil
+			})
+
+			var inst Instance
+			inst = New(func(context.Context) error {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					<-reached
+					inst.Pause()
+					close(release)
+					return nil
+				}
+				time.Sleep(testTimeDelta)
+				return nil
+			}, Recur(true), RunLimit(2), Concurrency(2), WithLimiter(limiter))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(uint64(2), inst.Stats().Runs)
+			as.True(inst.Paused())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
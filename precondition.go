@@ -0,0 +1,28 @@
+package run
+
+import "context"
+
+// RunIf sets a precondition evaluated before each execution of a runnable,
+// provided with the context of the instance carrying the upcoming attempt
+// (see AttemptFromContext) (default: nil, always executed).
+//
+// An execution whose precondition does not hold is skipped,
+// and rescheduled according to the recurrence options of the runnable
+// as a successful one would be, without being accounted as a run.
+// A precondition returning an error fails the execution with it,
+// subject to the restart options of the runnable.
+func RunIf(cond func(ctx context.Context) (bool, error)) Option {
+	return func(o *options) *options {
+		o.precondition = cond
+		return o
+	}
+}
+
+// admit evaluates the precondition of a runnable (if any)
+// for an upcoming execution, provided with its context.
+func (o *options) admit(ctx context.Context) (bool, error) {
+	if o == nil || o.precondition == nil {
+		return true, nil
+	}
+	return o.precondition(ctx)
+}
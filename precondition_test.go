@@ -0,0 +1,111 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testPrecondition(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"skipped executions are rescheduled": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs, checks := 0, 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, Recur(true), RunLimit(2),
+				RunIf(func(ctx context.Context) (bool, error) {
+					_, ok := AttemptFromContext(ctx)
+					as.True(ok)
+					checks++
+					return checks%2 == 0, nil
+				}))
+
+			as.Equal([]Event{
+				RunSkipped{},
+				RunStarted{},
+				RunSucceeded{},
+				RunSkipped{},
+				RunStarted{},
+				RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.Equal(2, runs)
+			as.Equal(4, checks)
+			as.Equal(uint64(2), inst.SuccessfulRuns())
+		},
+		"skipped execution without recurrence": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, RunIf(func(context.Context) (bool, error) {
+				return false, nil
+			}))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Zero(runs)
+			as.Equal(Completed, inst.TerminationReason())
+			as.Zero(inst.Stats().Runs)
+		},
+		"failed precondition is restarted": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs, checks := 0, 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, Restart(true), RestartLimit(0, nil),
+				RunIf(func(context.Context) (bool, error) {
+					if checks++; checks == 1 {
+						return false, testError(-1)
+					}
+					return true, nil
+				}))
+
+			as.Equal([]Event{
+				RunFailed{Err: testError(-1)},
+				BackoffStarted{},
+				RunStarted{},
+				RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.Equal(1, runs)
+			as.Equal(uint64(1), inst.Stats().FailedRuns)
+		},
+		"cancelled during precondition": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, RunIf(func(ctx context.Context) (bool, error) {
+				cancel()
+				return false, ctx.Err()
+			}))
+
+			as.Equal([]error{context.Canceled}, waitErrors(inst.Run(ctx)))
+			as.Zero(runs)
+			as.Equal(ContextCancelled, inst.TerminationReason())
+		},
+		"without precondition": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var opts *options
+			admitted, err := opts.admit(context.TODO())
+			as.True(admitted)
+			as.NoError(err)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,32 @@
+package run
+
+import "time"
+
+// ServiceProfile returns an option bundling the options suited
+// to long-running services, which are kept running by restarting them
+// after failed executions without limit, with a backoff doubling
+// from 100ms up to 30s (reset once an execution has been stable
+// for a minute before failing), and recovering from panic.
+//
+// Options following it override the ones it bundles.
+func ServiceProfile() Option {
+	return WithOptions(
+		Recover(true),
+		Restart(true),
+		RestartLimit(0, ExponentialBackoff(100*time.Millisecond, 30*time.Second)),
+		ResetAfterStable(time.Minute),
+	)
+}
+
+// CronJobProfile returns an option bundling the options suited
+// to periodic jobs, which recur with the provided period between
+// their executions, terminating after a failed one.
+//
+// Options following it override the ones it bundles.
+func CronJobProfile(period time.Duration) Option {
+	return WithOptions(
+		Recur(true),
+		Period(period),
+		Restart(false),
+	)
+}
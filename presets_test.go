@@ -0,0 +1,59 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testPresets(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"ServiceProfile": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{ServiceProfile()})
+			backoff := opts.restartable.backoff
+			opts.restartable.backoff = nil
+
+			as.Equal(&options{
+				restartable: restartOptions{
+					restartOnError: true,
+					stableAfter:    time.Minute,
+				},
+				recoverable: panicOptions{
+					calm: true,
+				},
+			}, opts)
+			as.Equal(100*time.Millisecond, backoff(1))
+			as.Equal(30*time.Second, backoff(20))
+		},
+		"CronJobProfile": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{CronJobProfile(time.Hour)})
+			as.Equal(&options{
+				recurring: recurrenceOptions{
+					recur:  true,
+					period: time.Hour,
+				},
+			}, opts)
+		},
+		"overridden": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return testError(runs)
+			}, ServiceProfile(), RestartLimit(2, nil))
+
+			as.Equal([]error{testError(1), testError(2)},
+				waitErrors(inst.Run(context.TODO())))
+			as.Equal(RestartLimitExceeded, inst.TerminationReason())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
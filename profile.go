@@ -0,0 +1,52 @@
+package run
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+)
+
+// Profiling indicates whether to label each execution of a runnable
+// for profiling (default: false), telling apart the executions
+// of different instances in profiles and execution traces.
+//
+// Executions are run with the pprof labels "run.name" (the name
+// of the instance, see WithName) and "run.attempt" (the number
+// of the execution, see Attempt), which are inherited by the goroutines
+// they start, and traced as a runtime/trace task named after the instance
+// (or "run", if unnamed), with a region spanning each execution.
+func Profiling(enabled bool) Option {
+	return func(o *options) *options {
+		o.profiling = enabled
+		return o
+	}
+}
+
+// profile calls the provided function executing a runnable as the provided
+// attempt, with the provided context labelled for profiling,
+// if the appropriate option is set.
+func (o *options) profile(ctx context.Context, attempt Attempt,
+	execute func(ctx context.Context)) {
+
+	if o == nil || !o.profiling {
+		execute(ctx)
+		return
+	}
+
+	name := o.name
+	if name == "" {
+		name = "run"
+	}
+	ctx, task := trace.NewTask(ctx, name)
+	defer task.End()
+
+	number := strconv.FormatUint(attempt.Number, 10)
+	trace.Log(ctx, "attempt", number)
+	labels := pprof.Labels("run.name", o.name, "run.attempt", number)
+	pprof.Do(ctx, labels, func(ctx context.Context) {
+		trace.WithRegion(ctx, "execution", func() {
+			execute(ctx)
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package run
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+)
+
+func testProfiling(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"executions are labelled": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var attempts []string
+			for _, name := range []string{"job", ""} {
+				runs := 0
+				inst := New(func(ctx context.Context) error {
+					actual, ok := pprof.Label(ctx, "run.name")
+					as.True(ok)
+					as.Equal(name, actual)
+					attempt, _ := pprof.Label(ctx, "run.attempt")
+					attempts = append(attempts, attempt)
+					if runs++; runs == 1 {
+						return testError(runs)
+					}
+					return nil
+				}, Restart(true), RestartLimit(0, nil), WithName(name), WithRegistry(NewRegistry()), Profiling(true))
+
+				as.Equal([]error{testError(1)}, waitErrors(inst.Run(context.TODO())))
+			}
+			as.Equal([]string{"1", "2", "1", "2"}, attempts)
+		},
+		"unlabelled by default": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				_, ok := pprof.Label(ctx, "run.attempt")
+				as.False(ok)
+				return nil
+			})
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,64 @@
+package run
+
+import (
+	"context"
+	"sync"
+)
+
+// progressKey is the context key for the function reporting
+// the progress of an execution.
+type progressKey struct{}
+
+// Progress reports the progress of the execution of a runnable,
+// provided with its context, as the amounts of work done so far
+// and in total (in units of the runnable's choosing), along with
+// a message describing it (e.g. Progress(ctx, 60, 100, "migrating")).
+//
+// The progress is emitted as a ProgressReported event,
+// kept in the statistics of the instance until its next execution
+// (see Stats) and passed to its metrics hooks (see ProgressMetrics).
+// Outside of executions (including once an execution is abandoned,
+// see StopGrace and AbandonAfter), it has no effect.
+func Progress(ctx context.Context, done, total uint64, msg string) {
+	if report, ok := ctx.Value(progressKey{}).(func(ProgressReported)); ok {
+		report(ProgressReported{Done: done, Total: total, Message: msg})
+	}
+}
+
+// withProgress returns a child of the provided context reporting
+// the progress of an execution of the runnable of an instance,
+// clearing any progress reported by the previous one,
+// along with a function ending the execution, after which
+// any progress reported is dropped (e.g. by abandoned executions).
+func (i *Instance) withProgress(ctx context.Context,
+	emit func(Event)) (context.Context, func()) {
+
+	i.mu.Lock()
+	i.stats.Progress = ProgressReported{}
+	i.mu.Unlock()
+
+	// mu is held while reporting, so that no progress is emitted
+	// once the execution ends.
+	var mu sync.Mutex
+	ended := false
+	report := func(p ProgressReported) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if ended {
+			return
+		}
+		i.mu.Lock()
+		i.stats.Progress = p
+		i.mu.Unlock()
+
+		emit(p)
+	}
+	end := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ended = true
+	}
+	return context.WithValue(ctx, progressKey{}, report), end
+}
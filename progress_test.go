@@ -0,0 +1,143 @@
+package run
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type progressMetrics struct {
+	recordingMetrics
+}
+
+func (m *progressMetrics) Progress(done, total uint64, msg string) {
+	m.record("progress: %d/%d %s", done, total, msg)
+}
+
+func testProgress(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"progress is reported": func(t *testing.T) {
+			as := newAssertions(t)
+
+			m := &progressMetrics{}
+			runs := 0
+			inst := New(func(ctx context.Context) error {
+				if runs++; runs == 1 {
+					Progress(ctx, 1, 2, "halfway")
+					Progress(ctx, 2, 2, "done")
+				}
+				return nil
+			}, Recur(true), RunLimit(2), WithMetrics(m))
+
+			as.Equal([]Event{
+				RunStarted{},
+				ProgressReported{Done: 1, Total: 2, Message: "halfway"},
+				ProgressReported{Done: 2, Total: 2, Message: "done"},
+				RunSucceeded{},
+				RunStarted{},
+				RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+			as.Equal([]string{
+				"started",
+				"progress: 1/2 halfway",
+				"progress: 2/2 done",
+				"finished: <nil>",
+				"started",
+				"finished: <nil>",
+				"terminated",
+			}, m.calls)
+			// Progress is cleared by the next execution.
+			as.Zero(inst.Stats().Progress)
+		},
+		"latest progress": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				Progress(ctx, 60, 100, "migrating")
+				return nil
+			})
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(ProgressReported{Done: 60, Total: 100, Message: "migrating"},
+				inst.Stats().Progress)
+		},
+		"abandoned executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release, reported := make(chan struct{}), make(chan struct{})
+			var runs atomic.Int32
+			inst := New(func(ctx context.Context) error {
+				if runs.Add(1) == 1 {
+					// Ignores the cancellation of its context.
+					<-release
+					Progress(ctx, 1, 1, "stale")
+					close(reported)
+					return nil
+				}
+				Progress(ctx, 1, 2, "current")
+				return nil
+			}, Timeout(testTimeDelta), AbandonAfter(testTimeDelta), Restart(true))
+
+			as.Equal([]Event{
+				RunStarted{},
+				RunAbandoned{},
+				BackoffStarted{},
+				RunStarted{},
+				ProgressReported{Done: 1, Total: 2, Message: "current"},
+				RunSucceeded{},
+				Terminated{},
+			}, waitEvents(inst.Events(context.TODO())))
+
+			// Reporting after termination neither panics nor overwrites
+			// the progress of the latest execution.
+			close(release)
+			select {
+			case <-reported:
+			case <-time.After(time.Second):
+				as.Fail("runnable did not finish reporting")
+			}
+			as.Equal(ProgressReported{Done: 1, Total: 2, Message: "current"},
+				inst.Stats().Progress)
+		},
+		"after stop grace": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release, reported := make(chan struct{}), make(chan struct{})
+			inst := New(func(ctx context.Context) error {
+				<-release
+				Progress(ctx, 1, 1, "stale")
+				close(reported)
+				return nil
+			}, StopGrace(testTimeDelta))
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+			as.Equal([]Event{
+				RunStarted{},
+				RunAbandoned{},
+				Terminated{Reason: context.DeadlineExceeded},
+			}, waitEvents(inst.Events(ctx)))
+
+			close(release)
+			select {
+			case <-reported:
+			case <-time.After(time.Second):
+				as.Fail("runnable did not finish reporting")
+			}
+			as.Zero(inst.Stats().Progress)
+		},
+		"outside executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.NotPanics(func() {
+				Progress(context.TODO(), 1, 1, "")
+			})
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
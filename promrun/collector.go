@@ -0,0 +1,159 @@
+// Package promrun exports the metrics of runnable instances
+// as Prometheus collectors.
+package promrun
+
+import (
+	"time"
+
+	"github.com/Ale1ster/run"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// states are the instance states reported by the state gauge.
+var states = []run.State{
+	run.StateIdle,
+	run.StateRunning,
+	run.StateBackingOff,
+	run.StateWaitingPeriod,
+	run.StateTerminated,
+	run.StateStopped,
+}
+
+// Collector collects the metrics of runnable instances,
+// labeled by instance name.
+//
+// It implements prometheus.Collector, and provides
+// a metrics hook for each instance through Instance.
+type Collector struct {
+	runs      *prometheus.CounterVec
+	failures  *prometheus.CounterVec
+	restarts  *prometheus.CounterVec
+	panics    *prometheus.CounterVec
+	missed    *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+	state     *prometheus.GaugeVec
+}
+
+// NewCollector creates a new collector whose metrics are
+// placed under the provided namespace.
+func NewCollector(namespace string) *Collector {
+	label := []string{"instance"}
+
+	return &Collector{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "run",
+			Name:      "executions_total",
+			Help:      "Total number of started executions.",
+		}, label),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "run",
+			Name:      "failures_total",
+			Help:      "Total number of executions that returned an error.",
+		}, label),
+		restarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "run",
+			Name:      "restarts_total",
+			Help:      "Total number of restarts after failed executions.",
+		}, label),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "run",
+			Name:      "panics_total",
+			Help:      "Total number of recovered panics.",
+		}, label),
+		missed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "run",
+			Name:      "missed_executions_total",
+			Help:      "Total number of executions skipped due to overrun or downtime.",
+		}, label),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "run",
+			Name:      "execution_duration_seconds",
+			Help:      "Duration of executions that did not panic.",
+			Buckets:   prometheus.DefBuckets,
+		}, label),
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "run",
+			Name:      "state",
+			Help:      "Current state of the instance (1 for the active state).",
+		}, []string{"instance", "state"}),
+	}
+}
+
+// Describe satisfies prometheus.Collector interface for Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.runs.Describe(ch)
+	c.failures.Describe(ch)
+	c.restarts.Describe(ch)
+	c.panics.Describe(ch)
+	c.missed.Describe(ch)
+	c.durations.Describe(ch)
+	c.state.Describe(ch)
+}
+
+// Collect satisfies prometheus.Collector interface for Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.runs.Collect(ch)
+	c.failures.Collect(ch)
+	c.restarts.Collect(ch)
+	c.panics.Collect(ch)
+	c.missed.Collect(ch)
+	c.durations.Collect(ch)
+	c.state.Collect(ch)
+}
+
+// Instance returns a metrics hook recording measurements
+// under the provided instance name, to be registered with run.WithMetrics.
+func (c *Collector) Instance(name string) run.Metrics {
+	return &instanceMetrics{c: c, name: name}
+}
+
+// instanceMetrics is the metrics hook of a single instance.
+type instanceMetrics struct {
+	c    *Collector
+	name string
+}
+
+func (m *instanceMetrics) RunStarted() {
+	m.c.runs.WithLabelValues(m.name).Inc()
+}
+
+func (m *instanceMetrics) RunFinished(d time.Duration, err error) {
+	m.c.durations.WithLabelValues(m.name).Observe(d.Seconds())
+	if err != nil {
+		m.c.failures.WithLabelValues(m.name).Inc()
+	}
+}
+
+func (m *instanceMetrics) Restarted(_ time.Duration) {
+	m.c.restarts.WithLabelValues(m.name).Inc()
+}
+
+func (m *instanceMetrics) Panicked(_ interface{}) {
+	m.c.panics.WithLabelValues(m.name).Inc()
+}
+
+func (m *instanceMetrics) Terminated() {}
+
+// RunsMissed satisfies run.MissedMetrics interface.
+func (m *instanceMetrics) RunsMissed(count uint64) {
+	m.c.missed.WithLabelValues(m.name).Add(float64(count))
+}
+
+// StateChanged satisfies run.StateMetrics interface,
+// marking the state the instance transitioned to as the active one.
+func (m *instanceMetrics) StateChanged(tr run.StateTransition) {
+	for _, s := range states {
+		var v float64
+		if s == tr.To {
+			v = 1
+		}
+		m.c.state.WithLabelValues(m.name, s.String()).Set(v)
+	}
+}
@@ -0,0 +1,85 @@
+package promrun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ale1ster/run"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func drain(errCh <-chan error) {
+	for range errCh {
+	}
+}
+
+func TestCollector(t *testing.T) {
+	as := assert.New(t)
+
+	c := NewCollector("test")
+	reg := prometheus.NewPedanticRegistry()
+	as.NoError(reg.Register(c))
+
+	calls := 0
+	inst := run.New(func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("failed")
+		}
+		return nil
+	},
+		run.Restart(true),
+		run.RestartLimit(0, run.ConstantBackoff(time.Millisecond)),
+		run.WithMetrics(c.Instance("job")),
+	)
+	drain(inst.Run(context.TODO()))
+
+	as.Equal(3.0, testutil.ToFloat64(c.runs.WithLabelValues("job")))
+	as.Equal(2.0, testutil.ToFloat64(c.failures.WithLabelValues("job")))
+	as.Equal(2.0, testutil.ToFloat64(c.restarts.WithLabelValues("job")))
+	as.Equal(0.0, testutil.ToFloat64(c.panics.WithLabelValues("job")))
+	as.Equal(1.0, testutil.ToFloat64(c.state.WithLabelValues("job", run.StateTerminated.String())))
+	as.Equal(0.0, testutil.ToFloat64(c.state.WithLabelValues("job", run.StateBackingOff.String())))
+	as.Equal(1, testutil.CollectAndCount(c.durations))
+
+	problems, err := testutil.CollectAndLint(c)
+	as.NoError(err)
+	as.Empty(problems)
+}
+
+func TestCollectorPanics(t *testing.T) {
+	as := assert.New(t)
+
+	c := NewCollector("test")
+	inst := run.New(func(context.Context) error {
+		panic("panic message")
+	}, run.Recover(true), run.WithMetrics(c.Instance("job")))
+	drain(inst.Run(context.TODO()))
+
+	as.Equal(1.0, testutil.ToFloat64(c.runs.WithLabelValues("job")))
+	as.Equal(1.0, testutil.ToFloat64(c.panics.WithLabelValues("job")))
+	as.Equal(1.0, testutil.ToFloat64(c.state.WithLabelValues("job", run.StateTerminated.String())))
+}
+
+func TestCollectorMissed(t *testing.T) {
+	as := assert.New(t)
+
+	c := NewCollector("test")
+	inst := run.New(func(context.Context) error {
+		time.Sleep(25 * time.Millisecond)
+		return nil
+	},
+		run.Recur(true),
+		run.Period(10*time.Millisecond),
+		run.FixedRate(true),
+		run.RunLimit(2),
+		run.WithMetrics(c.Instance("job")),
+	)
+	drain(inst.Run(context.TODO()))
+
+	as.Equal(2.0, testutil.ToFloat64(c.missed.WithLabelValues("job")))
+}
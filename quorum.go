@@ -0,0 +1,44 @@
+package run
+
+import "errors"
+
+// ErrQuorumLost is propagated by a group with a quorum (see GroupQuorum)
+// whenever the number of its running members drops below it.
+var ErrQuorumLost = errors.New("group quorum lost")
+
+// GroupQuorum sets the number of members of a group that need to be running
+// for it to be considered healthy (default: 0, all of them need to be healthy),
+// e.g. for groups of redundant workers.
+//
+// Once the group reaches its quorum, ErrQuorumLost is propagated
+// each time the number of its running members drops below it,
+// unless the group is stopped or its context is cancelled.
+// With a quorum, the group is healthy (see Group.HealthReport)
+// as long as at least that many of its members are running and healthy.
+func GroupQuorum(n uint) GroupOption {
+	return func(o *groupOptions) *groupOptions {
+		o.quorum = n
+		return o
+	}
+}
+
+// assess updates whether the quorum of a group is reached,
+// indicating whether it was lost since the previous assessment.
+// The lock of the group should be held.
+func (s *supervisor) assess() bool {
+	quorum := s.g.opts.quorum
+	if quorum == 0 {
+		return false
+	}
+
+	running := uint(0)
+	for _, m := range s.g.members {
+		if m.running {
+			running++
+		}
+	}
+	reached := running >= quorum
+	lost := s.quorate && !reached && !s.g.stopped && s.ctx.Err() == nil
+	s.quorate = reached
+	return lost
+}
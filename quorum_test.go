@@ -0,0 +1,129 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testQuorum(t *testing.T) {
+	blocking := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	subtests := map[string]func(*testing.T){
+		"GroupQuorum": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.Equal(uint(2), NewGroup(GroupQuorum(2)).opts.quorum)
+			as.Zero(NewGroup().opts.quorum)
+		},
+		"lost": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			g := NewGroup(GroupQuorum(3))
+			as.NoError(g.Add("a", blocking))
+			as.NoError(g.Add("b", blocking))
+			as.NoError(g.Add("c", func(context.Context) error {
+				<-release
+				return errors.New("failed")
+			}))
+			errCh := g.Run(context.TODO())
+
+			close(release)
+			as.Equal(MemberError{Member: "c", Err: errors.New("failed")}, <-errCh)
+			as.Equal(ErrQuorumLost, <-errCh)
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"lost repeatedly": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup(GroupQuorum(3), Supervise(OneForAll))
+			as.NoError(g.Add("a", blocking))
+			as.NoError(g.Add("b", blocking))
+			runs := 0
+			as.NoError(g.Add("c", func(ctx context.Context) error {
+				if runs++; runs <= 2 {
+					return testError(runs)
+				}
+				return blocking(ctx)
+			}))
+			errCh := g.Run(context.TODO())
+
+			for _, expected := range []error{
+				MemberError{Member: "c", Err: testError(1)}, ErrQuorumLost,
+				MemberError{Member: "c", Err: testError(2)}, ErrQuorumLost,
+			} {
+				as.Equal(expected, <-errCh)
+			}
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"never reached": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup(GroupQuorum(3))
+			as.NoError(g.Add("a", blocking))
+			as.NoError(g.Add("b", func(context.Context) error {
+				return errors.New("failed")
+			}))
+			errCh := g.Run(context.TODO())
+
+			as.Equal(MemberError{Member: "b", Err: errors.New("failed")}, <-errCh)
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"not lost when stopped": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup(GroupQuorum(2))
+			as.NoError(g.Add("a", blocking))
+			as.NoError(g.Add("b", blocking))
+			errCh := g.Run(context.TODO())
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"health": func(t *testing.T) {
+			as := newAssertions(t)
+
+			unhealthy := func(Stats, time.Time) error {
+				return errors.New("unhealthy")
+			}
+			for _, tc := range []struct {
+				quorum  uint
+				healthy bool
+			}{
+				{quorum: 0, healthy: false},
+				{quorum: 1, healthy: true},
+				{quorum: 2, healthy: false},
+			} {
+				g := NewGroup(GroupQuorum(tc.quorum))
+				as.NoError(g.Add("a", blocking))
+				as.NoError(g.Add("b", blocking, HealthRules(unhealthy)))
+				as.NoError(g.Add("c", func(context.Context) error {
+					return nil
+				}))
+				errCh := g.Run(context.TODO())
+				c, _ := g.Member("c")
+				<-c.Done()
+				// Allow the group to observe the termination of the member.
+				time.Sleep(testTimeDelta)
+
+				report := g.HealthReport()
+				as.Equal(tc.healthy, report.Healthy, "quorum %d", tc.quorum)
+				as.Len(report.Members, 3)
+				g.Stop()
+				as.Empty(waitErrors(errCh))
+			}
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,129 @@
+package run
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotReady is returned when waiting for an instance
+// that terminates before becoming ready.
+var ErrNotReady = errors.New("instance terminated before becoming ready")
+
+// readyKey is the context key under which the executing instance
+// is stored, for its runnable to report readiness.
+type readyKey struct{}
+
+// AwaitReady indicates whether an instance becomes ready only once
+// its runnable reports readiness through Ready, rather than
+// once its first execution starts (default: false).
+func AwaitReady(await bool) Option {
+	return func(o *options) *options {
+		o.awaitReady = await
+		return o
+	}
+}
+
+// awaitsReady indicates whether the runnable reports its readiness.
+func (o *options) awaitsReady() bool {
+	return (o != nil) && o.awaitReady
+}
+
+// Ready reports that the instance executing a runnable
+// with the provided context is ready.
+//
+// It has no effect if the instance is already ready,
+// or if the context does not belong to an execution.
+func Ready(ctx context.Context) {
+	if i, ok := ctx.Value(readyKey{}).(*Instance); ok {
+		i.markReady()
+	}
+}
+
+// WaitReady blocks until an instance becomes ready (see AwaitReady),
+// returning ErrNotReady if it terminates before that,
+// or the context error if the provided context is done first.
+func (i *Instance) WaitReady(ctx context.Context) error {
+	ready, done := i.readiness()
+
+	select {
+	case <-ready:
+		return nil
+	case <-done:
+		// Readiness takes precedence, since select picks randomly.
+		select {
+		case <-ready:
+			return nil
+		default:
+			return ErrNotReady
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// readiness returns the channels closed once an instance
+// becomes ready and terminates respectively.
+func (i *Instance) readiness() (ready, done <-chan struct{}) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.initReadiness()
+	return i.ready, i.done
+}
+
+// initReadiness creates the readiness channels of an instance (if needed).
+// The lock of the instance should be held.
+func (i *Instance) initReadiness() {
+	if i.ready == nil {
+		i.ready = make(chan struct{})
+		i.done = make(chan struct{})
+	}
+}
+
+// markReady marks an instance as ready.
+func (i *Instance) markReady() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.initReadiness()
+	select {
+	case <-i.ready:
+	default:
+		close(i.ready)
+	}
+}
+
+// markDone marks an instance as terminated.
+func (i *Instance) markDone() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.initReadiness()
+	close(i.done)
+}
+
+// WaitReady blocks until all members of a group are ready (see AwaitReady),
+// returning a MemberError with ErrNotReady if any of them
+// terminates before that, or the context error if the provided context
+// is done first (e.g. due to a timeout).
+//
+// Only the members of the group at the time of the call are waited for.
+func (g *Group) WaitReady(ctx context.Context) error {
+	g.mu.Lock()
+	members := make([]member, 0, len(g.members))
+	for _, m := range g.members {
+		members = append(members, member{name: m.name, inst: m.inst})
+	}
+	g.mu.Unlock()
+
+	for _, m := range members {
+		switch err := m.inst.WaitReady(ctx); err {
+		case nil:
+		case ErrNotReady:
+			return MemberError{Member: m.name, Err: err}
+		default:
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testReady(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"AwaitReady": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{AwaitReady(true)})
+			as.Equal(&options{awaitReady: true}, opts)
+			as.True(opts.awaitsReady())
+
+			opts = nil
+			as.False(opts.awaitsReady())
+		},
+		"ready once executing": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}, InitialDelay(2*testTimeDelta))
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			errCh := inst.Run(ctx)
+
+			waitCtx, waitCancel := context.WithTimeout(ctx, testTimeDelta)
+			defer waitCancel()
+			as.Equal(context.DeadlineExceeded, inst.WaitReady(waitCtx))
+			as.NoError(inst.WaitReady(ctx))
+
+			cancel()
+			waitErrors(errCh)
+			as.NoError(inst.WaitReady(context.TODO()))
+		},
+		"ready once reported": func(t *testing.T) {
+			as := newAssertions(t)
+
+			reported := make(chan struct{})
+			inst := New(func(ctx context.Context) error {
+				time.Sleep(testTimeDelta)
+				close(reported)
+				Ready(ctx)
+				Ready(ctx)
+				<-ctx.Done()
+				return nil
+			}, AwaitReady(true))
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			errCh := inst.Run(ctx)
+
+			as.NoError(inst.WaitReady(ctx))
+			select {
+			case <-reported:
+			default:
+				as.Fail("ready before reporting")
+			}
+
+			cancel()
+			waitErrors(errCh)
+		},
+		"terminated before ready": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, AwaitReady(true))
+			waitErrors(inst.Run(context.TODO()))
+
+			as.Equal(ErrNotReady, inst.WaitReady(context.TODO()))
+		},
+		"reporting outside of an execution": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.NotPanics(func() {
+				Ready(context.TODO())
+			})
+		},
+		"group readiness": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				time.Sleep(testTimeDelta)
+				Ready(ctx)
+				<-ctx.Done()
+				return nil
+			}, AwaitReady(true)))
+			as.NoError(g.Add("b", func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}))
+
+			errCh := g.Run(context.TODO())
+			start := time.Now()
+			as.NoError(g.WaitReady(context.TODO()))
+			as.GreaterOrEqual(time.Since(start), testTimeDelta)
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"group members terminated before ready": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(context.Context) error {
+				return nil
+			}, AwaitReady(true)))
+
+			errCh := g.Run(context.TODO())
+			as.Equal(MemberError{Member: "a", Err: ErrNotReady},
+				g.WaitReady(context.TODO()))
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"group readiness timeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}, AwaitReady(true)))
+
+			errCh := g.Run(context.TODO())
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+			as.Equal(context.DeadlineExceeded, g.WaitReady(ctx))
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"ordered groups start members once ready": func(t *testing.T) {
+			as := newAssertions(t)
+
+			l := new(orderLog)
+			g := NewGroup(Ordered(true))
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				l.add("start a")
+				time.Sleep(testTimeDelta)
+				l.add("ready a")
+				Ready(ctx)
+				<-ctx.Done()
+				return nil
+			}, AwaitReady(true)))
+			as.NoError(g.Add("b", func(ctx context.Context) error {
+				l.add("start b")
+				<-ctx.Done()
+				return nil
+			}))
+
+			errCh := g.Run(context.TODO())
+			as.NoError(g.WaitReady(context.TODO()))
+			g.Stop()
+
+			as.Empty(waitErrors(errCh))
+			as.Equal([]string{"start a", "ready a", "start b"}, l.list())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
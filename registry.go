@@ -0,0 +1,222 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrDuplicateName is the error an instance terminates with
+// when run while another instance is registered under its name
+// in its registry (see WithRegistry).
+var ErrDuplicateName = errors.New("duplicate instance name")
+
+// registryKey is the context key under which the executing instance
+// is stored, for its runnable to access its identity
+// (see NameFromContext).
+type registryKey struct{}
+
+// Registry keeps track of running named instances (see WithName),
+// providing a central handle on them.
+type Registry struct {
+	mu        sync.Mutex
+	instances map[string]*Instance
+}
+
+// DefaultRegistry is a process-wide registry, for named instances
+// to be registered in through WithRegistry(DefaultRegistry).
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{instances: make(map[string]*Instance)}
+}
+
+// WithName sets the name of an instance (default: unnamed),
+// identifying it to its runnable (see NameFromContext)
+// and integrations, such as profiling (see Profiling),
+// as well as in its registry, if any (see WithRegistry).
+//
+// Names need not be unique, unless the instances are registered
+// in the same registry.
+func WithName(name string) Option {
+	return func(o *options) *options {
+		o.name = name
+		return o
+	}
+}
+
+// WithLabels sets the labels of an instance,
+// by which registered instances can be selected,
+// and which are available to its runnable (see LabelsFromContext).
+func WithLabels(labels map[string]string) Option {
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+
+	return func(o *options) *options {
+		o.labels = copied
+		return o
+	}
+}
+
+// Name returns the name of an instance (see WithName),
+// or the empty string if it is unnamed.
+func (i *Instance) Name() string {
+	if i.options() == nil {
+		return ""
+	}
+	return i.options().name
+}
+
+// Labels returns a copy of the labels of an instance (see WithLabels).
+func (i *Instance) Labels() map[string]string {
+	labels := make(map[string]string)
+	if i.options() != nil {
+		for k, v := range i.options().labels {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// NameFromContext returns the name of the instance executing
+// with the provided context (see WithName),
+// and whether it was executed by an instance.
+func NameFromContext(ctx context.Context) (string, bool) {
+	i, ok := ctx.Value(registryKey{}).(*Instance)
+	if !ok {
+		return "", false
+	}
+	return i.Name(), true
+}
+
+// LabelsFromContext returns a copy of the labels of the instance executing
+// with the provided context (see WithLabels), or nil if it was not executed
+// by an instance.
+func LabelsFromContext(ctx context.Context) map[string]string {
+	i, ok := ctx.Value(registryKey{}).(*Instance)
+	if !ok {
+		return nil
+	}
+	return i.Labels()
+}
+
+// WithRegistry sets the registry a named instance is registered in
+// under its name while running (default: nil, not registered).
+//
+// An instance run while another one is registered under its name
+// in the same registry terminates immediately with ErrDuplicateName
+// (see NameConflict), without executing its runnable.
+func WithRegistry(reg *Registry) Option {
+	return func(o *options) *options {
+		o.registry = reg
+		return o
+	}
+}
+
+// register registers an instance in its registry, if it is named
+// and has one, and returns a function unregistering it,
+// or ErrDuplicateName if its name is already in use.
+func (i *Instance) register() (func(), error) {
+	opts := i.options()
+	if opts == nil || opts.name == "" || opts.registry == nil {
+		return func() {}, nil
+	}
+
+	reg, name := opts.registry, opts.name
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if _, ok := reg.instances[name]; ok {
+		return func() {}, fmt.Errorf("%w: %q", ErrDuplicateName, name)
+	}
+	reg.instances[name] = i
+
+	return func() {
+		reg.mu.Lock()
+		defer reg.mu.Unlock()
+
+		delete(reg.instances, name)
+	}, nil
+}
+
+// Lookup returns the instance registered under the provided name,
+// and whether there is such an instance.
+func (r *Registry) Lookup(name string) (*Instance, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inst, ok := r.instances[name]
+	return inst, ok
+}
+
+// Names returns the names of the registered instances, in order.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.instances))
+	for name := range r.instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Range calls the provided function for each registered instance
+// in order of name, until it returns false.
+//
+// The registry is not locked during the calls, so instances registered
+// or unregistered in the meantime may or may not be visited.
+func (r *Registry) Range(fn func(name string, inst *Instance) bool) {
+	for _, name := range r.Names() {
+		inst, ok := r.Lookup(name)
+		if !ok {
+			continue
+		}
+		if !fn(name, inst) {
+			return
+		}
+	}
+}
+
+// Select returns the registered instances carrying
+// all of the provided labels, by name.
+func (r *Registry) Select(labels map[string]string) map[string]*Instance {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	selected := make(map[string]*Instance)
+	for name, inst := range r.instances {
+		if inst.options().matches(labels) {
+			selected[name] = inst
+		}
+	}
+	return selected
+}
+
+// Stop stops the registered instances carrying
+// all of the provided labels (all of them, if none is provided).
+//
+// Stop does not wait for the instances to terminate.
+func (r *Registry) Stop(labels map[string]string) {
+	for _, inst := range r.Select(labels) {
+		inst.Stop()
+	}
+}
+
+// matches indicates whether the labels of a runnable
+// include all of the provided ones.
+func (o *options) matches(labels map[string]string) bool {
+	for k, v := range labels {
+		if l, ok := o.labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
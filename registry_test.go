@@ -0,0 +1,204 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testRegistry(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"options": func(t *testing.T) {
+			as := newAssertions(t)
+
+			labels := map[string]string{"k": "v"}
+			reg := NewRegistry()
+			opts := apply(t, new(options), []Option{
+				WithName("a"), WithLabels(labels), WithRegistry(reg),
+			})
+			labels["k"] = "w"
+			as.Equal(&options{
+				name:     "a",
+				labels:   map[string]string{"k": "v"},
+				registry: reg,
+			}, opts)
+
+			as.True(opts.matches(nil))
+			as.True(opts.matches(map[string]string{"k": "v"}))
+			as.False(opts.matches(map[string]string{"k": "w"}))
+			as.False(opts.matches(map[string]string{"l": "v"}))
+		},
+		"identity": func(t *testing.T) {
+			as := newAssertions(t)
+
+			labels := map[string]string{"k": "v"}
+			inst := New(func(ctx context.Context) error {
+				name, ok := NameFromContext(ctx)
+				as.True(ok)
+				as.Equal("a", name)
+				as.Equal(labels, LabelsFromContext(ctx))
+				return nil
+			}, WithName("a"), WithLabels(labels), WithRegistry(NewRegistry()))
+
+			as.Equal("a", inst.Name())
+			as.Equal(labels, inst.Labels())
+			inst.Labels()["k"] = "w"
+			as.Equal(labels, inst.Labels())
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+
+			unnamed := &Instance{}
+			as.Empty(unnamed.Name())
+			as.Empty(unnamed.Labels())
+			_, ok := NameFromContext(context.TODO())
+			as.False(ok)
+			as.Nil(LabelsFromContext(context.TODO()))
+		},
+		"registered while running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			reg := NewRegistry()
+			newBlocking := func(name, tier string) Instance {
+				return New(func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				}, WithName(name), WithRegistry(reg),
+					WithLabels(map[string]string{"tier": tier}))
+			}
+			a, b, c := newBlocking("a", "web"), newBlocking("b", "db"),
+				newBlocking("c", "web")
+
+			errChs := []<-chan error{
+				a.Run(context.TODO()), b.Run(context.TODO()), c.Run(context.TODO()),
+			}
+			for _, inst := range []*Instance{&a, &b, &c} {
+				as.NoError(inst.WaitReady(context.TODO()))
+			}
+
+			as.Equal([]string{"a", "b", "c"}, reg.Names())
+			inst, ok := reg.Lookup("b")
+			as.True(ok)
+			as.Same(&b, inst)
+
+			var visited []string
+			reg.Range(func(name string, inst *Instance) bool {
+				visited = append(visited, name)
+				return name != "b"
+			})
+			as.Equal([]string{"a", "b"}, visited)
+
+			as.Equal(map[string]*Instance{"a": &a, "c": &c},
+				reg.Select(map[string]string{"tier": "web"}))
+
+			reg.Stop(map[string]string{"tier": "web"})
+			waitErrors(errChs[0])
+			waitErrors(errChs[2])
+			as.Equal([]string{"b"}, reg.Names())
+			_, ok = reg.Lookup("a")
+			as.False(ok)
+
+			reg.Stop(nil)
+			waitErrors(errChs[1])
+			as.Empty(reg.Names())
+		},
+		"duplicate names": func(t *testing.T) {
+			as := newAssertions(t)
+
+			reg := NewRegistry()
+			first := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}, WithName("a"), WithRegistry(reg))
+			second := New(func(context.Context) error {
+				as.Fail("runnable of duplicate instance executed")
+				return nil
+			}, WithName("a"), WithRegistry(reg))
+
+			firstCh := first.Run(context.TODO())
+			as.NoError(first.WaitReady(context.TODO()))
+
+			errs := waitErrors(second.Run(context.TODO()))
+			if as.Len(errs, 1) {
+				as.ErrorIs(errs[0], ErrDuplicateName)
+			}
+			as.Equal(NameConflict, second.TerminationReason())
+
+			// The conflicting instance does not unregister the registered one.
+			inst, ok := reg.Lookup("a")
+			as.True(ok)
+			as.Same(&first, inst)
+
+			first.Stop()
+			waitErrors(firstCh)
+			as.Empty(reg.Names())
+		},
+		"instances unregistered while ranging": func(t *testing.T) {
+			as := newAssertions(t)
+
+			reg := NewRegistry()
+			a := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}, WithName("a"), WithRegistry(reg))
+			b := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}, WithName("b"), WithRegistry(reg))
+
+			aCh, bCh := a.Run(context.TODO()), b.Run(context.TODO())
+			as.NoError(a.WaitReady(context.TODO()))
+			as.NoError(b.WaitReady(context.TODO()))
+
+			var visited []string
+			reg.Range(func(name string, _ *Instance) bool {
+				visited = append(visited, name)
+				b.Stop()
+				waitErrors(bCh)
+				return true
+			})
+			as.Equal([]string{"a"}, visited)
+
+			a.Stop()
+			waitErrors(aCh)
+		},
+		"default registry": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var found bool
+			err := Do(context.TODO(), func(context.Context) error {
+				_, found = DefaultRegistry.Lookup("default registry test")
+				return nil
+			}, WithName("default registry test"), WithRegistry(DefaultRegistry))
+			as.NoError(err)
+			as.True(found)
+
+			_, found = DefaultRegistry.Lookup("default registry test")
+			as.False(found)
+		},
+		"unregistered by default": func(t *testing.T) {
+			as := newAssertions(t)
+
+			// Instances sharing a name run alongside each other
+			// unless registered in the same registry.
+			started := make(chan struct{}, 2)
+			blueprint := New(func(ctx context.Context) error {
+				_, found := DefaultRegistry.Lookup("unregistered")
+				as.False(found)
+				started <- struct{}{}
+				<-ctx.Done()
+				return nil
+			}, WithName("unregistered"))
+			a, b := blueprint.Clone(), blueprint.Clone()
+
+			aCh, bCh := a.Run(context.TODO()), b.Run(context.TODO())
+			<-started
+			<-started
+			a.Stop()
+			b.Stop()
+			as.Empty(waitErrors(aCh))
+			as.Empty(waitErrors(bCh))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,88 @@
+package run
+
+import (
+	"context"
+	"time"
+)
+
+// Reporter reports the failures of runnables to an external service,
+// such as an error tracker.
+//
+// Adapters for error tracking clients (e.g. Sentry or Rollbar)
+// are expected to be implemented by users, so that this package
+// remains free of dependencies, e.g.
+//
+//	run.ReporterFunc(func(ctx context.Context, err error, meta run.ReportMeta) {
+//		event := tracker.NewEvent(err)
+//		event.Tags["instance"] = meta.Name
+//		event.Extra["attempt"] = meta.Attempt.Number
+//		if meta.Panicked {
+//			event.Stacktrace = meta.Stack
+//		}
+//		tracker.Capture(ctx, event)
+//	})
+type Reporter interface {
+	// Report reports the failure of an execution of a runnable
+	// with the provided metadata. It is called synchronously
+	// during the execution of the instance, so it should not block.
+	Report(ctx context.Context, err error, meta ReportMeta)
+}
+
+// ReporterFunc is an adapter allowing the use of a function as Reporter.
+type ReporterFunc func(ctx context.Context, err error, meta ReportMeta)
+
+// Report satisfies Reporter interface for ReporterFunc.
+func (f ReporterFunc) Report(ctx context.Context, err error, meta ReportMeta) {
+	f(ctx, err, meta)
+}
+
+// ReportMeta describes a failed execution of a runnable (see Reporter).
+type ReportMeta struct {
+	// Name and Labels are the name and labels of the instance
+	// (see WithName and WithLabels).
+	Name   string
+	Labels map[string]string
+	// Attempt describes the failed execution.
+	Attempt Attempt
+	// Duration is the duration of the execution.
+	Duration time.Duration
+	// Panicked indicates whether the execution panicked
+	// (see Recover and RecoverWith),
+	// in which case Stack holds the stack trace of the goroutine
+	// the panic was recovered on.
+	Panicked bool
+	Stack    []byte
+}
+
+// WithReporter sets the reporter of the failures of an instance
+// (default: nil, failures are not reported).
+//
+// It is provided with the error of each failed execution,
+// as propagated to the error channel, and each RunnablePanic
+// recovered from (see Recover), along with the context of the instance
+// carrying the attempt (see AttemptFromContext).
+func WithReporter(reporter Reporter) Option {
+	return func(o *options) *options {
+		o.reporter = reporter
+		return o
+	}
+}
+
+// report reports the failure of the latest execution of a copy
+// of the runnable of an instance to its reporter, if any.
+func (i *Instance) report(ctx context.Context, err error, w *worker,
+	elapsed time.Duration, stack []byte) {
+
+	opts := i.options()
+	if opts == nil || opts.reporter == nil {
+		return
+	}
+	opts.reporter.Report(withAttempt(ctx, w.attempt), err, ReportMeta{
+		Name:     opts.name,
+		Labels:   i.Labels(),
+		Attempt:  w.attempt,
+		Duration: elapsed,
+		Panicked: stack != nil,
+		Stack:    stack,
+	})
+}
@@ -0,0 +1,85 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testReport(t *testing.T) {
+	type report struct {
+		err     error
+		attempt Attempt
+		meta    ReportMeta
+	}
+	// collect returns a reporter recording its reports.
+	collect := func(reports *[]report) Reporter {
+		return ReporterFunc(func(ctx context.Context, err error, meta ReportMeta) {
+			attempt, _ := AttemptFromContext(ctx)
+			*reports = append(*reports, report{err: err, attempt: attempt, meta: meta})
+		})
+	}
+
+	subtests := map[string]func(*testing.T){
+		"failures": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var reports []report
+			runs := 0
+			inst := New(func(context.Context) error {
+				if runs++; runs <= 2 {
+					return testError(runs)
+				}
+				return nil
+			}, Restart(true), WithName("sync"),
+				WithLabels(map[string]string{"team": "infra"}),
+				WithReporter(collect(&reports)))
+
+			as.Equal([]error{testError(1), testError(2)},
+				waitErrors(inst.Run(context.TODO())))
+			as.Len(reports, 2)
+			for idx, rep := range reports {
+				as.Equal(testError(idx+1), rep.err)
+				as.Equal(rep.meta.Attempt, rep.attempt)
+				as.Equal(uint64(idx+1), rep.meta.Attempt.Number)
+				as.Equal(uint64(idx), rep.meta.Attempt.ConsecutiveFailures)
+				as.Equal("sync", rep.meta.Name)
+				as.Equal(map[string]string{"team": "infra"}, rep.meta.Labels)
+				as.False(rep.meta.Panicked)
+				as.Nil(rep.meta.Stack)
+			}
+		},
+		"recovered panics": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var reports []report
+			inst := New(func(context.Context) error {
+				panic("boom")
+			}, Recover(true), WithReporter(collect(&reports)))
+
+			as.Equal([]error{RunnablePanic{Value: "boom"}},
+				waitErrors(inst.Run(context.TODO())))
+			if as.Len(reports, 1) {
+				rep := reports[0]
+				as.Equal(RunnablePanic{Value: "boom"}, rep.err)
+				as.Equal(uint64(1), rep.attempt.Number)
+				as.True(rep.meta.Panicked)
+				as.Contains(string(rep.meta.Stack), "testReport")
+			}
+		},
+		"successful executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var reports []report
+			inst := New(func(context.Context) error {
+				return nil
+			}, WithReporter(collect(&reports)))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Empty(reports)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,57 @@
+package run
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotTerminated is returned when resetting an instance
+// that has not terminated yet.
+var ErrNotTerminated = errors.New("instance has not terminated")
+
+// Reset restores an instance that has terminated to its initial state,
+// clearing its execution statistics, counters and terminal error,
+// so that it can be run again with the same runnable and options.
+// Resetting an instance that has not been run has no effect.
+//
+// It returns ErrNotTerminated if the instance has been run
+// but has not terminated yet (see Done). It should not be called
+// concurrently with running the instance, and only once the channel
+// returned when running it has been closed.
+// Whether the instance is paused is preserved.
+func (i *Instance) Reset() error {
+	// Consuming the once-guard indicates that the instance has not been run.
+	fresh := false
+	i.once.Do(func() { fresh = true })
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !fresh {
+		i.initReadiness()
+		select {
+		case <-i.done:
+		default:
+			return ErrNotTerminated
+		}
+	}
+
+	i.stats = Stats{}
+	i.since = time.Time{}
+	i.history = nil
+	i.rate = nil
+	i.watchers = nil
+	i.subscribers = nil
+	i.runs.Store(0)
+	i.failedRuns.Store(0)
+	i.timeouts.Store(0)
+	i.attempts.Store(0)
+	i.stopped, i.cancel, i.stopCh = false, nil, nil
+	i.ready, i.done = nil, nil
+	i.trigger, i.lastTriggered = nil, time.Time{}
+	i.restored = time.Time{}
+	i.err, i.ended = nil, 0
+	i.once = sync.Once{}
+	return nil
+}
@@ -0,0 +1,93 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testReset(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"not run": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			})
+			as.NoError(inst.Reset())
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(uint64(1), inst.Stats().Runs)
+		},
+		"runs again": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(ctx context.Context) error {
+				runs++
+				Ready(ctx)
+				<-ctx.Done()
+				return testError(runs)
+			}, AwaitReady(true))
+
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			as.Equal(ErrNotTerminated, inst.Reset())
+			inst.Stop()
+			as.Equal([]error{testError(1)}, waitErrors(errCh))
+			as.Nil(inst.Run(context.TODO()))
+			_, err := inst.TryRun(context.TODO())
+			as.Equal(ErrAlreadyRunning, err)
+
+			as.NoError(inst.Reset())
+			as.Equal(StateIdle, inst.State())
+			as.Zero(inst.Stats())
+			as.NoError(inst.Err())
+			as.Zero(inst.TerminationReason())
+			select {
+			case <-inst.Done():
+				as.Fail("reset instance done")
+			default:
+			}
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			errCh, err = inst.TryRun(ctx)
+			as.NoError(err)
+			as.NoError(inst.WaitReady(context.TODO()))
+			cancel()
+			as.Equal([]error{testError(2)}, waitErrors(errCh))
+			as.Equal(uint64(1), inst.Stats().FailedRuns)
+			as.Equal(Failed, inst.TerminationReason())
+		},
+		"typed": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := NewTyped(func(ctx context.Context) (int, error) {
+				if runs++; runs > 2 {
+					Ready(ctx)
+					<-ctx.Done()
+					return 0, ctx.Err()
+				}
+				return runs, nil
+			}, WithChanBuffer(1), AwaitReady(true))
+
+			for n := 1; n <= 2; n++ {
+				as.NoError(inst.Reset())
+				as.Empty(waitErrors(inst.Run(context.TODO())))
+				as.Equal(n, <-inst.Results())
+				_, ok := <-inst.Results()
+				as.False(ok)
+			}
+
+			as.NoError(inst.Reset())
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			as.Equal(ErrNotTerminated, inst.Reset())
+			inst.Stop()
+			waitErrors(errCh)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,134 @@
+package run
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrGroupNotRunning is returned when restarting the members
+// of a group that has not been run.
+var ErrGroupNotRunning = errors.New("group not running")
+
+// RollingPolicy determines how the members of a group
+// are restarted by RollingRestart.
+type RollingPolicy struct {
+	// BatchSize is the number of members restarted at a time,
+	// with values less than 1 treated as 1.
+	BatchSize int
+	// Options are applied to the restarted instances of the members,
+	// on top of their existing ones (see Instance.Clone),
+	// e.g. to reload their configuration.
+	Options []Option
+}
+
+// rollout represents the restart of a member by a rolling restart.
+type rollout struct {
+	opts []Option
+	// done is closed once the member is restarted (or not, if relaunched
+	// is false, since it was removed or the group terminated in the meantime).
+	done       chan struct{}
+	relaunched bool
+}
+
+// RollingRestart restarts the running members of a group in batches
+// (in the order they were added), stopping each member
+// and starting a new instance with its runnable and options
+// once it terminates, as the group does when supervising it.
+// Each batch is restarted once the members of the previous one are ready
+// (see AwaitReady), so that the rest of the members keep running.
+// Members removed in the meantime are skipped.
+//
+// It returns ErrGroupNotRunning or ErrGroupTerminated if the group
+// is not running, a MemberError with ErrNotReady if a restarted member
+// terminates before becoming ready, or the context error
+// if the provided context is done first, in which case the rest
+// of the members are not restarted.
+func (g *Group) RollingRestart(ctx context.Context, policy RollingPolicy) error {
+	size := max(policy.BatchSize, 1)
+
+	g.mu.Lock()
+	members := append([]*member(nil), g.members...)
+	g.mu.Unlock()
+
+	for start := 0; start < len(members); start += size {
+		batch := members[start:min(start+size, len(members))]
+		rollouts, err := g.roll(batch, policy.Options)
+		if err != nil {
+			return err
+		}
+
+		for idx, ro := range rollouts {
+			if ro == nil {
+				continue
+			}
+			select {
+			case <-ro.done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			g.mu.Lock()
+			inst, relaunched, removed := batch[idx].inst, ro.relaunched, batch[idx].removed
+			g.mu.Unlock()
+			switch {
+			case removed:
+				continue
+			case !relaunched:
+				return ErrGroupTerminated
+			}
+			switch err := inst.WaitReady(ctx); err {
+			case nil:
+			case ErrNotReady:
+				return MemberError{Member: batch[idx].name, Err: err}
+			default:
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// roll stops the running members among the provided ones,
+// to be restarted with the provided options once they terminate,
+// and returns their rollouts (nil for the members not running).
+func (g *Group) roll(members []*member, opts []Option) ([]*rollout, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s := g.sup
+	switch {
+	case s == nil:
+		return nil, ErrGroupNotRunning
+	case g.stopped || s.active == 0:
+		return nil, ErrGroupTerminated
+	}
+
+	rollouts := make([]*rollout, len(members))
+	for idx, m := range members {
+		if !m.running || m.removed || s.rolling[m] != nil {
+			continue
+		}
+		ro := &rollout{opts: opts, done: make(chan struct{})}
+		rollouts[idx] = ro
+		s.rolling[m] = ro
+		m.inst.Stop()
+	}
+	return rollouts, nil
+}
+
+// relaunch restarts a member stopped by a rolling restart,
+// unless it was removed or the group terminated in the meantime.
+// The lock of the group should be held.
+func (s *supervisor) relaunch(m *member) {
+	ro := s.rolling[m]
+	delete(s.rolling, m)
+	defer close(ro.done)
+
+	if m.removed || s.pending[m] || s.g.stopped || s.exhausted || s.ctx.Err() != nil {
+		return
+	}
+	clone := m.inst.Clone(ro.opts...)
+	m.inst = &clone
+	ro.relaunched = true
+	s.launch([]*member{m})
+}
@@ -0,0 +1,271 @@
+package run
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testRolling(t *testing.T) {
+	// event records the start or termination of the runnable of a member.
+	type event struct {
+		member string
+		start  bool
+	}
+	var mu sync.Mutex
+	var events []event
+	record := func(e event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}
+	// worker returns a runnable becoming ready after the provided delay,
+	// recording its events.
+	worker := func(name string, delay time.Duration) Runnable {
+		return func(ctx context.Context) error {
+			record(event{member: name, start: true})
+			defer record(event{member: name})
+			select {
+			case <-time.After(delay):
+				Ready(ctx)
+			case <-ctx.Done():
+			}
+			<-ctx.Done()
+			return nil
+		}
+	}
+	// lingering returns a runnable lingering for the provided delay once stopped,
+	// which closes the provided channel when it first runs.
+	lingering := func(started chan struct{}, delay time.Duration) Runnable {
+		var once sync.Once
+		return func(ctx context.Context) error {
+			once.Do(func() { close(started) })
+			<-ctx.Done()
+			time.Sleep(delay)
+			return nil
+		}
+	}
+	reset := func() []event {
+		mu.Lock()
+		defer mu.Unlock()
+		recorded := events
+		events = nil
+		return recorded
+	}
+
+	subtests := map[string]func(*testing.T){
+		"one at a time": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			for _, name := range []string{"a", "b", "c"} {
+				as.NoError(g.Add(name, worker(name, 0), AwaitReady(true)))
+			}
+			errCh := g.Run(context.TODO())
+			as.NoError(g.WaitReady(context.TODO()))
+			first, _ := g.Member("b")
+			reset()
+
+			as.NoError(g.RollingRestart(context.TODO(), RollingPolicy{}))
+			as.Equal([]event{
+				{member: "a"}, {member: "a", start: true},
+				{member: "b"}, {member: "b", start: true},
+				{member: "c"}, {member: "c", start: true},
+			}, reset())
+			inst, _ := g.Member("b")
+			as.NotSame(first, inst)
+			as.NoError(inst.WaitReady(context.TODO()))
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"batches": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			for _, name := range []string{"a", "b", "c"} {
+				as.NoError(g.Add(name, worker(name, testTimeDelta), AwaitReady(true)))
+			}
+			errCh := g.Run(context.TODO())
+			as.NoError(g.WaitReady(context.TODO()))
+			reset()
+
+			start := time.Now()
+			as.NoError(g.RollingRestart(context.TODO(), RollingPolicy{BatchSize: 2}))
+			elapsed := time.Since(start)
+			as.GreaterOrEqual(elapsed, 2*testTimeDelta)
+			as.Less(elapsed, 3*testTimeDelta)
+
+			recorded := reset()
+			as.Len(recorded, 6)
+			as.ElementsMatch([]event{
+				{member: "a"}, {member: "a", start: true},
+				{member: "b"}, {member: "b", start: true},
+			}, recorded[:4])
+			as.Equal([]event{
+				{member: "c"}, {member: "c", start: true},
+			}, recorded[4:])
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"options applied": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", worker("a", 0), AwaitReady(true), WithName("v1")))
+			errCh := g.Run(context.TODO())
+
+			as.NoError(g.RollingRestart(context.TODO(), RollingPolicy{
+				Options: []Option{WithName("v2")},
+			}))
+			inst, _ := g.Member("a")
+			as.Equal("v2", inst.Name())
+			as.True(inst.options().awaitsReady())
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"members not running skipped": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(context.Context) error {
+				return nil
+			}))
+			as.NoError(g.Add("b", worker("b", 0), AwaitReady(true)))
+			errCh := g.Run(context.TODO())
+			as.NoError(g.WaitReady(context.TODO()))
+			a, _ := g.Member("a")
+			<-a.Done()
+			// Allow the group to observe the termination of the member.
+			time.Sleep(testTimeDelta)
+			reset()
+
+			as.NoError(g.RollingRestart(context.TODO(), RollingPolicy{}))
+			as.Equal([]event{{member: "b"}, {member: "b", start: true}}, reset())
+			inst, _ := g.Member("a")
+			as.Same(a, inst)
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"restarted member removed": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			started := make(chan struct{})
+			as.NoError(g.Add("a", lingering(started, testTimeDelta)))
+			as.NoError(g.Add("b", worker("b", 0), AwaitReady(true)))
+			errCh := g.Run(context.TODO())
+			<-started
+			as.NoError(g.WaitReady(context.TODO()))
+			reset()
+
+			go func() {
+				time.Sleep(testTimeDelta / 2)
+				as.NoError(g.Remove("a"))
+			}()
+			as.NoError(g.RollingRestart(context.TODO(), RollingPolicy{}))
+			as.Equal([]event{{member: "b"}, {member: "b", start: true}}, reset())
+			as.Equal([]string{"b"}, g.Members())
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"group not running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", worker("a", 0)))
+			as.Equal(ErrGroupNotRunning,
+				g.RollingRestart(context.TODO(), RollingPolicy{}))
+		},
+		"group terminated": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", worker("a", 0)))
+			errCh := g.Run(context.TODO())
+			g.Stop()
+			as.Equal(ErrGroupTerminated,
+				g.RollingRestart(context.TODO(), RollingPolicy{}))
+			as.Empty(waitErrors(errCh))
+		},
+		"group stopped while restarting": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			started := make(chan struct{})
+			as.NoError(g.Add("a", lingering(started, testTimeDelta)))
+			errCh := g.Run(context.TODO())
+			<-started
+
+			go func() {
+				time.Sleep(testTimeDelta / 2)
+				g.Stop()
+			}()
+			as.Equal(ErrGroupTerminated,
+				g.RollingRestart(context.TODO(), RollingPolicy{}))
+			as.Empty(waitErrors(errCh))
+		},
+		"member terminated before ready": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			started := make(chan struct{})
+			first := lingering(started, 0)
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				select {
+				case <-started:
+					return nil
+				default:
+					return first(ctx)
+				}
+			}, AwaitReady(true)))
+			errCh := g.Run(context.TODO())
+			<-started
+
+			as.Equal(MemberError{Member: "a", Err: ErrNotReady},
+				g.RollingRestart(context.TODO(), RollingPolicy{}))
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"context done": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", worker("a", time.Hour), AwaitReady(true)))
+			errCh := g.Run(context.TODO())
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+			as.Equal(context.DeadlineExceeded, g.RollingRestart(ctx, RollingPolicy{}))
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"context done while stopping": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			started := make(chan struct{})
+			as.NoError(g.Add("a", lingering(started, 2*testTimeDelta)))
+			errCh := g.Run(context.TODO())
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+			as.Equal(context.DeadlineExceeded, g.RollingRestart(ctx, RollingPolicy{}))
+
+			g.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+	}
+
+	for name, test := range subtests {
+		reset()
+		t.Run(name, test)
+	}
+}
@@ -8,12 +8,85 @@ import (
 )
 
 var tests = map[string]func(*testing.T){
-	"constants": testConstants,
-	"panic":     testRunnablePanic,
-	"runnable":  testRunnable,
-	"options":   testOptions,
-	"instance":  testInstance,
-	"new":       testNew,
+	"constants":    testConstants,
+	"panic":        testRunnablePanic,
+	"runnable":     testRunnable,
+	"options":      testOptions,
+	"instance":     testInstance,
+	"new":          testNew,
+	"metrics":      testMetrics,
+	"middleware":   testMiddleware,
+	"events":       testEvents,
+	"state":        testState,
+	"stats":        testStats,
+	"history":      testHistory,
+	"attempt":      testAttempt,
+	"typed":        testTyped,
+	"do":           testDo,
+	"cron":         testCron,
+	"schedule":     testSchedule,
+	"window":       testWindow,
+	"overrun":      testOverrun,
+	"concurrency":  testConcurrency,
+	"limiter":      testLimiter,
+	"group":        testGroup,
+	"supervisor":   testSupervisor,
+	"order":        testOrder,
+	"ready":        testReady,
+	"actor":        testActor,
+	"signal":       testSignal,
+	"shutdown":     testShutdown,
+	"pause":        testPause,
+	"registry":     testRegistry,
+	"health":       testHealth,
+	"heartbeat":    testHeartbeat,
+	"command":      testCommand,
+	"service":      testService,
+	"parallel":     testParallel,
+	"budget":       testBudget,
+	"clock":        testClock,
+	"overflow":     testOverflow,
+	"onerror":      testOnError,
+	"termination":  testTermination,
+	"runerror":     testRunError,
+	"timeout":      testTimeout,
+	"cause":        testCause,
+	"detach":       testDetach,
+	"trigger":      testTrigger,
+	"reset":        testReset,
+	"blocking":     testBlocking,
+	"classify":     testClassify,
+	"validate":     testValidate,
+	"precondition": testPrecondition,
+	"gate":         testGating,
+	"singleflight": testSingleflight,
+	"store":        testStore,
+	"catchup":      testCatchUp,
+	"coordinate":   testCoordinate,
+	"tasks":        testTasks,
+	"sleep":        testSleep,
+	"progress":     testProgress,
+	"profiling":    testProfiling,
+	"presets":      testPresets,
+	"check":        testCheck,
+	"config":       testConfig,
+	"update":       testUpdate,
+	"describe":     testDescribe,
+	"settings":     testSettings,
+	"builder":      testBuilder,
+	"errorpolicy":  testErrorPolicy,
+	"snapshot":     testSnapshot,
+	"rolling":      testRolling,
+	"child":        testChild,
+	"quorum":       testQuorum,
+	"subscribe":    testSubscribe,
+	"drain":        testDrain,
+	"report":       testReport,
+	"alarm":        testAlarm,
+	"dedupe":       testDedupe,
+	"slow":         testSlow,
+	"unresponsive": testUnresponsive,
+	"abandon":      testAbandon,
 }
 
 func TestRun(t *testing.T) {
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"fmt"
+	"time"
+)
+
+// RunError wraps the error of a failed execution of a runnable
+// with its metadata (see WrapErrors).
+type RunError struct {
+	// Attempt is the (1-based) number of the execution.
+	Attempt uint64
+	// StartedAt is the time the execution started.
+	StartedAt time.Time
+	// Duration is the duration of the execution.
+	Duration time.Duration
+	// Err is the error returned by the execution.
+	Err error
+}
+
+// Error returns the error of the execution, annotated with its metadata.
+func (e RunError) Error() string {
+	return fmt.Sprintf("attempt %d failed after %v: %v",
+		e.Attempt, e.Duration, e.Err)
+}
+
+// Unwrap returns the error of the execution.
+func (e RunError) Unwrap() error {
+	return e.Err
+}
+
+// WrapErrors indicates whether to wrap the errors of failed executions
+// of a runnable in a RunError (default: false),
+// before they are propagated (e.g. to the error channel).
+func WrapErrors(wrap bool) Option {
+	return func(o *options) *options {
+		o.wrapErrors = wrap
+		return o
+	}
+}
+
+// annotate wraps the error of a failed execution in a RunError,
+// if the appropriate option is set.
+func (o *options) annotate(err error, attempt Attempt,
+	started time.Time, elapsed time.Duration) error {
+
+	if o == nil || !o.wrapErrors {
+		return err
+	}
+	return RunError{
+		Attempt:   attempt.Number,
+		StartedAt: started,
+		Duration:  elapsed,
+		Err:       err,
+	}
+}
@@ -0,0 +1,59 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testRunError(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"WrapErrors": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{WrapErrors(true)})
+			as.Equal(&options{wrapErrors: true}, opts)
+
+			now := time.Now()
+			as.Equal(RunError{
+				Attempt:   2,
+				StartedAt: now,
+				Duration:  time.Second,
+				Err:       testError(1),
+			}, opts.annotate(testError(1), Attempt{Number: 2}, now, time.Second))
+
+			opts = nil
+			as.Equal(testError(1),
+				opts.annotate(testError(1), Attempt{Number: 2}, now, time.Second))
+		},
+		"RunError": func(t *testing.T) {
+			as := newAssertions(t)
+
+			err := RunError{Attempt: 3, Duration: time.Second, Err: testError(1)}
+			as.EqualError(err, "attempt 3 failed after 1s: test error: 1")
+			as.ErrorIs(err, testError(1))
+		},
+		"propagated errors are wrapped": func(t *testing.T) {
+			as := newAssertions(t)
+
+			before := time.Now()
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, Restart(true), RestartLimit(2, nil), WrapErrors(true))
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.Len(errs, 2)
+			for n, err := range errs {
+				var runErr RunError
+				as.True(errors.As(err, &runErr))
+				as.Equal(uint64(n+1), runErr.Attempt)
+				as.False(runErr.StartedAt.Before(before))
+				as.Equal(testError(1), runErr.Err)
+			}
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -29,3 +29,24 @@ func New(r Runnable, opts ...Option) Instance {
 		opts: runnableOpts,
 	}
 }
+
+// Clone creates a new, unstarted instance with the runnable
+// and effective options of an instance (see Update),
+// with the provided options applied on top of them,
+// so that a configured instance can serve as a blueprint for variations.
+//
+// The options of the clone are independent of those of the original.
+func (i *Instance) Clone(extra ...Option) Instance {
+	return Instance{
+		r:    i.r,
+		opts: i.options().with(extra),
+	}
+}
+
+// clone creates a new instance with the runnable and options of an instance.
+func (i *Instance) clone() *Instance {
+	return &Instance{
+		r:    i.r,
+		opts: i.options(),
+	}
+}
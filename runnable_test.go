@@ -29,6 +29,60 @@ func testRunnable(t *testing.T) {
 
 			as.EqualError(err, errorVal)
 		},
+		"clone with overrides": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var shards []string
+			blueprint := New(func(ctx context.Context) error {
+				name, _ := NameFromContext(ctx)
+				shards = append(shards, name)
+				return nil
+			}, Recur(true), RunLimit(2))
+
+			clone := blueprint.Clone(WithName("shard-1"), RunLimit(1))
+			as.Empty(waitErrors(clone.Run(context.TODO())))
+			as.Equal([]string{"shard-1"}, shards)
+
+			// The blueprint is unaffected, and can still run.
+			as.Equal(uint64(2), blueprint.Options().RunLimit)
+			as.Empty(waitErrors(blueprint.Run(context.TODO())))
+			as.Equal([]string{"shard-1", "", ""}, shards)
+		},
+		"clones do not share middleware": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var wrapped []string
+			tag := func(name string) Middleware {
+				return func(next Runnable) Runnable {
+					wrapped = append(wrapped, name)
+					return next
+				}
+			}
+			blueprint := New(func(context.Context) error {
+				return nil
+			}, WithMiddleware(tag("a"), tag("b"), tag("c")))
+
+			first := blueprint.Clone(WithMiddleware(tag("first")))
+			second := blueprint.Clone(WithMiddleware(tag("second")))
+
+			waitErrors(first.Run(context.TODO()))
+			waitErrors(second.Run(context.TODO()))
+			as.Equal([]string{"first", "c", "b", "a", "second", "c", "b", "a"},
+				wrapped)
+		},
+		"clone of updated instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(nil, Recur(true))
+			as.NoError(inst.Update(RunLimit(3)))
+
+			clone := inst.Clone()
+			as.Equal(inst.options(), clone.opts)
+			as.NotSame(inst.options(), clone.opts)
+
+			var zero Instance
+			as.Equal(&options{}, zero.Clone().opts)
+		},
 	}
 
 	for name, test := range subtests {
@@ -0,0 +1,238 @@
+// Package runtest provides utilities for testing runnables
+// and instances deterministically.
+package runtest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Ale1ster/run"
+)
+
+// Clock is a fake clock, whose time only moves when advanced explicitly,
+// firing any timers that become due.
+//
+// It is safe for concurrent use.
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []*Timer
+	changed chan struct{}
+}
+
+var _ run.Clock = (*Clock)(nil)
+
+// NewClock creates a fake clock set to the provided time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now, changed: make(chan struct{})}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer creates a timer delivering the time of the clock on its channel
+// once it is advanced by the provided duration.
+func (c *Clock) NewTimer(d time.Duration) run.Timer {
+	t := &Timer{clock: c, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// AfterFunc creates a timer calling the provided function
+// once the clock is advanced by the provided duration
+// (in its own goroutine, if the duration is not positive).
+func (c *Clock) AfterFunc(d time.Duration, f func()) run.Timer {
+	t := &Timer{clock: c, f: f}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the clock forward by the provided duration,
+// firing any timers that become due, in order.
+//
+// Functions of timers created by AfterFunc are called
+// before Advance returns.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	until := c.now.Add(d)
+	for len(c.timers) > 0 && !c.timers[0].when.After(until) {
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		c.now = t.when
+		c.notify()
+		now := c.now
+
+		// Fire outside the critical section,
+		// since the function of the timer may use the clock.
+		c.mu.Unlock()
+		t.fire(now, false)
+		c.mu.Lock()
+	}
+	c.now = until
+	c.mu.Unlock()
+}
+
+// Timers returns the number of active timers of the clock.
+func (c *Clock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+// BlockUntil blocks until the clock has at least the provided number
+// of active timers, e.g. until an instance is waiting for its next execution.
+func (c *Clock) BlockUntil(n int) {
+	for {
+		c.mu.Lock()
+		active, changed := len(c.timers), c.changed
+		c.mu.Unlock()
+
+		if active >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+// waiting returns a channel that is closed once the clock has active timers,
+// or their number changes.
+func (c *Clock) waiting() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.timers) > 0 {
+		return closed
+	}
+	return c.changed
+}
+
+// next returns the amount of time until the earliest active timer
+// of the clock is due, or zero if there is none.
+func (c *Clock) next() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.timers) == 0 {
+		return 0
+	}
+	return c.timers[0].when.Sub(c.now)
+}
+
+// active indicates whether a timer of the clock is active.
+func (c *Clock) active(t *Timer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, active := range c.timers {
+		if active == t {
+			return true
+		}
+	}
+	return false
+}
+
+// closed is a closed channel.
+var closed = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// notify signals a change in the active timers of the clock.
+// It must be called with the lock held.
+func (c *Clock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// remove deactivates a timer, and indicates whether it was active.
+// It must be called with the lock held.
+func (c *Clock) remove(t *Timer) bool {
+	for i, active := range c.timers {
+		if active == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.notify()
+			return true
+		}
+	}
+	return false
+}
+
+// Timer is a timer of a fake clock.
+type Timer struct {
+	clock *Clock
+	c     chan time.Time
+	f     func()
+	when  time.Time
+}
+
+var _ run.Timer = (*Timer)(nil)
+
+// C returns the channel where the timer delivers the time it fires at
+// (nil, for timers created by AfterFunc).
+func (t *Timer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop prevents the timer from firing, and indicates whether it was active.
+func (t *Timer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.clock.remove(t)
+}
+
+// Reset changes the timer to fire once the clock is advanced
+// by the provided duration, and indicates whether it was active.
+//
+// A timer reset to a non-positive duration fires immediately.
+func (t *Timer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	active := c.remove(t)
+	t.when = c.now.Add(d)
+	if d <= 0 {
+		now := c.now
+		c.mu.Unlock()
+		t.fire(now, true)
+		return active
+	}
+
+	// Keep the timers ordered by due time, preserving the order
+	// of timers due at the same time.
+	at := sort.Search(len(c.timers), func(i int) bool {
+		return c.timers[i].when.After(t.when)
+	})
+	c.timers = append(c.timers, nil)
+	copy(c.timers[at+1:], c.timers[at:])
+	c.timers[at] = t
+	c.notify()
+	c.mu.Unlock()
+	return active
+}
+
+// fire delivers the provided time on the channel of the timer
+// (dropping it, if a previous one is not yet received),
+// or calls its function, asynchronously if requested.
+func (t *Timer) fire(now time.Time, async bool) {
+	if t.f == nil {
+		select {
+		case t.c <- now:
+		default:
+		}
+		return
+	}
+
+	if async {
+		go t.f()
+		return
+	}
+	t.f()
+}
@@ -0,0 +1,116 @@
+package runtest
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ale1ster/run"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	as := assert.New(t)
+
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClock(start)
+	as.Equal(start, c.Now())
+
+	first := c.NewTimer(time.Second)
+	second := c.NewTimer(2 * time.Second)
+	as.Equal(2, c.Timers())
+
+	var called int32
+	c.AfterFunc(time.Second, func() {
+		// The function may use the clock.
+		as.Equal(start.Add(time.Second), c.Now())
+		atomic.AddInt32(&called, 1)
+	})
+
+	c.Advance(time.Second)
+	as.Equal(start.Add(time.Second), <-first.C())
+	as.Equal(int32(1), atomic.LoadInt32(&called))
+	as.Len(second.C(), 0)
+	as.Equal(1, c.Timers())
+
+	as.Equal(time.Second, c.next())
+	as.True(c.active(second.(*Timer)))
+	as.True(second.Stop())
+	as.False(second.Stop())
+	as.False(c.active(second.(*Timer)))
+	as.Zero(c.next())
+	c.Advance(time.Hour)
+	as.Len(second.C(), 0)
+
+	as.False(first.Reset(time.Minute))
+	as.True(first.Reset(time.Minute))
+	c.Advance(time.Minute)
+	as.Equal(start.Add(time.Second+time.Hour+time.Minute), <-first.C())
+
+	// Expired timers fire immediately.
+	first.Reset(0)
+	as.Equal(c.Now(), <-first.C())
+	done := make(chan struct{})
+	c.AfterFunc(-time.Second, func() {
+		close(done)
+	})
+	<-done
+	as.Equal(0, c.Timers())
+}
+
+func TestClockBlockUntil(t *testing.T) {
+	as := assert.New(t)
+
+	c := NewClock(time.Time{})
+	c.BlockUntil(0)
+
+	go func() {
+		c.NewTimer(time.Second)
+		c.NewTimer(time.Second)
+	}()
+	c.BlockUntil(2)
+	as.Equal(2, c.Timers())
+}
+
+func TestClockInstance(t *testing.T) {
+	as := assert.New(t)
+
+	c := NewClock(time.Time{})
+	failure := errors.New("failed")
+	var runs int32
+	inst := run.New(func(ctx context.Context) error {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			return failure
+		}
+		// Time out the second execution.
+		<-ctx.Done()
+		return ctx.Err()
+	},
+		run.WithClock(c),
+		run.WithChanBuffer(2),
+		run.Restart(true),
+		run.RestartLimit(2, run.ConstantBackoff(time.Hour)),
+		run.Timeout(time.Minute),
+	)
+
+	errCh := inst.Run(context.TODO())
+	as.Equal(failure, <-errCh)
+
+	// Waiting for the backoff period.
+	c.BlockUntil(1)
+	as.Equal(c.Now().Add(time.Hour), inst.Stats().NextRun)
+	c.Advance(time.Hour)
+
+	// Waiting for the execution timeout.
+	c.BlockUntil(1)
+	c.Advance(time.Minute)
+	as.ErrorIs(<-errCh, run.ErrRunTimeout)
+	_, ok := <-errCh
+	as.False(ok)
+
+	stats := inst.Stats()
+	as.Equal(uint64(2), stats.Runs)
+	as.Equal(time.Minute, stats.LastDuration)
+}
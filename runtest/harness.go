@@ -0,0 +1,243 @@
+package runtest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Ale1ster/run"
+)
+
+// Epoch is the time the clock of a harness starts at.
+var Epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// waitTimeout is the maximum amount of real time a harness waits
+// for the events of its instance, before failing the test.
+var waitTimeout = 10 * time.Second
+
+// Harness drives the execution of a script deterministically,
+// as an instance whose timing is controlled by a fake clock.
+//
+// Virtual time only advances when the instance is waiting,
+// either for its next execution or for an execution of the script
+// blocked on the clock or its context.
+type Harness struct {
+	t      testing.TB
+	clock  *Clock
+	script *Script
+	inst   *run.Instance
+	events <-chan run.Event
+	seen   []run.Event
+}
+
+// Start runs a script as an instance with the provided options,
+// using a fake clock set to Epoch, and returns a harness driving it.
+//
+// The instance is cancelled, and its remaining events are drained,
+// when the test completes.
+func Start(t testing.TB, s *Script, opts ...run.Option) *Harness {
+	clock := NewClock(Epoch)
+	s.bind(clock)
+
+	inst := run.New(s.Run, append(opts, run.WithClock(clock))...)
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &Harness{
+		t:      t,
+		clock:  clock,
+		script: s,
+		inst:   &inst,
+		events: inst.Events(ctx),
+	}
+	t.Cleanup(func() {
+		cancel()
+		for range h.events {
+		}
+	})
+	return h
+}
+
+// Clock returns the fake clock of a harness.
+func (h *Harness) Clock() *Clock {
+	return h.clock
+}
+
+// Instance returns the instance driven by a harness.
+func (h *Harness) Instance() *run.Instance {
+	return h.inst
+}
+
+// Advance advances the clock of a harness by the provided duration.
+func (h *Harness) Advance(d time.Duration) {
+	h.clock.Advance(d)
+}
+
+// Next returns the next event of the instance,
+// advancing virtual time while it is waiting.
+// It fails the test if the instance terminates without one.
+func (h *Harness) Next() run.Event {
+	h.t.Helper()
+
+	evs := h.receive(func(run.Event) bool {
+		return true
+	})
+	if len(evs) == 0 {
+		h.t.Fatal("runtest: instance terminated")
+		return nil
+	}
+	return evs[0]
+}
+
+// Step returns the events of the instance up to the end
+// of its next execution (or its termination),
+// advancing virtual time while it is waiting.
+func (h *Harness) Step() []run.Event {
+	h.t.Helper()
+
+	return h.receive(func(ev run.Event) bool {
+		switch ev.(type) {
+		case run.RunSucceeded, run.RunFailed, run.RunAbandoned, run.Terminated:
+			return true
+		}
+		return false
+	})
+}
+
+// Wait returns the remaining events of the instance until it terminates,
+// advancing virtual time while it is waiting.
+func (h *Harness) Wait() []run.Event {
+	h.t.Helper()
+
+	return h.receive(func(run.Event) bool {
+		return false
+	})
+}
+
+// Stop stops the instance, and returns its remaining events
+// until it terminates.
+func (h *Harness) Stop() []run.Event {
+	h.t.Helper()
+
+	h.inst.Stop()
+	return h.Wait()
+}
+
+// Expect receives the next events of the instance,
+// and fails the test unless they equal the provided ones
+// (with the errors of RunFailed and Terminated events matched by errors.Is,
+// or by their message).
+func (h *Harness) Expect(evs ...run.Event) {
+	h.t.Helper()
+
+	for n, want := range evs {
+		got := h.Next()
+		if got == nil {
+			return
+		}
+		if !equal(got, want) {
+			h.t.Fatalf("runtest: unexpected event no.%d: %#v, expected %#v",
+				n+1, got, want)
+			return
+		}
+	}
+}
+
+// Events returns all the events of the instance received so far.
+func (h *Harness) Events() []run.Event {
+	return append([]run.Event(nil), h.seen...)
+}
+
+// Errors returns the errors the events of the instance received so far
+// propagate (as the error channel of an instance would).
+func (h *Harness) Errors() []error {
+	var errs []error
+	for _, ev := range h.seen {
+		var err error
+		switch e := ev.(type) {
+		case run.RunFailed:
+			err = e.Err
+		case run.RunAbandoned:
+			err = run.ErrRunAbandoned
+		case run.Terminated:
+			err = e.Reason
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// receive receives events of the instance until the provided function
+// indicates completion or the instance terminates,
+// advancing virtual time while it is waiting.
+func (h *Harness) receive(done func(run.Event) bool) []run.Event {
+	h.t.Helper()
+
+	guard := time.NewTimer(waitTimeout)
+	defer guard.Stop()
+
+	var evs []run.Event
+	for {
+		// Prefer pending events over advancing virtual time.
+		var ev run.Event
+		var ok bool
+		select {
+		case ev, ok = <-h.events:
+		default:
+			select {
+			case ev, ok = <-h.events:
+			case <-h.clock.waiting():
+				if !h.idle() {
+					runtime.Gosched()
+					continue
+				}
+				h.clock.Advance(h.clock.next())
+				continue
+			case <-guard.C:
+				h.t.Fatal("runtest: timed out waiting for events of the instance")
+				return evs
+			}
+		}
+
+		if !ok {
+			return evs
+		}
+		h.seen = append(h.seen, ev)
+		evs = append(evs, ev)
+		if done(ev) {
+			return evs
+		}
+	}
+}
+
+// idle indicates whether the instance is waiting,
+// so that virtual time can advance.
+func (h *Harness) idle() bool {
+	return h.inst.State() != run.StateRunning || h.script.blocked()
+}
+
+// equal indicates whether two events are equal,
+// comparing errors as sameError does.
+func equal(got, want run.Event) bool {
+	switch w := want.(type) {
+	case run.RunFailed:
+		g, ok := got.(run.RunFailed)
+		return ok && g.Duration == w.Duration && sameError(g.Err, w.Err)
+	case run.Terminated:
+		g, ok := got.(run.Terminated)
+		return ok && sameError(g.Reason, w.Reason)
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+// sameError indicates whether an error matches the expected one
+// (see errors.Is), or has the same message.
+func sameError(got, want error) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return errors.Is(got, want) || got.Error() == want.Error()
+}
@@ -0,0 +1,188 @@
+package runtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Ale1ster/run"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTB records the failures of a test, without stopping it.
+type recordingTB struct {
+	testing.TB
+	failures []string
+	cleanups []func()
+}
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Fatal(args ...interface{}) {
+	tb.failures = append(tb.failures, fmt.Sprint(args...))
+}
+
+func (tb *recordingTB) Fatalf(format string, args ...interface{}) {
+	tb.failures = append(tb.failures, fmt.Sprintf(format, args...))
+}
+
+func (tb *recordingTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func (tb *recordingTB) cleanup() {
+	for _, f := range tb.cleanups {
+		f()
+	}
+}
+
+func TestHarnessRestarts(t *testing.T) {
+	as := assert.New(t)
+
+	failure := errors.New("failed")
+	s := NewScript(Fail(failure), Fail(failure), Succeed().Taking(time.Minute))
+	h := Start(t, s, run.Restart(true),
+		run.RestartLimit(0, run.ConstantBackoff(time.Hour)))
+	as.Equal(h.clock, h.Clock())
+
+	as.Equal([]run.Event{
+		run.RunStarted{},
+		run.RunFailed{Err: failure},
+	}, h.Step())
+	as.Equal([]run.Event{
+		run.BackoffStarted{Delay: time.Hour},
+		run.RunStarted{},
+		run.RunFailed{Err: failure},
+	}, h.Step())
+	h.Expect(
+		run.BackoffStarted{Delay: time.Hour},
+		run.RunStarted{},
+		run.RunSucceeded{Duration: time.Minute},
+	)
+	as.Equal([]run.Event{run.Terminated{}}, h.Wait())
+	as.Equal([]error{failure, failure}, h.Errors())
+	as.Len(h.Events(), 9)
+
+	calls := s.Calls()
+	as.Len(calls, 3)
+	for n, call := range calls {
+		as.Equal(Epoch.Add(time.Duration(n)*time.Hour), call.At)
+		as.Equal(uint64(n+1), call.Attempt.Number)
+	}
+	as.Equal(uint64(3), h.Instance().Stats().Runs)
+	as.Equal(Epoch.Add(2*time.Hour+time.Minute), h.Clock().Now())
+}
+
+func TestHarnessTimeout(t *testing.T) {
+	as := assert.New(t)
+
+	s := NewScript(Block(), Succeed().Taking(time.Hour))
+	h := Start(t, s, run.Timeout(time.Minute),
+		run.Restart(true), run.RestartLimit(2, nil))
+
+	h.Expect(
+		run.RunStarted{},
+		run.RunFailed{
+			Err:      errors.New("run timeout: context deadline exceeded"),
+			Duration: time.Minute,
+		},
+	)
+	h.Expect(
+		run.BackoffStarted{},
+		run.RunStarted{},
+		run.RunFailed{Err: context.DeadlineExceeded, Duration: time.Minute},
+		run.Terminated{},
+	)
+	as.Len(h.Errors(), 2)
+	for _, err := range h.Errors() {
+		as.ErrorIs(err, run.ErrRunTimeout)
+	}
+}
+
+func TestHarnessScript(t *testing.T) {
+	as := assert.New(t)
+
+	s := NewScript(Panic("boom"))
+	h := Start(t, s, run.Recover(true))
+	h.Expect(
+		run.RunStarted{},
+		run.Recovered{Panic: "boom"},
+		run.Terminated{Reason: run.RunnablePanic{Value: "boom"}},
+	)
+
+	s = NewScript(Succeed())
+	h = Start(t, s, run.Recur(true), run.Period(time.Second))
+	h.Expect(run.RunStarted{}, run.RunSucceeded{}, run.RunStarted{})
+	as.Equal([]run.Event{
+		run.RunFailed{Err: ErrScriptExhausted},
+		run.Terminated{},
+	}, h.Wait())
+	as.Equal(Epoch.Add(time.Second), s.Calls()[1].At)
+
+	s = NewScript(Succeed().Taking(time.Minute))
+	h = Start(t, s)
+	as.Equal(run.RunStarted{}, h.Next())
+	h.Advance(time.Minute)
+	h.Expect(run.RunSucceeded{Duration: time.Minute}, run.Terminated{})
+
+	h = &Harness{seen: []run.Event{run.RunStarted{}, run.RunAbandoned{}}}
+	as.Equal([]error{run.ErrRunAbandoned}, h.Errors())
+
+	s = NewScript(Block())
+	h = Start(t, s, run.Recur(true))
+	as.Equal(run.RunStarted{}, h.Next())
+	as.Equal([]run.Event{
+		run.RunFailed{Err: context.Canceled},
+		run.Terminated{},
+	}, h.Stop())
+}
+
+func TestHarnessFailures(t *testing.T) {
+	as := assert.New(t)
+
+	tb := new(recordingTB)
+	h := Start(tb, NewScript(Succeed()))
+	h.Expect(run.RunStarted{}, run.RunFailed{Err: errors.New("failed")})
+	as.Len(tb.failures, 1)
+	as.Contains(tb.failures[0], "runtest: unexpected event no.2: "+
+		"run.RunSucceeded{Duration:0}, expected run.RunFailed")
+
+	tb.failures = nil
+	h.Expect(run.Terminated{Reason: errors.New("failed")})
+	h.Wait()
+	h.Expect(run.Terminated{})
+	as.Len(tb.failures, 2)
+	as.Contains(tb.failures[1], "runtest: instance terminated")
+
+	// Without virtual time advancing, the instance blocks indefinitely.
+	defer func(timeout time.Duration) {
+		waitTimeout = timeout
+	}(waitTimeout)
+	waitTimeout = 10 * time.Millisecond
+
+	tb.failures = nil
+	h = Start(tb, NewScript(Block()))
+	h.Wait()
+	as.Equal([]string{"runtest: timed out waiting for events of the instance"},
+		tb.failures)
+	tb.cleanup()
+}
+
+func TestScript(t *testing.T) {
+	as := assert.New(t)
+
+	// Without a harness, scripts use the system clock.
+	s := NewScript(Fail(errors.New("failed")).Taking(time.Millisecond))
+	err := run.Do(context.TODO(), s.Run)
+	as.EqualError(err, "failed")
+	as.EqualError(s.Run(context.TODO()), ErrScriptExhausted.Error())
+	as.Len(s.Calls(), 2)
+	as.False(s.blocked())
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	s = NewScript(Succeed().Taking(time.Hour))
+	as.Equal(context.Canceled, s.Run(ctx))
+}
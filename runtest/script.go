@@ -0,0 +1,186 @@
+package runtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Ale1ster/run"
+)
+
+// ErrScriptExhausted is returned by the executions of a script
+// exceeding its outcomes.
+var ErrScriptExhausted = errors.New("script exhausted")
+
+// Outcome determines the result of a single execution of a script.
+type Outcome struct {
+	err      error
+	panics   bool
+	value    interface{}
+	block    bool
+	duration time.Duration
+}
+
+// Succeed returns the outcome of a successful execution.
+func Succeed() Outcome {
+	return Outcome{}
+}
+
+// Fail returns the outcome of an execution failing with the provided error.
+func Fail(err error) Outcome {
+	return Outcome{err: err}
+}
+
+// Panic returns the outcome of an execution panicking with the provided value.
+func Panic(v interface{}) Outcome {
+	return Outcome{panics: true, value: v}
+}
+
+// Block returns the outcome of an execution
+// that blocks until its context is done, returning its error.
+func Block() Outcome {
+	return Outcome{block: true}
+}
+
+// Taking delays an outcome until the clock of the script
+// advances by the provided duration.
+// If the context of the execution is done first, it returns its error instead.
+func (o Outcome) Taking(d time.Duration) Outcome {
+	o.duration = d
+	return o
+}
+
+// Call records a single execution of a script.
+type Call struct {
+	// At is the time the execution started,
+	// according to the clock of the script.
+	At time.Time
+	// Attempt is the execution metadata carried by its context.
+	Attempt run.Attempt
+}
+
+// Script is a runnable whose executions have scripted outcomes, in order.
+//
+// Its timing is controlled by the clock of the harness it is started with
+// (see Start), or the system clock otherwise.
+type Script struct {
+	mu        sync.Mutex
+	clock     *Clock
+	outcomes  []Outcome
+	calls     []Call
+	executing map[*execution]struct{}
+}
+
+// NewScript creates a script with the provided outcomes.
+func NewScript(outcomes ...Outcome) *Script {
+	return &Script{
+		outcomes:  outcomes,
+		executing: make(map[*execution]struct{}),
+	}
+}
+
+// Run executes a script, with the next of its outcomes.
+// It has the signature of run.Runnable.
+func (s *Script) Run(ctx context.Context) error {
+	s.mu.Lock()
+	var c run.Clock = run.SystemClock
+	if s.clock != nil {
+		c = s.clock
+	}
+	attempt, _ := run.AttemptFromContext(ctx)
+	s.calls = append(s.calls, Call{At: c.Now(), Attempt: attempt})
+	if len(s.calls) > len(s.outcomes) {
+		s.mu.Unlock()
+		return ErrScriptExhausted
+	}
+	o := s.outcomes[len(s.calls)-1]
+	s.mu.Unlock()
+
+	if o.block || o.duration > 0 {
+		if err := s.wait(ctx, c, o); err != nil {
+			return err
+		}
+	}
+	if o.panics {
+		panic(o.value)
+	}
+	return o.err
+}
+
+// wait blocks an execution according to its outcome.
+func (s *Script) wait(ctx context.Context, c run.Clock, o Outcome) error {
+	e := &execution{ctx: ctx}
+	var elapsed <-chan time.Time
+	if !o.block {
+		e.timer = c.NewTimer(o.duration)
+		defer e.timer.Stop()
+		elapsed = e.timer.C()
+	}
+
+	s.mu.Lock()
+	s.executing[e] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.executing, e)
+		s.mu.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-elapsed:
+		return nil
+	}
+}
+
+// Calls returns the executions of a script so far.
+func (s *Script) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call(nil), s.calls...)
+}
+
+// bind sets the clock controlling the timing of a script.
+func (s *Script) bind(c *Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clock = c
+}
+
+// blocked indicates whether a script is executing,
+// with all of its executions waiting for their clock or context.
+func (s *Script) blocked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.executing) == 0 {
+		return false
+	}
+	for e := range s.executing {
+		if !e.blocked() {
+			return false
+		}
+	}
+	return true
+}
+
+// execution is a blocked execution of a script.
+type execution struct {
+	ctx   context.Context
+	timer run.Timer
+}
+
+// blocked indicates whether an execution is still waiting.
+func (e *execution) blocked() bool {
+	if e.ctx.Err() != nil {
+		return false
+	}
+	if t, ok := e.timer.(*Timer); ok {
+		return t.clock.active(t)
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package run
+
+import "time"
+
+// Schedule determines the times a recurring runnable executes at.
+type Schedule interface {
+	// Next returns the time of the next execution after the provided time,
+	// or the zero time if there are no more executions.
+	Next(after time.Time) time.Time
+}
+
+// ScheduleFunc is an adapter allowing the use of
+// ordinary functions as schedules.
+type ScheduleFunc func(after time.Time) time.Time
+
+// Next satisfies Schedule interface for ScheduleFunc.
+func (f ScheduleFunc) Next(after time.Time) time.Time {
+	return f(after)
+}
+
+// WithSchedule sets a schedule for a recurring runnable,
+// which then executes at the times determined by it
+// (including its first execution),
+// rather than periodically after each successful execution.
+//
+// WithSchedule implies Recur(true), and takes precedence over Period.
+// A nil schedule removes any previously set one.
+func WithSchedule(s Schedule) Option {
+	return func(o *options) *options {
+		o.recurring.schedule = s
+		if s != nil {
+			o.recurring.recur = true
+		}
+		return o
+	}
+}
@@ -0,0 +1,59 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testSchedule(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"WithSchedule": func(t *testing.T) {
+			as := newAssertions(t)
+
+			sched := ScheduleFunc(func(after time.Time) time.Time {
+				return after.Add(time.Minute)
+			})
+			opts := apply(t, new(options), []Option{WithSchedule(sched)})
+
+			as.True(opts.recurring.recur)
+			as.NotNil(opts.recurring.schedule)
+
+			opts = apply(t, opts, []Option{WithSchedule(nil)})
+			as.Nil(opts.recurring.schedule)
+		},
+		"custom schedule drives executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			// Schedule three executions 10ms apart.
+			delay := 10 * time.Millisecond
+			remaining := 3
+			sched := ScheduleFunc(func(after time.Time) time.Time {
+				if remaining == 0 {
+					return time.Time{}
+				}
+				remaining--
+				return after.Add(delay)
+			})
+
+			var calls []time.Time
+			inst := New(func(context.Context) error {
+				calls = append(calls, time.Now())
+				return nil
+			}, WithSchedule(sched))
+
+			started := time.Now()
+			waitErrors(inst.Run(context.TODO()))
+
+			if as.Len(calls, 3) {
+				as.WithinDuration(started.Add(delay), calls[0], testTimeDelta)
+				as.WithinDuration(calls[0].Add(delay), calls[1], testTimeDelta)
+				as.WithinDuration(calls[1].Add(delay), calls[2], testTimeDelta)
+			}
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"context"
+	"fmt"
+)
+
+// Service represents a component with separate start and stop operations,
+// as exposed by many libraries.
+type Service interface {
+	// Start starts the service, returning once it has started.
+	Start(ctx context.Context) error
+	// Stop stops the service, returning once it has stopped.
+	Stop(ctx context.Context) error
+}
+
+// StopError is returned by an execution of a service runnable
+// whose service fails to stop.
+type StopError struct {
+	// Err is the error returned by Stop.
+	Err error
+}
+
+// Error satisfies error interface for StopError.
+func (e StopError) Error() string {
+	return fmt.Sprintf("stopping service: %v", e.Err)
+}
+
+// Unwrap returns the error returned by Stop.
+func (e StopError) Unwrap() error {
+	return e.Err
+}
+
+// FromService returns a runnable starting a service on each execution,
+// which reports readiness once it has started (see AwaitReady),
+// and stops it once the context of the execution is cancelled.
+//
+// The execution fails with the error returned by Start, if any,
+// or StopError if Stop fails, and returns the context error otherwise.
+// Stop is provided with a context carrying the values of the context
+// of the execution, but not its cancellation
+// (see StopGrace for limiting the time it takes).
+func FromService(s Service) Runnable {
+	return func(ctx context.Context) error {
+		if err := s.Start(ctx); err != nil {
+			return err
+		}
+		Ready(ctx)
+
+		<-ctx.Done()
+		if err := s.Stop(context.WithoutCancel(ctx)); err != nil {
+			return StopError{Err: err}
+		}
+		return ctx.Err()
+	}
+}
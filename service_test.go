@@ -0,0 +1,70 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// recordingService records the calls to its operations.
+type recordingService struct {
+	startErr, stopErr error
+	calls             []string
+	stopCtxErr        error
+}
+
+func (s *recordingService) Start(context.Context) error {
+	s.calls = append(s.calls, "start")
+	return s.startErr
+}
+
+func (s *recordingService) Stop(ctx context.Context) error {
+	s.calls = append(s.calls, "stop")
+	s.stopCtxErr = ctx.Err()
+	return s.stopErr
+}
+
+func testService(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"started and stopped": func(t *testing.T) {
+			as := newAssertions(t)
+
+			s := &recordingService{}
+			inst := New(FromService(s), AwaitReady(true))
+
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			inst.Stop()
+
+			as.Equal([]error{context.Canceled}, waitErrors(errCh))
+			as.Equal([]string{"start", "stop"}, s.calls)
+			as.NoError(s.stopCtxErr)
+		},
+		"start failure": func(t *testing.T) {
+			as := newAssertions(t)
+
+			s := &recordingService{startErr: testError("start")}
+			as.Equal(testError("start"), Do(context.TODO(), FromService(s)))
+			as.Equal([]string{"start"}, s.calls)
+		},
+		"stop failure": func(t *testing.T) {
+			as := newAssertions(t)
+
+			s := &recordingService{stopErr: testError("stop")}
+			inst := New(FromService(s), AwaitReady(true))
+
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			inst.Stop()
+
+			errs := waitErrors(errCh)
+			as.Equal([]error{StopError{Err: testError("stop")}}, errs)
+			as.Equal("stopping service: test error: stop", errs[0].Error())
+			as.True(errors.Is(errs[0], testError("stop")))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
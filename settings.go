@@ -0,0 +1,261 @@
+package run
+
+import (
+	"context"
+	"time"
+)
+
+// Settings describes the effective options of an instance
+// (see Instance.Options), with each field corresponding
+// to the respective option.
+//
+// It is a copy, so modifying it does not affect the instance.
+type Settings struct {
+	Name   string
+	Labels map[string]string
+	// Registry is the registry of the instance (see WithRegistry), if any.
+	Registry *Registry
+
+	Recur        bool
+	Period       time.Duration
+	PeriodJitter float64
+	FixedRate    bool
+	Overrun      OverrunPolicy
+	CatchUp      CatchUpPolicy
+	// Schedule is the schedule of the instance (see WithSchedule), if any.
+	Schedule Schedule
+	// Triggered indicates whether the instance has a trigger channel
+	// (see Triggers).
+	Triggered        bool
+	CoalesceTriggers bool
+	Debounce         time.Duration
+	Throttle         time.Duration
+	// Windowed indicates whether the instance has a run window,
+	// with RunWindowFrom, RunWindowTo and RunWindowLocation
+	// being its parameters (see RunWindow).
+	Windowed          bool
+	RunWindowFrom     time.Duration
+	RunWindowTo       time.Duration
+	RunWindowLocation *time.Location
+
+	InitialDelay time.Duration
+	StartSplay   time.Duration
+	Timeout      time.Duration
+	// TimeoutFn is the function determining the timeout of each attempt
+	// (see TimeoutFn), if any.
+	TimeoutFn    func(attempt uint64) time.Duration
+	TotalTimeout time.Duration
+	StopGrace    time.Duration
+	StopTimeout  time.Duration
+	Concurrency  uint
+	AwaitReady   bool
+
+	RunLimit     uint64
+	AttemptLimit uint64
+
+	Restart          bool
+	RestartLimit     uint64
+	RestartOnTimeout bool
+	ResetOnSuccess   bool
+	ResetAfterStable time.Duration
+	// Backoff is the backoff function of the instance (see Backoff),
+	// or nil if there is none.
+	Backoff BackoffFn
+	// RetryBudget is the retry budget of the instance
+	// (see WithRetryBudget), if any.
+	RetryBudget *Budget
+
+	// TimeoutRestartLimit and TimeoutBackoff are the restart limit
+	// and backoff function of timed out executions (see TimeoutRestartLimit),
+	// with the latter being nil unless they are accounted separately.
+	TimeoutRestartLimit uint64
+	TimeoutBackoff      BackoffFn
+
+	Recover bool
+	// RecoverWith is the function converting panics into errors
+	// (see RecoverWith), if any.
+	RecoverWith func(v interface{}, stack []byte) error
+
+	// ChanBuffer is the buffer size of the error channel
+	// (see WithChanBuffer).
+	ChanBuffer        uint
+	Overflow          OverflowPolicy
+	ReportTermination bool
+	WrapErrors        bool
+	KeepHistory       uint
+	HeartbeatTimeout  time.Duration
+	Profiling         bool
+	DetachValues      bool
+
+	AbandonAfter      time.Duration
+	UnresponsiveGrace time.Duration
+	SlowRunThreshold  time.Duration
+	DedupeErrors      bool
+	// FailureRateThreshold, FailureRateWindow and FailureRateMinRuns
+	// are the parameters of the failure-rate alarm of the instance
+	// (see FailureRateAlarm), and are zero if it has none.
+	FailureRateThreshold float64
+	FailureRateWindow    time.Duration
+	FailureRateMinRuns   uint
+	// Reporter is the reporter of the instance (see WithReporter), if any.
+	Reporter Reporter
+	// HealthRules are the health rules of the instance (see HealthRules).
+	HealthRules []HealthRule
+
+	// The following are the respective hooks and dependencies
+	// of the instance, if any.
+	OnError         func(error)
+	ErrClassifier   func(error) Outcome
+	ValidateSuccess func(ctx context.Context) error
+	RunIf           func(ctx context.Context) (bool, error)
+	ContextFactory  ContextFactory
+	Limiter         Limiter
+	Gate            Gate
+	Store           Store
+	Clock           Clock
+	Metrics         []Metrics
+	Middleware      []Middleware
+	// Flights and FlightKey are the parameters of the singleflight
+	// group of the instance (see Singleflight).
+	Flights   *Flights
+	FlightKey string
+	// Locker, CoordinationKey and CoordinationHold are the parameters
+	// of the coordination of the instance (see Coordinate).
+	Locker           Locker
+	CoordinationKey  string
+	CoordinationHold time.Duration
+
+	// Parent is the parent of the instance (see ChildOf), if any.
+	Parent       *Instance
+	BubbleErrors bool
+
+	// RunsLeft, AttemptsLeft and RestartsLeft are the numbers of
+	// successful executions, executions and restarts left before
+	// the run, attempt and restart limit is reached respectively,
+	// and are only meaningful if the respective limit is set,
+	// while TimeoutRestartsLeft is that of the restarts of timed out
+	// executions, if accounted separately.
+	RunsLeft, AttemptsLeft, RestartsLeft uint64
+	TimeoutRestartsLeft                  uint64
+}
+
+// Options returns the effective options of an instance,
+// including any updates (see Update), along with the number
+// of executions left before each of its limits is reached.
+//
+// It is safe to call while the instance is running.
+func (i *Instance) Options() Settings {
+	o := i.options()
+	if o == nil {
+		return Settings{Concurrency: 1, RestartOnTimeout: true}
+	}
+
+	var labels map[string]string
+	if o.labels != nil {
+		labels = make(map[string]string, len(o.labels))
+		for k, v := range o.labels {
+			labels[k] = v
+		}
+	}
+	rOpts, cOpts, restart := o.recurring, o.constrained, o.restartable
+	trigger, window, coord := o.triggering, o.window, o.coordination
+	return Settings{
+		Name:     o.name,
+		Labels:   labels,
+		Registry: o.registry,
+
+		Recur:             rOpts.recur,
+		Period:            rOpts.period,
+		PeriodJitter:      rOpts.jitter,
+		FixedRate:         rOpts.fixedRate,
+		Overrun:           rOpts.overrun,
+		CatchUp:           rOpts.catchUp,
+		Schedule:          rOpts.schedule,
+		Triggered:         o.triggers() != nil,
+		CoalesceTriggers:  trigger.coalesce,
+		Debounce:          trigger.debounce,
+		Throttle:          trigger.throttle,
+		Windowed:          window.restricted,
+		RunWindowFrom:     window.from,
+		RunWindowTo:       window.to,
+		RunWindowLocation: window.loc,
+
+		InitialDelay:     o.starting.delay,
+		StartSplay:       o.starting.splay,
+		Timeout:          cOpts.timeout,
+		TimeoutFn:        cOpts.timeoutFn,
+		TotalTimeout:     o.totalTimeout,
+		StopGrace:        o.grace(),
+		StopTimeout:      o.stopTimeout,
+		Concurrency:      o.workers(),
+		AwaitReady:       o.awaitReady,
+		RunLimit:         cOpts.runLimit,
+		AttemptLimit:     cOpts.attemptLimit,
+		Restart:          restart.restartOnError,
+		RestartLimit:     restart.restartLimit,
+		RestartOnTimeout: !restart.fatalTimeout,
+		ResetOnSuccess:   restart.resetOnSuccess,
+		ResetAfterStable: restart.stableAfter,
+		Backoff:          restart.backoff,
+		RetryBudget:      o.budget,
+
+		TimeoutRestartLimit: restart.timeoutLimit,
+		TimeoutBackoff:      restart.timeoutBackoff,
+
+		Recover:     o.calm(),
+		RecoverWith: o.recoverable.converter,
+
+		ChanBuffer:        o.errChanSize,
+		Overflow:          o.overflow,
+		ReportTermination: o.reportEnd,
+		WrapErrors:        o.wrapErrors,
+		KeepHistory:       o.historySize,
+		HeartbeatTimeout:  o.heartbeat,
+		Profiling:         o.profiling,
+		DetachValues:      o.detached,
+
+		AbandonAfter:         o.abandonAfter,
+		UnresponsiveGrace:    o.unresponsiveAfter,
+		SlowRunThreshold:     o.slowAfter,
+		DedupeErrors:         o.dedupeErrors,
+		FailureRateThreshold: o.alarm.threshold,
+		FailureRateWindow:    o.alarm.window,
+		FailureRateMinRuns:   o.alarm.minRuns,
+		Reporter:             o.reporter,
+		HealthRules:          append([]HealthRule(nil), o.health...),
+
+		OnError:          o.onError,
+		ErrClassifier:    o.classifier,
+		ValidateSuccess:  o.validator,
+		RunIf:            o.precondition,
+		ContextFactory:   o.contextFn,
+		Limiter:          o.limiter,
+		Gate:             o.gate,
+		Store:            o.store,
+		Clock:            o.timing,
+		Metrics:          append([]Metrics(nil), o.metrics...),
+		Middleware:       append([]Middleware(nil), o.middleware...),
+		Flights:          o.flights,
+		FlightKey:        o.flightKey,
+		Locker:           coord.locker,
+		CoordinationKey:  coord.key,
+		CoordinationHold: coord.hold,
+
+		Parent:       o.parent,
+		BubbleErrors: o.bubble,
+
+		RunsLeft:            left(cOpts.runLimit, i.runs.Load()),
+		AttemptsLeft:        left(cOpts.attemptLimit, i.attempts.Load()),
+		RestartsLeft:        left(restart.restartLimit, i.failedRuns.Load()),
+		TimeoutRestartsLeft: left(restart.timeoutLimit, i.timeouts.Load()),
+	}
+}
+
+// left returns the amount left before the provided limit is reached,
+// given the amount used so far.
+func left(limit, used uint64) uint64 {
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
@@ -0,0 +1,282 @@
+package run
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSettings(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"effective options": func(t *testing.T) {
+			as := newAssertions(t)
+
+			sched := MustParseCron("0 3 * * *", time.UTC)
+			triggers := make(chan struct{})
+			parent := New(nil)
+			inst := New(nil,
+				WithName("sync"), WithLabels(map[string]string{"team": "infra"}),
+				Recur(true), Period(time.Minute), PeriodJitter(0.1), FixedRate(true),
+				Cron(sched), Triggers(triggers),
+				InitialDelay(time.Second), StartSplay(2*time.Second),
+				Timeout(3*time.Second), TotalTimeout(time.Hour),
+				StopGrace(4*time.Second), Concurrency(2),
+				RunLimit(5), AttemptLimit(6),
+				Restart(true), RestartLimit(7, ConstantBackoff(time.Second)),
+				ResetOnSuccess(true), ResetAfterStable(time.Minute), Recover(true),
+				ChildOf(&parent), BubbleErrors(true),
+				TimeoutRestartLimit(8, ConstantBackoff(time.Minute)),
+				RecoverWith(func(interface{}, []byte) error { return testError(1) }),
+				AbandonAfter(5*time.Second), UnresponsiveGrace(6*time.Second),
+				SlowRunThreshold(7*time.Second), DedupeErrors(true),
+				FailureRateAlarm(0.5, 10*time.Minute, 3),
+				WithReporter(ReporterFunc(func(context.Context, error, ReportMeta) {})),
+			)
+			reg, budget, flights := NewRegistry(), NewBudget(1, time.Minute), &Flights{}
+			limiter := limiterFunc(nil)
+			gate, store := &testGate{}, &MemoryStore{}
+			clock, metrics := &manualClock{}, &recordingMetrics{}
+			locker := NewKVLocker(&MemoryKV{}, "owner")
+			as.NoError(inst.Update(
+				WithRegistry(reg), Overrun(OverrunRunOnce), CatchUp(CatchUpSkip),
+				CoalesceTriggers(true), Debounce(time.Second), Throttle(time.Minute),
+				RunWindow(time.Hour, 2*time.Hour, time.UTC),
+				TimeoutFn(func(uint64) time.Duration { return time.Hour }),
+				StopTimeout(9*time.Second), AwaitReady(true), RestartOnTimeout(false),
+				WithRetryBudget(budget), WithChanBuffer(10), Overflow(OverflowDropOldest),
+				ReportTermination(true), WrapErrors(true), KeepHistory(11),
+				HeartbeatTimeout(12*time.Second), Profiling(true), DetachValues(true),
+				HealthRules(MaxConsecutiveFailures(1)), OnError(func(error) {}),
+				ErrClassifier(func(error) Outcome { return Fatal }),
+				ValidateSuccess(func(context.Context) error { return nil }),
+				RunIf(func(context.Context) (bool, error) { return true, nil }),
+				WithContextFactory(func(ctx context.Context, _ Attempt) context.Context {
+					return ctx
+				}),
+				WithLimiter(limiter), WithGate(gate), WithStore(store),
+				WithClock(clock), WithMetrics(metrics),
+				WithMiddleware(func(next Runnable) Runnable { return next }),
+				Singleflight(flights, "key"), Coordinate(locker, "job", time.Hour),
+			))
+
+			settings := inst.Options()
+			as.Equal(time.Second, settings.Backoff(1))
+			as.Equal(time.Minute, settings.TimeoutBackoff(1))
+			as.Equal(testError(1), settings.RecoverWith(nil, nil))
+			as.Equal(time.Hour, settings.TimeoutFn(1))
+			as.NotNil(settings.Reporter)
+			as.NotNil(settings.OnError)
+			as.NotNil(settings.ErrClassifier)
+			as.NotNil(settings.ValidateSuccess)
+			as.NotNil(settings.RunIf)
+			as.NotNil(settings.ContextFactory)
+			as.Len(settings.HealthRules, 1)
+			as.Len(settings.Middleware, 1)
+			settings.Backoff, settings.TimeoutBackoff = nil, nil
+			settings.RecoverWith, settings.TimeoutFn = nil, nil
+			settings.Reporter, settings.OnError = nil, nil
+			settings.ErrClassifier, settings.ValidateSuccess = nil, nil
+			settings.RunIf, settings.ContextFactory = nil, nil
+			settings.HealthRules, settings.Middleware = nil, nil
+			as.Equal(Settings{
+				Name:              "sync",
+				Labels:            map[string]string{"team": "infra"},
+				Registry:          reg,
+				Recur:             true,
+				Period:            time.Minute,
+				PeriodJitter:      0.1,
+				FixedRate:         true,
+				Overrun:           OverrunRunOnce,
+				CatchUp:           CatchUpSkip,
+				Schedule:          sched,
+				Triggered:         true,
+				CoalesceTriggers:  true,
+				Debounce:          time.Second,
+				Throttle:          time.Minute,
+				Windowed:          true,
+				RunWindowFrom:     time.Hour,
+				RunWindowTo:       2 * time.Hour,
+				RunWindowLocation: time.UTC,
+				InitialDelay:      time.Second,
+				StartSplay:        2 * time.Second,
+				Timeout:           3 * time.Second,
+				TotalTimeout:      time.Hour,
+				StopGrace:         4 * time.Second,
+				StopTimeout:       9 * time.Second,
+				Concurrency:       2,
+				AwaitReady:        true,
+				RunLimit:          5,
+				AttemptLimit:      6,
+				Restart:           true,
+				RestartLimit:      7,
+				ResetOnSuccess:    true,
+				ResetAfterStable:  time.Minute,
+				RetryBudget:       budget,
+				Recover:           true,
+				Parent:            &parent,
+				BubbleErrors:      true,
+
+				ChanBuffer:        10,
+				Overflow:          OverflowDropOldest,
+				ReportTermination: true,
+				WrapErrors:        true,
+				KeepHistory:       11,
+				HeartbeatTimeout:  12 * time.Second,
+				Profiling:         true,
+				DetachValues:      true,
+				Limiter:           limiter,
+				Gate:              gate,
+				Store:             store,
+				Clock:             clock,
+				Metrics:           []Metrics{metrics},
+				Flights:           flights,
+				FlightKey:         "key",
+				Locker:            locker,
+				CoordinationKey:   "job",
+				CoordinationHold:  time.Hour,
+
+				TimeoutRestartLimit:  8,
+				AbandonAfter:         5 * time.Second,
+				UnresponsiveGrace:    6 * time.Second,
+				SlowRunThreshold:     7 * time.Second,
+				DedupeErrors:         true,
+				FailureRateThreshold: 0.5,
+				FailureRateWindow:    10 * time.Minute,
+				FailureRateMinRuns:   3,
+
+				RunsLeft:            5,
+				AttemptsLeft:        6,
+				RestartsLeft:        7,
+				TimeoutRestartsLeft: 8,
+			}, settings)
+
+			settings.Labels["team"] = "other"
+			as.Equal("infra", inst.Labels()["team"])
+		},
+		"all options described": func(t *testing.T) {
+			as := newAssertions(t)
+
+			// Options without a field of their own are described
+			// by the respective settings.
+			described := map[string]string{
+				"errChanSize":                "ChanBuffer",
+				"starting.delay":             "InitialDelay",
+				"starting.splay":             "StartSplay",
+				"recurring.jitter":           "PeriodJitter",
+				"window.restricted":          "Windowed",
+				"window.from":                "RunWindowFrom",
+				"window.to":                  "RunWindowTo",
+				"window.loc":                 "RunWindowLocation",
+				"restartable.restartOnError": "Restart",
+				"restartable.fatalTimeout":   "RestartOnTimeout",
+				"restartable.stableAfter":    "ResetAfterStable",
+				"restartable.timeoutLimit":   "TimeoutRestartLimit",
+				"recoverable.calm":           "Recover",
+				"recoverable.converter":      "RecoverWith",
+				"historySize":                "KeepHistory",
+				"health":                     "HealthRules",
+				"heartbeat":                  "HeartbeatTimeout",
+				"budget":                     "RetryBudget",
+				"timing":                     "Clock",
+				"reportEnd":                  "ReportTermination",
+				"contextFn":                  "ContextFactory",
+				"detached":                   "DetachValues",
+				"triggering.source":          "Triggered",
+				"triggering.coalesce":        "CoalesceTriggers",
+				"classifier":                 "ErrClassifier",
+				"validator":                  "ValidateSuccess",
+				"precondition":               "RunIf",
+				"coordination.locker":        "Locker",
+				"coordination.key":           "CoordinationKey",
+				"coordination.hold":          "CoordinationHold",
+				"parent":                     "Parent",
+				"bubble":                     "BubbleErrors",
+				"alarm.threshold":            "FailureRateThreshold",
+				"alarm.window":               "FailureRateWindow",
+				"alarm.minRuns":              "FailureRateMinRuns",
+				"slowAfter":                  "SlowRunThreshold",
+				"unresponsiveAfter":          "UnresponsiveGrace",
+				"abandonAfter":               "AbandonAfter",
+			}
+			settings := reflect.TypeOf(Settings{})
+			var walk func(prefix string, typ reflect.Type)
+			walk = func(prefix string, typ reflect.Type) {
+				for idx := 0; idx < typ.NumField(); idx++ {
+					field := typ.Field(idx)
+					path := prefix + field.Name
+					// Options grouped in structs of their own are walked
+					// (unlike those of other types, e.g. Schedule).
+					if field.Type.Kind() == reflect.Struct &&
+						!field.Type.Field(0).IsExported() {
+						walk(path+".", field.Type)
+						continue
+					}
+
+					name, ok := described[path]
+					if !ok {
+						// By default, the field is named after the option.
+						name = strings.ToUpper(field.Name[:1]) + field.Name[1:]
+					}
+					_, ok = settings.FieldByName(name)
+					as.True(ok, "option %s is not described by Settings.%s",
+						path, name)
+				}
+			}
+			walk("", reflect.TypeOf(options{}))
+		},
+		"zero instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var inst Instance
+			as.Equal(Settings{Concurrency: 1, RestartOnTimeout: true}, inst.Options())
+			inst = New(nil)
+			as.Equal(Settings{Concurrency: 1, RestartOnTimeout: true}, inst.Options())
+		},
+		"remaining counters": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				if runs++; runs%2 == 0 {
+					return testError(runs)
+				}
+				return nil
+			}, Recur(true), RunLimit(2), AttemptLimit(10),
+				Restart(true), RestartLimit(3, nil))
+
+			waitErrors(inst.Run(context.TODO()))
+
+			settings := inst.Options()
+			as.Zero(settings.RunsLeft)
+			as.Equal(uint64(7), settings.AttemptsLeft)
+			as.Equal(uint64(3), settings.RestartsLeft)
+
+			as.NoError(inst.Update(RunLimit(4)))
+			as.Equal(uint64(2), inst.Options().RunsLeft)
+		},
+		"remaining timeout restarts": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				if attempt, _ := AttemptFromContext(ctx); attempt.Number < 3 {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return testError(1)
+			}, Timeout(testTimeDelta), Restart(true), RestartLimit(1, nil),
+				TimeoutRestartLimit(3, nil))
+
+			waitErrors(inst.Run(context.TODO()))
+
+			settings := inst.Options()
+			as.Zero(settings.RestartsLeft)
+			as.Equal(uint64(1), settings.TimeoutRestartsLeft)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
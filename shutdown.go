@@ -0,0 +1,115 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"time"
+)
+
+// ErrRunAbandoned is propagated when an execution of a runnable
+// does not return within the stop grace period of its instance.
+var ErrRunAbandoned = errors.New("runnable did not stop in time")
+
+// StopGrace sets the maximum amount of time an execution of a runnable
+// is waited for once its instance is stopped (or its context is cancelled),
+// before it is abandoned and the instance terminates (default: 0, no limit).
+//
+// Abandoned executions emit RunAbandoned, with their goroutine left
+// running until the runnable returns (see Stats.Leaked). Executions are run on a separate
+// goroutine if this option is set.
+func StopGrace(d time.Duration) Option {
+	return func(o *options) *options {
+		o.stopGrace = d
+		return o
+	}
+}
+
+// grace returns the stop grace period of a runnable.
+func (o *options) grace() time.Duration {
+	if o == nil {
+		return 0
+	}
+	return o.stopGrace
+}
+
+// invoke executes the runnable of a worker once with the provided context,
+// and returns its error. If the context of the instance is cancelled
+// and the execution does not return within the stop grace period,
+// or its own context is cancelled and it does not return
+// within the abandonment limit, it is abandoned, in which case
+// a channel closed once it eventually returns is returned instead.
+//
+// A panic during the execution is propagated to the calling goroutine,
+// with the stack trace of the goroutine that panicked recorded
+// at the provided site.
+func (i *Instance) invoke(ctx, ctxt context.Context, w *worker,
+	site *panicSite) (err error, abandoned <-chan struct{}) {
+
+	grace, limit := i.options().grace(), i.options().abandonment()
+	if grace <= 0 && limit <= 0 {
+		return w.r(ctxt), nil
+	}
+
+	type result struct {
+		err      error
+		panicked bool
+		episode  interface{}
+		stack    []byte
+	}
+	// Buffered, so that an abandoned execution does not block.
+	resCh, returned := make(chan result, 1), make(chan struct{})
+	go func() {
+		defer close(returned)
+		panicked := true
+		defer func() {
+			if panicked {
+				stack := debug.Stack()
+				resCh <- result{panicked: true, episode: recover(), stack: stack}
+			}
+		}()
+
+		err := w.r(ctxt)
+		panicked = false
+		resCh <- result{err: err}
+	}()
+
+	// Wait for the cancellation of the instance if there is a grace period,
+	// and for the cancellation of the execution if there is a limit.
+	var stopping, cancelled <-chan struct{}
+	if grace > 0 {
+		stopping = ctx.Done()
+	}
+	if limit > 0 {
+		cancelled = ctxt.Done()
+	}
+	var res result
+	wait := grace
+	select {
+	case res = <-resCh:
+		wait = 0
+	case <-stopping:
+	case <-cancelled:
+		// The grace period applies if the instance is cancelled as well.
+		if stopping == nil || ctx.Err() == nil {
+			wait = limit
+		}
+	}
+	if wait > 0 {
+		timer := i.options().clock().NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case res = <-resCh:
+		case <-timer.C():
+			i.abandon(returned)
+			return nil, returned
+		}
+	}
+
+	if res.panicked {
+		site.record(res.stack)
+		panic(res.episode)
+	}
+	return res.err, nil
+}
@@ -0,0 +1,129 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testShutdown(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"StopGrace": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{StopGrace(time.Second)})
+			as.Equal(&options{stopGrace: time.Second}, opts)
+			as.Equal(time.Second, opts.grace())
+
+			opts = nil
+			as.Zero(opts.grace())
+		},
+		"abandoned on stop": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			defer close(release)
+			m := &recordingMetrics{}
+			inst := New(func(context.Context) error {
+				<-release
+				return nil
+			}, StopGrace(testTimeDelta), WithMetrics(m), WithChanBuffer(3))
+
+			evCh := inst.Events(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			inst.Stop()
+
+			as.Equal([]Event{
+				RunStarted{},
+				RunAbandoned{},
+				Terminated{},
+			}, waitEvents(evCh))
+			as.Equal(StateStopped, inst.State())
+			as.Equal([]string{
+				"started",
+				"finished: " + ErrRunAbandoned.Error(),
+				"terminated",
+			}, m.calls)
+		},
+		"abandoned on cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			defer close(release)
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+
+			inst := New(func(context.Context) error {
+				<-release
+				return nil
+			}, StopGrace(testTimeDelta))
+
+			as.Equal([]error{ErrRunAbandoned, context.DeadlineExceeded},
+				waitErrors(inst.Run(ctx)))
+			as.Equal(StateTerminated, inst.State())
+		},
+		"abandoned on stop with final error": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			defer close(release)
+			inst := New(func(context.Context) error {
+				<-release
+				return nil
+			}, StopGrace(testTimeDelta))
+
+			execute, interrupt := inst.Actor(context.TODO())
+			done := make(chan error)
+			go func() {
+				done <- execute()
+			}()
+			as.NoError(inst.WaitReady(context.TODO()))
+			interrupt(nil)
+			as.Equal(ErrRunAbandoned, <-done)
+		},
+		"returning within grace period": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				time.Sleep(testTimeDelta / 2)
+				return ctx.Err()
+			}, StopGrace(2*testTimeDelta))
+
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			inst.Stop()
+
+			as.Equal([]error{context.Canceled}, waitErrors(errCh))
+		},
+		"returning before cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			err := Do(context.TODO(), func(context.Context) error {
+				calls++
+				return nil
+			}, StopGrace(testTimeDelta), Recur(true), RunLimit(3))
+			as.NoError(err)
+			as.Equal(3, calls)
+		},
+		"panic is propagated": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				panic("stuck")
+			}, StopGrace(testTimeDelta), Recover(true))
+
+			as.Equal([]Event{
+				RunStarted{},
+				Recovered{Panic: "stuck"},
+				Terminated{Reason: RunnablePanic{Value: "stuck"}},
+			}, waitEvents(inst.Events(context.TODO())))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,113 @@
+package run
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Exit codes returned by UntilSignal.
+const (
+	// ExitOK indicates that the runner terminated without errors,
+	// or that it shut down gracefully upon request without having
+	// propagated errors before.
+	ExitOK = 0
+	// ExitFailure indicates that the runner propagated errors
+	// before terminating (on its own or upon request),
+	// or that it had already been run.
+	ExitFailure = 1
+	// ExitTimeout indicates that the runner did not terminate
+	// within the grace period of a requested shutdown.
+	ExitTimeout = 2
+)
+
+// Runner represents anything that can be run once and stopped,
+// propagating its errors to a channel, such as an Instance or a Group.
+type Runner interface {
+	Run(ctx context.Context) <-chan error
+	Stop()
+}
+
+// UntilSignal runs a runner under the provided context until it terminates,
+// or until one of the provided signals (os.Interrupt and syscall.SIGTERM
+// if none is provided) is received or the context is cancelled,
+// in which case the runner is stopped and given the grace period
+// to terminate (indefinitely if zero).
+// It blocks until then, and returns the exit code of the process.
+//
+// Errors propagated by the runner are logged to the default logger
+// (see UntilSignalSink), and result in ExitFailure even if
+// the runner is shut down gracefully afterwards.
+func UntilSignal(ctx context.Context, r Runner, grace time.Duration,
+	signals ...os.Signal) int {
+
+	return UntilSignalSink(ctx, r, grace, SlogSink(nil, "runner error"),
+		signals...)
+}
+
+// UntilSignalSink runs a runner similarly to UntilSignal,
+// passing the errors it propagates to the provided sink
+// (discarding them if it is nil) instead of logging them.
+//
+// Errors propagated during a requested shutdown are passed to the sink too,
+// though they do not affect the exit code, since they usually result
+// from the shutdown itself (e.g. context.Canceled).
+func UntilSignalSink(ctx context.Context, r Runner, grace time.Duration,
+	sink ErrorSink, signals ...os.Signal) int {
+
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	if sink == nil {
+		sink = func(error) {}
+	}
+	sigCtx, stop := signal.NotifyContext(ctx, signals...)
+	defer stop()
+
+	errCh := r.Run(ctx)
+	if errCh == nil {
+		return ExitFailure
+	}
+
+	code := ExitOK
+	for {
+		select {
+		case err, ok := <-errCh:
+			if !ok {
+				return code
+			}
+			sink(err)
+			code = ExitFailure
+		case <-sigCtx.Done():
+			r.Stop()
+			return max(code, shutdown(errCh, grace, sink))
+		}
+	}
+}
+
+// shutdown waits for the error channel of a stopped runner to be closed,
+// for up to the provided grace period (indefinitely if zero),
+// passing its errors to the provided sink,
+// and returns the exit code of the process.
+func shutdown(errCh <-chan error, grace time.Duration, sink ErrorSink) int {
+	var expired <-chan time.Time
+	if grace != 0 {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		select {
+		case err, ok := <-errCh:
+			if !ok {
+				return ExitOK
+			}
+			sink(err)
+		case <-expired:
+			return ExitTimeout
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package run
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"testing"
+)
+
+func testSignal(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"terminating on its own": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			})
+			as.Equal(ExitOK, UntilSignal(context.TODO(), &inst, 0))
+			// Already run.
+			as.Equal(ExitFailure, UntilSignal(context.TODO(), &inst, 0))
+
+			inst = New(func(context.Context) error {
+				return testError(1)
+			})
+			as.Equal(ExitFailure, UntilSignal(context.TODO(), &inst, 0))
+
+			inst = New(func(context.Context) error {
+				return testError(1)
+			})
+			as.Equal(ExitFailure, UntilSignalSink(context.TODO(), &inst, 0, nil))
+		},
+		"signal": func(t *testing.T) {
+			as := newAssertions(t)
+
+			// Guard against delivery of the signal while not being notified.
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt)
+			defer signal.Stop(sigCh)
+
+			inst := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+			code := make(chan int)
+			go func() {
+				code <- UntilSignal(context.TODO(), &inst, 0, os.Interrupt)
+			}()
+			as.NoError(inst.WaitReady(context.TODO()))
+			proc, err := os.FindProcess(os.Getpid())
+			as.NoError(err)
+			as.NoError(proc.Signal(os.Interrupt))
+
+			as.Equal(ExitOK, <-code)
+			as.Equal(StateStopped, inst.State())
+		},
+		"cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}))
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			code := make(chan int)
+			go func() {
+				code <- UntilSignal(ctx, g, testTimeDelta)
+			}()
+			as.NoError(g.WaitReady(context.TODO()))
+			cancel()
+
+			as.Equal(ExitOK, <-code)
+		},
+		"errors before shutdown": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				if attempt, _ := AttemptFromContext(ctx); attempt.Number == 1 {
+					return testError(1)
+				}
+				<-ctx.Done()
+				return nil
+			}, Restart(true))
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			errs := make(chan error, 1)
+			code := UntilSignalSink(ctx, &inst, 0, func(err error) {
+				errs <- err
+				cancel()
+			})
+
+			as.Equal(ExitFailure, code)
+			as.Equal(testError(1), <-errs)
+			as.Equal(StateStopped, inst.State())
+		},
+		"grace period expiry": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			defer close(release)
+			inst := New(func(context.Context) error {
+				<-release
+				return nil
+			})
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			code := make(chan int)
+			go func() {
+				code <- UntilSignal(ctx, &inst, testTimeDelta)
+			}()
+			as.NoError(inst.WaitReady(context.TODO()))
+			cancel()
+
+			as.Equal(ExitTimeout, <-code)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,91 @@
+package run
+
+import (
+	"context"
+	"sync"
+)
+
+// Flights deduplicates the executions of runnables sharing a key
+// (see Singleflight), so that an execution starting while another one
+// with the same key is in flight waits for it instead,
+// sharing its result.
+//
+// The zero value is ready to use, and it can be shared among instances.
+type Flights struct {
+	mu      sync.Mutex
+	pending map[string]*flight
+}
+
+// flight is an in-flight execution.
+type flight struct {
+	done chan struct{}
+	err  error
+}
+
+// Singleflight deduplicates the executions of a runnable with those
+// of any runnables sharing the provided key through the provided flights
+// (default: nil, not deduplicated), such as cache refreshes triggered
+// from many places.
+//
+// A deduplicated execution returns the error of the one in flight,
+// or that it panicked as a RunnablePanic, unless its context is done first.
+// Middleware wraps the deduplicated executions (see WithMiddleware).
+func Singleflight(f *Flights, key string) Option {
+	return func(o *options) *options {
+		o.flights, o.flightKey = f, key
+		return o
+	}
+}
+
+// Do executes the provided runnable, unless an execution with
+// the provided key is already in flight, in which case it waits for it
+// and returns its error (see Singleflight).
+func (f *Flights) Do(ctx context.Context, key string, r Runnable) error {
+	f.mu.Lock()
+	if c, ok := f.pending[key]; ok {
+		f.mu.Unlock()
+
+		select {
+		case <-c.done:
+			return c.err
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+	if f.pending == nil {
+		f.pending = make(map[string]*flight)
+	}
+	c := &flight{done: make(chan struct{})}
+	f.pending[key] = c
+	f.mu.Unlock()
+
+	panicked := true
+	defer func() {
+		if panicked {
+			c.err = RunnablePanic{Value: recover()}
+		}
+		f.mu.Lock()
+		delete(f.pending, key)
+		f.mu.Unlock()
+		close(c.done)
+
+		if panicked {
+			panic(c.err.(RunnablePanic).Value)
+		}
+	}()
+
+	c.err = r.run(ctx)
+	panicked = false
+	return c.err
+}
+
+// share deduplicates the executions of a runnable,
+// if the appropriate option is set.
+func (o *options) share(r Runnable) Runnable {
+	if o.flights == nil {
+		return r
+	}
+	return func(ctx context.Context) error {
+		return o.flights.Do(ctx, o.flightKey, r)
+	}
+}
@@ -0,0 +1,112 @@
+package run
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testSingleflight(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"executions are shared": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var flights Flights
+			var calls int32
+			started, release := make(chan struct{}), make(chan struct{})
+			r := func(context.Context) error {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					close(started)
+				}
+				<-release
+				return testError(1)
+			}
+			leader := New(r, Singleflight(&flights, "refresh"))
+			follower := New(r, Singleflight(&flights, "refresh"))
+
+			leaderCh := leader.Run(context.TODO())
+			<-started
+			followerCh := follower.Run(context.TODO())
+			time.Sleep(testTimeDelta)
+			close(release)
+
+			as.Equal([]error{testError(1)}, waitErrors(leaderCh))
+			as.Equal([]error{testError(1)}, waitErrors(followerCh))
+			as.Equal(int32(1), atomic.LoadInt32(&calls))
+		},
+		"distinct keys are not shared": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var flights Flights
+			var calls int32
+			release := make(chan struct{})
+			r := func(context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return nil
+			}
+			first := New(r, Singleflight(&flights, "first"))
+			second := New(r, Singleflight(&flights, "second"))
+
+			firstCh, secondCh := first.Run(context.TODO()), second.Run(context.TODO())
+			time.Sleep(testTimeDelta)
+			close(release)
+
+			as.Empty(waitErrors(firstCh))
+			as.Empty(waitErrors(secondCh))
+			as.Equal(int32(2), atomic.LoadInt32(&calls))
+		},
+		"waiting is cancelled": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var flights Flights
+			started, release := make(chan struct{}), make(chan struct{})
+			go func() {
+				_ = flights.Do(context.TODO(), "key", func(context.Context) error {
+					close(started)
+					<-release
+					return nil
+				})
+			}()
+			defer close(release)
+			<-started
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			cancel()
+			as.Equal(context.Canceled, flights.Do(ctx, "key", nil))
+		},
+		"panics are shared": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var flights Flights
+			started, release := make(chan struct{}), make(chan struct{})
+			inst := New(func(context.Context) error {
+				close(started)
+				<-release
+				panic("panic message")
+			}, Recover(true), Singleflight(&flights, "key"))
+
+			errCh := inst.Run(context.TODO())
+			<-started
+			resCh := make(chan error)
+			go func() {
+				resCh <- flights.Do(context.TODO(), "key", nil)
+			}()
+			time.Sleep(testTimeDelta)
+			close(release)
+
+			as.Equal([]error{RunnablePanic{"panic message"}}, waitErrors(errCh))
+			as.Equal(RunnablePanic{"panic message"}, <-resCh)
+
+			// Flights end with their executions.
+			as.NoError(flights.Do(context.TODO(), "key", func(context.Context) error {
+				return nil
+			}))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"context"
+	"time"
+)
+
+// Sleep pauses for the provided duration, unless the provided context
+// is done first, in which case it returns its error.
+//
+// Within an execution of a runnable, it follows the clock
+// of the instance (see WithClock).
+func Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	timer := contextClock(ctx).NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Every calls the provided function repeatedly, pausing for the provided
+// duration after each call returns (see Sleep), until it fails
+// or the provided context is done, and returns the error
+// of the function or the context respectively.
+func Every(ctx context.Context, d time.Duration,
+	fn func(ctx context.Context) error) error {
+
+	for {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		if err := Sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// contextClock returns the clock of the instance executing
+// with the provided context, or the system clock.
+func contextClock(ctx context.Context) Clock {
+	if i, ok := ctx.Value(readyKey{}).(*Instance); ok {
+		return i.options().clock()
+	}
+	return SystemClock
+}
@@ -0,0 +1,86 @@
+package run
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingClock is a system clock recording the durations
+// of the timers created by NewTimer.
+type recordingClock struct {
+	systemClock
+
+	mu     sync.Mutex
+	timers []time.Duration
+}
+
+func (c *recordingClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timers = append(c.timers, d)
+	return c.systemClock.NewTimer(d)
+}
+
+func testSleep(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"sleeps": func(t *testing.T) {
+			as := newAssertions(t)
+
+			started := time.Now()
+			as.NoError(Sleep(context.TODO(), testTimeDelta))
+			as.GreaterOrEqual(time.Since(started), testTimeDelta)
+			as.NoError(Sleep(context.TODO(), -time.Second))
+		},
+		"cancelled": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			cancel()
+			as.Equal(context.Canceled, Sleep(ctx, time.Hour))
+
+			ctx, cancel = context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+			as.Equal(context.DeadlineExceeded, Sleep(ctx, time.Hour))
+		},
+		"instance clock": func(t *testing.T) {
+			as := newAssertions(t)
+
+			clock := &recordingClock{}
+			inst := New(func(ctx context.Context) error {
+				return Sleep(ctx, time.Millisecond)
+			}, WithClock(clock))
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Contains(clock.timers, time.Millisecond)
+		},
+		"every": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			err := Every(context.TODO(), time.Millisecond, func(context.Context) error {
+				if calls++; calls == 3 {
+					return testError(calls)
+				}
+				return nil
+			})
+			as.Equal(testError(3), err)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			calls = 0
+			err = Every(ctx, time.Hour, func(context.Context) error {
+				calls++
+				cancel()
+				return nil
+			})
+			as.Equal(context.Canceled, err)
+			as.Equal(1, calls)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
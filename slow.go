@@ -0,0 +1,69 @@
+package run
+
+import (
+	"sync"
+	"time"
+)
+
+// SlowRunThreshold sets the duration after which an execution
+// still in progress is considered slow (default: 0, none),
+// e.g. to catch performance regressions before they lead to timeouts.
+//
+// Once an execution exceeds it, RunSlow is emitted
+// (and reported to metrics hooks implementing SlowMetrics),
+// while the execution proceeds as usual.
+func SlowRunThreshold(d time.Duration) Option {
+	return func(o *options) *options {
+		o.slowAfter = d
+		return o
+	}
+}
+
+// RunSlow is emitted when an execution of the runnable exceeds
+// the slow run threshold of its instance (see SlowRunThreshold).
+type RunSlow struct {
+	// Attempt is the (1-based) number of the execution.
+	Attempt uint64
+	// Elapsed is the duration of the execution so far.
+	Elapsed time.Duration
+}
+
+func (RunSlow) event() {}
+
+// SlowMetrics can be implemented by a metrics hook
+// to be notified of slow executions (see SlowRunThreshold).
+type SlowMetrics interface {
+	RunSlow(attempt uint64, elapsed time.Duration)
+}
+
+// watchSlow emits RunSlow once an execution that started at the provided time
+// exceeds the slow run threshold (if any), unless the returned function
+// is called before that.
+func (o *options) watchSlow(attempt Attempt, started time.Time,
+	emit func(Event)) func() {
+
+	if o == nil || o.slowAfter <= 0 {
+		return func() {}
+	}
+
+	var mu sync.Mutex
+	finished := false
+	timer := o.clock().AfterFunc(o.slowAfter, func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !finished {
+			emit(RunSlow{
+				Attempt: attempt.Number,
+				Elapsed: o.clock().Now().Sub(started),
+			})
+		}
+	})
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		finished = true
+		timer.Stop()
+	}
+}
@@ -0,0 +1,90 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type slowMetrics struct {
+	recordingMetrics
+}
+
+func (m *slowMetrics) RunSlow(attempt uint64, _ time.Duration) {
+	m.record("slow: %d", attempt)
+}
+
+func testSlow(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"SlowRunThreshold": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{SlowRunThreshold(time.Second)})
+			as.Equal(&options{slowAfter: time.Second}, opts)
+
+			_, err := NewChecked(nil, SlowRunThreshold(-time.Second))
+			as.Equal(OptionError{Option: "SlowRunThreshold", Reason: "must not be negative"}, err)
+		},
+		"slow executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			c := new(manualClock)
+			m := &slowMetrics{}
+			started, release := make(chan struct{}), make(chan struct{})
+			runs := 0
+			inst := New(func(context.Context) error {
+				if runs++; runs == 1 {
+					close(started)
+					<-release
+				}
+				return nil
+			}, Recur(true), RunLimit(2), SlowRunThreshold(time.Minute),
+				WithClock(c), WithMetrics(m))
+
+			evCh := inst.Events(context.TODO())
+			go func() {
+				<-started
+				c.fire()
+				close(release)
+			}()
+			as.Equal([]Event{
+				RunStarted{},
+				RunSlow{Attempt: 1},
+				RunSucceeded{},
+				RunStarted{},
+				RunSucceeded{},
+				Terminated{},
+			}, waitEvents(evCh))
+			as.Equal([]string{
+				"started", "slow: 1", "finished: <nil>",
+				"started", "finished: <nil>", "terminated",
+			}, m.calls)
+
+			// Thresholds elapsing after executions finish are ignored.
+			as.NotPanics(c.fire)
+		},
+		"elapsed time": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				time.Sleep(2 * testTimeDelta)
+				return nil
+			}, SlowRunThreshold(testTimeDelta))
+
+			var slow []RunSlow
+			for ev := range inst.Events(context.TODO()) {
+				if e, ok := ev.(RunSlow); ok {
+					slow = append(slow, e)
+				}
+			}
+			if as.Len(slow, 1) {
+				as.GreaterOrEqual(slow[0].Elapsed, testTimeDelta)
+				as.Less(slow[0].Elapsed, 2*testTimeDelta)
+			}
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
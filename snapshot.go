@@ -0,0 +1,90 @@
+package run
+
+import "time"
+
+// GroupSnapshot represents the status of the members of a group
+// at a point in time (see Group.Snapshot), and can be JSON-encoded.
+type GroupSnapshot struct {
+	// Members holds the status of each member,
+	// in the order they were added.
+	Members []InstanceSnapshot `json:"members"`
+}
+
+// InstanceSnapshot represents the status of an instance at a point in time
+// (see Instance.Snapshot), as derived from its execution statistics
+// (see Stats), and can be JSON-encoded.
+type InstanceSnapshot struct {
+	// Name is the name of the instance (see WithName),
+	// or that of the member, for members of a group.
+	Name string `json:"name,omitempty"`
+	// State is the current state of the instance.
+	State State `json:"state"`
+	// Paused indicates whether the instance is paused.
+	Paused bool `json:"paused,omitempty"`
+	// Runs, FailedRuns and ConsecutiveFailures
+	// are the respective execution statistics of the instance.
+	Runs                uint64 `json:"runs"`
+	FailedRuns          uint64 `json:"failed_runs"`
+	ConsecutiveFailures uint64 `json:"consecutive_failures,omitempty"`
+	// LastError is the message of the error
+	// of the latest failed execution, if any.
+	LastError string `json:"last_error,omitempty"`
+	// LastDuration is the duration of the latest execution.
+	LastDuration time.Duration `json:"last_duration,omitempty"`
+	// LastSuccess is the time the latest successful execution finished,
+	// and NextRun is the time the next execution is scheduled for, if any.
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	NextRun     *time.Time `json:"next_run,omitempty"`
+}
+
+// Snapshot returns the status of an instance.
+//
+// It is safe to call while the instance is running.
+func (i *Instance) Snapshot() InstanceSnapshot {
+	stats := i.Stats()
+	snapshot := InstanceSnapshot{
+		Name:                i.Name(),
+		State:               stats.State,
+		Paused:              i.Paused(),
+		Runs:                stats.Runs,
+		FailedRuns:          stats.FailedRuns,
+		ConsecutiveFailures: stats.ConsecutiveFailures,
+		LastDuration:        stats.LastDuration,
+		LastSuccess:         optionalTime(stats.LastSuccess),
+		NextRun:             optionalTime(stats.NextRun),
+	}
+	if stats.LastError != nil {
+		snapshot.LastError = stats.LastError.Error()
+	}
+	return snapshot
+}
+
+// Snapshot returns the status of the members of a group.
+//
+// It is safe to call while the group is running.
+func (g *Group) Snapshot() GroupSnapshot {
+	g.mu.Lock()
+	members := make([]member, 0, len(g.members))
+	for _, m := range g.members {
+		members = append(members, member{name: m.name, inst: m.inst})
+	}
+	g.mu.Unlock()
+
+	snapshot := GroupSnapshot{
+		Members: make([]InstanceSnapshot, 0, len(members)),
+	}
+	for _, m := range members {
+		ms := m.inst.Snapshot()
+		ms.Name = m.name
+		snapshot.Members = append(snapshot.Members, ms)
+	}
+	return snapshot
+}
+
+// optionalTime returns a pointer to the provided time, or nil if it is zero.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,137 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func testSnapshot(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"empty group": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.Equal(GroupSnapshot{Members: []InstanceSnapshot{}},
+				NewGroup().Snapshot())
+		},
+		"member status": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup()
+			runs := 0
+			as.NoError(g.Add("a", func(context.Context) error {
+				if runs++; runs == 1 {
+					return testError("a")
+				}
+				return nil
+			}, Restart(true)))
+			as.NoError(g.Add("b", func(context.Context) error {
+				return nil
+			}, Recur(true), Period(time.Hour)))
+			as.NoError(g.Add("c", func(context.Context) error {
+				return nil
+			}))
+			c, _ := g.Member("c")
+			c.Pause()
+
+			errCh := g.Run(context.TODO())
+			go waitErrors(errCh)
+			a, _ := g.Member("a")
+			b, _ := g.Member("b")
+			<-a.Done()
+			as.Eventually(func() bool {
+				return b.State() == StateWaitingPeriod
+			}, time.Second, time.Millisecond)
+
+			snapshot := g.Snapshot()
+			g.Stop()
+			if !as.Len(snapshot.Members, 3) {
+				return
+			}
+
+			as.NotNil(snapshot.Members[0].LastSuccess)
+			snapshot.Members[0].LastSuccess = nil
+			snapshot.Members[0].LastDuration = 0
+			as.Equal(InstanceSnapshot{
+				Name:       "a",
+				State:      StateTerminated,
+				Runs:       2,
+				FailedRuns: 1,
+				LastError:  testError("a").Error(),
+			}, snapshot.Members[0])
+
+			as.Equal("b", snapshot.Members[1].Name)
+			as.Equal(StateWaitingPeriod, snapshot.Members[1].State)
+			if as.NotNil(snapshot.Members[1].NextRun) {
+				as.WithinDuration(time.Now().Add(time.Hour),
+					*snapshot.Members[1].NextRun, testTimeDelta)
+			}
+
+			// The paused member is due, but waits to be resumed.
+			as.NotNil(snapshot.Members[2].NextRun)
+			snapshot.Members[2].NextRun = nil
+			as.Equal(InstanceSnapshot{
+				Name:   "c",
+				State:  StateIdle,
+				Paused: true,
+			}, snapshot.Members[2])
+		},
+		"instance status": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, WithName("job"))
+			as.Equal(InstanceSnapshot{Name: "job", State: StateIdle},
+				inst.Snapshot())
+
+			waitErrors(inst.Run(context.TODO()))
+			snapshot := inst.Snapshot()
+			snapshot.LastDuration = 0
+			as.Equal(InstanceSnapshot{
+				Name:                "job",
+				State:               StateTerminated,
+				Runs:                1,
+				FailedRuns:          1,
+				ConsecutiveFailures: 1,
+				LastError:           testError(1).Error(),
+			}, snapshot)
+		},
+		"JSON encoding": func(t *testing.T) {
+			as := newAssertions(t)
+
+			next := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			encoded, err := json.Marshal(GroupSnapshot{
+				Members: []InstanceSnapshot{
+					{Name: "a", State: StateRunning, Runs: 1},
+					{
+						Name:                "b",
+						State:               StateBackingOff,
+						Paused:              true,
+						Runs:                2,
+						FailedRuns:          2,
+						ConsecutiveFailures: 1,
+						LastError:           "failed",
+						LastDuration:        time.Second,
+						NextRun:             &next,
+					},
+				},
+			})
+			as.NoError(err)
+			as.JSONEq(`{"members": [
+				{"name": "a", "state": "running", "runs": 1, "failed_runs": 0},
+				{
+					"name": "b", "state": "backing off", "paused": true,
+					"runs": 2, "failed_runs": 2, "consecutive_failures": 1,
+					"last_error": "failed", "last_duration": 1000000000,
+					"next_run": "2024-01-02T03:04:05Z"
+				}
+			]}`, string(encoded))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,130 @@
+package run
+
+import (
+	"fmt"
+	"time"
+)
+
+// State represents the execution state of an instance.
+type State int
+
+const (
+	// StateIdle is the state of an instance before its first execution.
+	StateIdle State = iota
+	// StateRunning is the state of an instance while its runnable executes.
+	StateRunning
+	// StateBackingOff is the state of an instance waiting
+	// to restart after a failed execution.
+	StateBackingOff
+	// StateWaitingPeriod is the state of a recurring instance waiting
+	// for its period to elapse after a successful execution.
+	StateWaitingPeriod
+	// StateTerminated is the state of an instance that stopped executing
+	// on its own (according to its options, or due to context cancellation).
+	StateTerminated
+	// StateStopped is the state of an instance that was stopped.
+	StateStopped
+)
+
+var stateNames = map[State]string{
+	StateIdle:          "idle",
+	StateRunning:       "running",
+	StateBackingOff:    "backing off",
+	StateWaitingPeriod: "waiting period",
+	StateTerminated:    "terminated",
+	StateStopped:       "stopped",
+}
+
+// String satisfies fmt.Stringer interface for State.
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// MarshalText satisfies encoding.TextMarshaler interface for State,
+// encoding it as its name (see String).
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText satisfies encoding.TextUnmarshaler interface for State,
+// decoding it from its name.
+func (s *State) UnmarshalText(text []byte) error {
+	for state, name := range stateNames {
+		if name == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown state %q", text)
+}
+
+// Final indicates whether a state is final,
+// that is, no transitions are possible from it.
+func (s State) Final() bool {
+	return s == StateTerminated || s == StateStopped
+}
+
+// StateTransition represents a change in the state of an instance.
+type StateTransition struct {
+	From, To State
+	// At is the time of the transition.
+	At time.Time
+}
+
+// StateMetrics can be implemented by a metrics hook
+// to be notified of the state transitions of an instance.
+type StateMetrics interface {
+	StateChanged(tr StateTransition)
+}
+
+// State returns the current state of an instance.
+//
+// It is safe to call while the instance is running.
+func (i *Instance) State() State {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.stats.State
+}
+
+// StateChanges returns a channel where the state transitions
+// of an instance are propagated, which is closed once
+// the instance reaches a final state.
+// Each call creates a new subscription,
+// with transitions preceding it not being propagated.
+//
+// The channel buffer size is controlled by WithChanBuffer,
+// and similarly to the error channel, delivery of a transition
+// blocks the execution of the instance until it is received.
+func (i *Instance) StateChanges() <-chan StateTransition {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ch := make(chan StateTransition, i.options().chanSize())
+	if i.stats.State.Final() {
+		close(ch)
+		return ch
+	}
+	i.watchers = append(i.watchers, ch)
+	return ch
+}
+
+// notify propagates a state transition to the subscribers
+// and the metrics hooks of an instance.
+func (i *Instance) notify(tr StateTransition, watchers []chan StateTransition) {
+	for _, m := range i.options().measure() {
+		if sm, ok := m.(StateMetrics); ok {
+			sm.StateChanged(tr)
+		}
+	}
+
+	for _, ch := range watchers {
+		ch <- tr
+		if tr.To.Final() {
+			close(ch)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// waitTransitions drains a state transition channel,
+// keeping only the target states.
+func waitTransitions(trCh <-chan StateTransition) []State {
+	states := make([]State, 0)
+	for tr := range trCh {
+		states = append(states, tr.To)
+	}
+	return states
+}
+
+func testState(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"names": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.Equal("idle", StateIdle.String())
+			as.Equal("running", StateRunning.String())
+			as.Equal("backing off", StateBackingOff.String())
+			as.Equal("waiting period", StateWaitingPeriod.String())
+			as.Equal("terminated", StateTerminated.String())
+			as.Equal("stopped", StateStopped.String())
+			as.Equal("State(42)", State(42).String())
+		},
+		"text encoding": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for _, state := range []State{StateIdle, StateBackingOff, StateStopped} {
+				text, err := state.MarshalText()
+				as.NoError(err)
+				as.Equal(state.String(), string(text))
+
+				var decoded State
+				as.NoError(decoded.UnmarshalText(text))
+				as.Equal(state, decoded)
+			}
+
+			var decoded State
+			as.EqualError(decoded.UnmarshalText([]byte("unknown")),
+				`unknown state "unknown"`)
+		},
+		"final states": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.False(StateIdle.Final())
+			as.False(StateRunning.Final())
+			as.False(StateBackingOff.Final())
+			as.False(StateWaitingPeriod.Final())
+			as.True(StateTerminated.Final())
+			as.True(StateStopped.Final())
+		},
+		"transitions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			inst := New(func(context.Context) error {
+				calls++
+				if calls == 1 {
+					return testError(1)
+				}
+				return nil
+			},
+				Recur(true),
+				RunLimit(2),
+				Restart(true),
+				RestartLimit(0, nil),
+			)
+			as.Equal(StateIdle, inst.State())
+
+			trCh := inst.StateChanges()
+			errCh := inst.Run(context.TODO())
+			go waitErrors(errCh)
+			states := waitTransitions(trCh)
+
+			as.Equal([]State{
+				StateRunning, StateBackingOff,
+				StateRunning, StateWaitingPeriod,
+				StateRunning, StateTerminated,
+			}, states)
+			as.Equal(StateTerminated, inst.State())
+
+			_, open := <-inst.StateChanges()
+			as.False(open, "subscription after termination is closed")
+		},
+		"stop before run": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			})
+			inst.Stop()
+
+			trCh := inst.StateChanges()
+			evCh := inst.Events(context.TODO())
+			as.Equal([]State{StateStopped}, waitTransitions(trCh))
+			as.Equal([]Event{Terminated{}}, waitEvents(evCh))
+		},
+		"stop during run": func(t *testing.T) {
+			as := newAssertions(t)
+
+			started := make(chan struct{})
+			inst := New(func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			}, Recur(true), Period(time.Hour))
+
+			errCh := inst.Run(context.TODO())
+			<-started
+			inst.Stop()
+
+			as.Equal([]error{context.Canceled}, waitErrors(errCh))
+			as.Equal(StateStopped, inst.State())
+		},
+		"state metrics": func(t *testing.T) {
+			as := newAssertions(t)
+
+			m := &stateMetrics{}
+			inst := New(func(context.Context) error {
+				return nil
+			}, WithMetrics(m))
+			waitErrors(inst.Run(context.TODO()))
+
+			as.Equal([]State{StateRunning, StateTerminated}, m.states)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
+
+// stateMetrics records the states of an instance.
+type stateMetrics struct {
+	recordingMetrics
+	states []State
+}
+
+func (m *stateMetrics) StateChanged(tr StateTransition) {
+	m.states = append(m.states, tr.To)
+}
@@ -0,0 +1,176 @@
+package run
+
+import "time"
+
+// Stats represents a snapshot of the execution statistics of an instance.
+type Stats struct {
+	// Runs is the total number of executions of the runnable,
+	// both successful and failed (see Instance.SuccessfulRuns).
+	Runs uint64
+	// FailedRuns is the total number of failed executions of the runnable.
+	FailedRuns uint64
+	// ConsecutiveFailures is the number of failed executions
+	// accounted towards the restart limit of the runnable.
+	ConsecutiveFailures uint64
+	// ConsecutiveTimeouts is the number of timed out executions
+	// accounted towards their own restart limit (see TimeoutRestartLimit).
+	ConsecutiveTimeouts uint64
+	// LastError is the error of the latest failed execution.
+	LastError error
+	// LastDuration is the duration of the latest execution.
+	LastDuration time.Duration
+	// LastSuccess is the time the latest successful execution finished,
+	// or zero if there is none.
+	LastSuccess time.Time
+	// StartedAt is the time the instance started running,
+	// or zero if it has not.
+	StartedAt time.Time
+	// NextRun is the time the next execution is scheduled for,
+	// or zero if none is scheduled.
+	NextRun time.Time
+	// State is the current state of the instance.
+	State State
+	// Progress is the latest progress reported by the current
+	// (or latest) execution of the runnable, if any (see Progress).
+	Progress ProgressReported
+	// DroppedErrors is the number of errors dropped
+	// from the error channel of the instance (see Overflow).
+	DroppedErrors uint64
+	// RunTime is the cumulative duration of the executions of the runnable
+	// (of all its copies, see Concurrency).
+	RunTime time.Duration
+	// BackoffTime and WaitTime are the cumulative amounts of time
+	// the instance spent backing off after failed executions,
+	// and waiting for the next execution otherwise
+	// (including its initial delay, schedule and triggers) respectively.
+	BackoffTime, WaitTime time.Duration
+	// Uptime is the amount of time the instance has been running for,
+	// or ran for if it has terminated.
+	Uptime time.Duration
+	// Abandoned is the total number of abandoned executions
+	// (see StopGrace and AbandonAfter), while Leaked is the number
+	// of those still running, including ones abandoned before a Reset.
+	Abandoned, Leaked uint64
+}
+
+// Stats returns a snapshot of the execution statistics of an instance.
+//
+// It is safe to call while the instance is running.
+func (i *Instance) Stats() Stats {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	stats := i.stats
+	stats.Leaked = i.leaked.Load()
+	if !stats.StartedAt.IsZero() && !stats.State.Final() {
+		// Account for the time elapsed as of the snapshot.
+		now := i.options().clock().Now()
+		stats.elapse(i.since, now)
+		stats.Uptime = now.Sub(stats.StartedAt)
+	}
+	return stats
+}
+
+// elapse accounts the time elapsed in the current state of an instance
+// since the provided time (if set) to the respective statistics,
+// as of the provided time.
+func (s *Stats) elapse(since, now time.Time) {
+	if since.IsZero() {
+		return
+	}
+
+	switch s.State {
+	case StateBackingOff:
+		s.BackoffTime += now.Sub(since)
+	case StateIdle, StateWaitingPeriod:
+		s.WaitTime += now.Sub(since)
+	}
+}
+
+// SuccessfulRuns returns the number of successful executions
+// of an instance, which are accounted towards its run limit
+// (unlike Stats.Runs, which includes failed executions).
+//
+// It is safe to call while the instance is running.
+func (i *Instance) SuccessfulRuns() uint64 {
+	return i.runs.Load()
+}
+
+// ConsecutiveFailures returns the number of failed executions
+// of an instance accounted towards its restart limit
+// (see Stats.ConsecutiveFailures, unlike Stats.FailedRuns,
+// which is the total number of failed executions).
+//
+// It is safe to call while the instance is running.
+func (i *Instance) ConsecutiveFailures() uint64 {
+	return i.failedRuns.Load()
+}
+
+// account updates the statistics of an instance
+// after an execution of its runnable, which started at the provided time.
+func (i *Instance) account(err error, started time.Time, elapsed time.Duration) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.stats.Runs++
+	i.stats.LastDuration = elapsed
+	i.stats.RunTime += elapsed
+	if err != nil {
+		i.stats.FailedRuns++
+		i.stats.LastError = err
+	} else {
+		i.stats.LastSuccess = started.Add(elapsed)
+	}
+	i.stats.ConsecutiveFailures = i.failedRuns.Load()
+	i.stats.ConsecutiveTimeouts = i.timeouts.Load()
+
+	if i.history != nil {
+		i.history.add(RunRecord{
+			Attempt:   i.stats.Runs,
+			StartedAt: started,
+			Duration:  elapsed,
+			Err:       err,
+		})
+	}
+}
+
+// schedule updates the state of an instance,
+// along with the time of its next execution (if any),
+// which is due after the provided delay, and returns it.
+func (i *Instance) schedule(state State, after time.Duration) time.Time {
+	i.mu.Lock()
+
+	now := i.options().clock().Now()
+	tr := StateTransition{From: i.stats.State, To: state, At: now}
+
+	i.stats.elapse(i.since, now)
+	i.since = now
+	i.stats.State = state
+	if state.Final() {
+		i.stats.Uptime = now.Sub(i.stats.StartedAt)
+	}
+	switch state {
+	case StateIdle, StateBackingOff, StateWaitingPeriod:
+		i.stats.NextRun = time.Time{}
+		if after != untimed {
+			i.stats.NextRun = now.Add(after)
+		}
+	default:
+		i.stats.NextRun = time.Time{}
+	}
+
+	next := i.stats.NextRun
+
+	watchers := i.watchers
+	if state.Final() {
+		i.watchers = nil
+	}
+	i.mu.Unlock()
+
+	// Notify outside the critical section,
+	// since delivery may block.
+	if tr.From != tr.To {
+		i.notify(tr, watchers)
+	}
+	return next
+}
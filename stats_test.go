@@ -0,0 +1,178 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testStats(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"cumulative time": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			inst := New(func(context.Context) error {
+				calls++
+				time.Sleep(testTimeDelta)
+				if calls == 1 {
+					return testError(calls)
+				}
+				return nil
+			},
+				InitialDelay(testTimeDelta),
+				Recur(true),
+				Period(testTimeDelta),
+				RunLimit(2),
+				Restart(true),
+				RestartLimit(0, ConstantBackoff(testTimeDelta)),
+			)
+			waitErrors(inst.Run(context.TODO()))
+
+			stats := inst.Stats()
+			as.GreaterOrEqual(stats.RunTime, 3*testTimeDelta)
+			as.Less(stats.RunTime, 4*testTimeDelta)
+			as.GreaterOrEqual(stats.BackoffTime, testTimeDelta)
+			as.Less(stats.BackoffTime, 2*testTimeDelta)
+			// The initial delay and the period.
+			as.GreaterOrEqual(stats.WaitTime, 2*testTimeDelta)
+			as.Less(stats.WaitTime, 3*testTimeDelta)
+			as.GreaterOrEqual(stats.Uptime,
+				stats.RunTime+stats.BackoffTime+stats.WaitTime)
+
+			time.Sleep(testTimeDelta / 2)
+			as.Equal(stats, inst.Stats())
+		},
+		"cumulative time while running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, Restart(true), RestartLimit(0, ConstantBackoff(time.Hour)))
+			errCh := inst.Run(context.TODO())
+			as.Equal(testError(1), <-errCh)
+
+			time.Sleep(testTimeDelta)
+			stats := inst.Stats()
+			as.Equal(StateBackingOff, stats.State)
+			as.GreaterOrEqual(stats.BackoffTime, testTimeDelta)
+			as.GreaterOrEqual(stats.Uptime, stats.BackoffTime)
+			as.Less(stats.BackoffTime, inst.Stats().BackoffTime)
+
+			inst.Stop()
+			as.Empty(waitErrors(errCh))
+		},
+		"idle instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(nil)
+
+			as.Equal(Stats{}, inst.Stats())
+		},
+		"terminated instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			inst := New(func(context.Context) error {
+				calls++
+				if calls%2 == 1 {
+					return testError(calls)
+				}
+				return nil
+			},
+				Recur(true),
+				RunLimit(2),
+				Restart(true),
+				RestartLimit(0, nil),
+			)
+			waitErrors(inst.Run(context.TODO()))
+
+			stats := inst.Stats()
+			as.Equal(uint64(4), stats.Runs)
+			as.Equal(uint64(2), stats.FailedRuns)
+			as.Equal(uint64(0), stats.ConsecutiveFailures)
+			as.Equal(testError(3), stats.LastError)
+			as.True(stats.NextRun.IsZero())
+			as.Equal(StateTerminated, stats.State)
+			as.Equal(uint64(2), inst.SuccessfulRuns())
+			as.Zero(inst.ConsecutiveFailures())
+		},
+		"counters while running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, Restart(true), RestartLimit(0, nil), Concurrency(2),
+				OnError(func(error) {}))
+			ctx, cancel := context.WithCancel(context.TODO())
+			errCh := inst.Run(ctx)
+
+			// Read concurrently with the executions of the instance.
+			as.Eventually(func() bool {
+				return inst.ConsecutiveFailures() >= 10
+			}, time.Second, time.Millisecond)
+			cancel()
+			waitErrors(errCh)
+			as.Zero(inst.SuccessfulRuns())
+			stats := inst.Stats()
+			as.Equal(stats.ConsecutiveFailures, inst.ConsecutiveFailures())
+			as.Equal(stats.FailedRuns, inst.ConsecutiveFailures())
+		},
+		"panicked instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				<-time.After(testTimeDelta)
+				panic("panic message")
+			}, Recover(true))
+			waitErrors(inst.Run(context.TODO()))
+
+			stats := inst.Stats()
+			as.Equal(uint64(1), stats.Runs)
+			as.Equal(uint64(1), stats.FailedRuns)
+			as.Equal(RunnablePanic{"panic message"}, stats.LastError)
+			as.GreaterOrEqual(stats.LastDuration, testTimeDelta)
+			as.Equal(StateTerminated, stats.State)
+		},
+		"statistics during execution": func(t *testing.T) {
+			as := newAssertions(t)
+
+			period := time.Hour
+			calls := 0
+			inst := New(func(context.Context) error {
+				calls++
+				if calls == 1 {
+					return testError(1)
+				}
+				return nil
+			},
+				Recur(true),
+				Period(period),
+				Restart(true),
+				RestartLimit(0, ConstantBackoff(period)),
+			)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			evCh := inst.Events(ctx)
+
+			<-evCh // RunStarted
+			as.Equal(StateRunning, inst.Stats().State)
+
+			<-evCh // RunFailed
+			<-evCh // BackoffStarted
+			stats := inst.Stats()
+			as.Equal(StateBackingOff, stats.State)
+			as.Equal(uint64(1), stats.ConsecutiveFailures)
+			as.WithinDuration(time.Now().Add(period), stats.NextRun, testTimeDelta)
+
+			cancel()
+			waitEvents(evCh)
+			as.Equal(StateTerminated, inst.Stats().State)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
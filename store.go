@@ -0,0 +1,230 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// RunState represents the persisted execution state of an instance
+// (see WithStore).
+type RunState struct {
+	// Runs is the number of successful executions of the runnable,
+	// which are accounted towards its run limit.
+	Runs uint64 `json:"runs"`
+	// FailedRuns is the total number of failed executions of the runnable.
+	FailedRuns uint64 `json:"failed_runs"`
+	// ConsecutiveFailures is the number of failed executions
+	// accounted towards the restart limit of the runnable.
+	ConsecutiveFailures uint64 `json:"consecutive_failures"`
+	// LastRun is the time the latest execution started.
+	LastRun time.Time `json:"last_run"`
+	// NextRun is the time the next execution is scheduled for,
+	// or zero if none is scheduled.
+	NextRun time.Time `json:"next_run"`
+}
+
+// Store persists the execution state of an instance (see WithStore).
+//
+// Its methods may be called concurrently, for instances executing
+// copies of their runnable concurrently (see Concurrency).
+type Store interface {
+	// Load returns the saved state, or false if there is none.
+	Load(ctx context.Context) (RunState, bool, error)
+	// Save saves the provided state, replacing any saved one.
+	Save(ctx context.Context, state RunState) error
+}
+
+// StoreError wraps the error of a store failing to load or save
+// the state of an instance (see WithStore).
+type StoreError struct {
+	// Op is the failed operation, either "load" or "save".
+	Op string
+	// Err is the error returned by the store.
+	Err error
+}
+
+// Error returns the error of the store, annotated with its operation.
+func (e StoreError) Error() string {
+	return fmt.Sprintf("store %s failed: %v", e.Op, e.Err)
+}
+
+// Unwrap returns the error of the store.
+func (e StoreError) Unwrap() error {
+	return e.Err
+}
+
+// WithStore persists the execution state of an instance
+// to the provided store (default: nil, not persisted),
+// so that recurring or scheduled runnables keep their place
+// across process restarts.
+//
+// The state is loaded once the instance starts running,
+// restoring its counters and the time of its next execution
+// (see CatchUp, in case it has elapsed),
+// and saved after each execution.
+// If the state cannot be loaded or saved, the instance terminates
+// with a StoreError as reason.
+func WithStore(s Store) Option {
+	return func(o *options) *options {
+		o.store = s
+		return o
+	}
+}
+
+// persistence returns the store of a runnable, if any.
+func (o *options) persistence() Store {
+	if o == nil {
+		return nil
+	}
+	return o.store
+}
+
+// restore restores the execution state of an instance
+// from its store (if any).
+func (i *Instance) restore(ctx context.Context) error {
+	store := i.options().persistence()
+	if store == nil {
+		return nil
+	}
+
+	state, ok, err := store.Load(ctx)
+	if err != nil {
+		return StoreError{Op: "load", Err: err}
+	}
+	if !ok {
+		return nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.runs.Store(state.Runs)
+	i.failedRuns.Store(state.ConsecutiveFailures)
+	i.stats.Runs = state.Runs + state.FailedRuns
+	i.stats.FailedRuns = state.FailedRuns
+	i.stats.ConsecutiveFailures = state.ConsecutiveFailures
+	i.restored = state.NextRun
+	return nil
+}
+
+// restoredRun returns the time of the next execution of an instance
+// restored from its store (or zero, if none),
+// and whether its run limit had already been reached.
+func (i *Instance) restoredRun() (next time.Time, reached bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.options() != nil {
+		limit := i.options().constrained.runLimit
+		reached = limit != 0 && i.runs.Load() >= limit
+	}
+	return i.restored, reached
+}
+
+// persist saves the execution state of an instance to its store (if any),
+// provided with the time its latest execution started,
+// even if its context is done.
+func (i *Instance) persist(ctx context.Context, started time.Time) error {
+	store := i.options().persistence()
+	if store == nil {
+		return nil
+	}
+
+	i.mu.Lock()
+	state := RunState{
+		Runs:                i.runs.Load(),
+		FailedRuns:          i.stats.FailedRuns,
+		ConsecutiveFailures: i.failedRuns.Load(),
+		LastRun:             started,
+		NextRun:             i.stats.NextRun,
+	}
+	i.mu.Unlock()
+
+	if err := store.Save(context.WithoutCancel(ctx), state); err != nil {
+		return StoreError{Op: "save", Err: err}
+	}
+	return nil
+}
+
+// MemoryStore is a store keeping the state of an instance in memory,
+// which survives resetting or recreating the instance,
+// but not process restarts.
+//
+// The zero value is ready to use.
+type MemoryStore struct {
+	mu    sync.Mutex
+	state RunState
+	saved bool
+}
+
+// Load returns the saved state, or false if there is none.
+func (s *MemoryStore) Load(context.Context) (RunState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state, s.saved, nil
+}
+
+// Save saves the provided state, replacing any saved one.
+func (s *MemoryStore) Save(_ context.Context, state RunState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state, s.saved = state, true
+	return nil
+}
+
+// FileStore is a store keeping the state of an instance
+// in a JSON-encoded file, which is replaced atomically when saved.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore creates a new store keeping the state of an instance
+// in the file at the provided path, whose directory should exist.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load returns the state saved in the file of the store,
+// or false if the file does not exist.
+func (s *FileStore) Load(context.Context) (RunState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var state RunState
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, false, nil
+	}
+	if err != nil {
+		return state, false, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, false, err
+	}
+	return state, true, nil
+}
+
+// Save saves the provided state to the file of the store,
+// by writing it to a temporary file next to it,
+// which is then renamed over it.
+func (s *FileStore) Save(_ context.Context, state RunState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Encoding the state cannot fail.
+	data, _ := json.Marshal(state)
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,193 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// failingStore is a store failing to load or save state.
+type failingStore struct {
+	MemoryStore
+	loadErr, saveErr error
+}
+
+func (s *failingStore) Load(ctx context.Context) (RunState, bool, error) {
+	if s.loadErr != nil {
+		return RunState{}, false, s.loadErr
+	}
+	return s.MemoryStore.Load(ctx)
+}
+
+func (s *failingStore) Save(ctx context.Context, state RunState) error {
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	return s.MemoryStore.Save(ctx, state)
+}
+
+func testStore(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"state survives restarts": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var store MemoryStore
+			runs := 0
+			r := func(context.Context) error {
+				if runs++; runs == 1 {
+					return testError(runs)
+				}
+				return nil
+			}
+			opts := []Option{
+				Recur(true), Restart(true), RestartLimit(0, nil), WithStore(&store),
+			}
+
+			inst := New(r, append(opts, RunLimit(2))...)
+			as.Equal([]error{testError(1)}, waitErrors(inst.Run(context.TODO())))
+			state, ok, err := store.Load(context.TODO())
+			as.NoError(err)
+			as.True(ok)
+			as.Equal(uint64(2), state.Runs)
+			as.Equal(uint64(1), state.FailedRuns)
+			as.Zero(state.ConsecutiveFailures)
+			as.False(state.LastRun.IsZero())
+			as.True(state.NextRun.IsZero())
+
+			inst = New(r, append(opts, RunLimit(3))...)
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(4, runs)
+			as.Equal(uint64(3), inst.SuccessfulRuns())
+			as.Equal(uint64(4), inst.Stats().Runs)
+			as.Equal(uint64(1), inst.Stats().FailedRuns)
+			as.Equal(RunLimitReached, inst.TerminationReason())
+
+			// The run limit was reached before restarting.
+			inst = New(r, append(opts, RunLimit(3))...)
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(4, runs)
+			as.Equal(RunLimitReached, inst.TerminationReason())
+		},
+		"next execution is resumed": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var store MemoryStore
+			next := time.Now().Add(time.Hour)
+			as.NoError(store.Save(context.TODO(), RunState{Runs: 1, NextRun: next}))
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, Recur(true), WithStore(&store))
+
+			errCh := inst.Run(context.TODO())
+			as.Eventually(func() bool {
+				return !inst.Stats().NextRun.IsZero()
+			}, time.Second, time.Millisecond)
+			as.WithinDuration(next, inst.Stats().NextRun, testTimeDelta)
+			inst.Stop()
+			as.Empty(waitErrors(errCh))
+			as.Zero(runs)
+			as.Equal(uint64(1), inst.SuccessfulRuns())
+		},
+		"elapsed execution is due": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var store MemoryStore
+			as.NoError(store.Save(context.TODO(), RunState{
+				ConsecutiveFailures: 1,
+				NextRun:             time.Now().Add(-time.Hour),
+			}))
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return testError(runs)
+			}, Restart(true), RestartLimit(2, nil), WithStore(&store))
+
+			as.Equal([]error{testError(1)}, waitErrors(inst.Run(context.TODO())))
+			as.Equal(1, runs)
+			as.Equal(RestartLimitExceeded, inst.TerminationReason())
+
+			state, _, _ := store.Load(context.TODO())
+			as.Equal(uint64(2), state.ConsecutiveFailures)
+			as.Equal(uint64(1), state.FailedRuns)
+		},
+		"failed load": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return nil
+			}, WithStore(&failingStore{loadErr: testError(-1)}))
+
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.Equal([]error{StoreError{Op: "load", Err: testError(-1)}}, errs)
+			as.ErrorIs(errs[0], testError(-1))
+			as.EqualError(errs[0], "store load failed: "+testError(-1).Error())
+			as.Zero(runs)
+			as.Equal(StoreFailed, inst.TerminationReason())
+		},
+		"failed save": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for _, opts := range [][]Option{
+				{Recur(true)},
+				{},
+			} {
+				runs := 0
+				inst := New(func(context.Context) error {
+					runs++
+					return nil
+				}, append(opts, WithStore(&failingStore{saveErr: testError(-1)}))...)
+
+				as.Equal([]error{StoreError{Op: "save", Err: testError(-1)}},
+					waitErrors(inst.Run(context.TODO())))
+				as.Equal(1, runs)
+				as.Equal(StoreFailed, inst.TerminationReason())
+			}
+		},
+		"file store": func(t *testing.T) {
+			as := newAssertions(t)
+
+			dir := t.TempDir()
+			store := NewFileStore(filepath.Join(dir, "state.json"))
+
+			_, ok, err := store.Load(context.TODO())
+			as.NoError(err)
+			as.False(ok)
+
+			saved := RunState{
+				Runs:    3,
+				LastRun: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				NextRun: time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+			}
+			as.NoError(store.Save(context.TODO(), saved))
+			state, ok, err := store.Load(context.TODO())
+			as.NoError(err)
+			as.True(ok)
+			as.Equal(saved, state)
+
+			as.NoError(os.WriteFile(filepath.Join(dir, "state.json"), []byte("{"), 0o644))
+			_, _, err = store.Load(context.TODO())
+			as.Error(err)
+
+			store = NewFileStore(dir)
+			_, _, err = store.Load(context.TODO())
+			as.Error(err)
+			as.False(errors.Is(err, os.ErrNotExist))
+
+			store = NewFileStore(filepath.Join(dir, "missing", "state.json"))
+			as.Error(store.Save(context.TODO(), saved))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,83 @@
+package run
+
+// subscription represents a subscriber to the events
+// or the errors of an instance.
+type subscription struct {
+	events chan Event
+	errs   chan error
+}
+
+// Subscribe returns a channel where the events of an instance
+// are propagated (as by Events), which is closed once it terminates,
+// so that multiple consumers can observe the same instance
+// (e.g. a logger and a supervisor) independently of the channel
+// returned by Run, Events or TryRun.
+// Each call creates a new subscription,
+// with events preceding it not being propagated.
+//
+// The channel buffer size is controlled by WithChanBuffer,
+// and similarly to the error channel, delivery of an event
+// blocks the execution of the instance until it is received.
+// In case the runnable panics without recovery, the channel is not closed.
+func (i *Instance) Subscribe() <-chan Event {
+	ch := make(chan Event, i.options().chanSize())
+	i.subscribe(subscription{events: ch})
+	return ch
+}
+
+// SubscribeErrors returns a channel where the errors of an instance
+// are propagated (as by Run), which is closed once it terminates,
+// similarly to Subscribe.
+func (i *Instance) SubscribeErrors() <-chan error {
+	ch := make(chan error, i.options().chanSize())
+	i.subscribe(subscription{errs: ch})
+	return ch
+}
+
+// subscribe adds a subscription to an instance,
+// or closes it if the instance has already terminated.
+func (i *Instance) subscribe(sub subscription) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.ended != 0 {
+		sub.close()
+		return
+	}
+	i.subscribers = append(i.subscribers, sub)
+}
+
+// publish propagates an event of an instance to its subscribers,
+// closing their channels once it terminates.
+func (i *Instance) publish(ev Event) {
+	_, terminated := ev.(Terminated)
+
+	i.mu.Lock()
+	subscribers := i.subscribers
+	if terminated {
+		i.subscribers = nil
+	}
+	i.mu.Unlock()
+
+	err := eventError(ev)
+	for _, sub := range subscribers {
+		switch {
+		case sub.events != nil:
+			sub.events <- ev
+		case err != nil:
+			sub.errs <- err
+		}
+		if terminated {
+			sub.close()
+		}
+	}
+}
+
+// close closes the channel of a subscription.
+func (s subscription) close() {
+	if s.events != nil {
+		close(s.events)
+		return
+	}
+	close(s.errs)
+}
@@ -0,0 +1,97 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func testSubscribe(t *testing.T) {
+	// kinds returns the types of the events received from a channel.
+	kinds := func(evCh <-chan Event) []string {
+		var kinds []string
+		for ev := range evCh {
+			kinds = append(kinds, fmt.Sprintf("%T", ev))
+		}
+		return kinds
+	}
+	flaky := func() Runnable {
+		runs := 0
+		return func(context.Context) error {
+			if runs++; runs == 1 {
+				return testError(runs)
+			}
+			return nil
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"multiple subscribers": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(flaky(), Restart(true))
+			first, second := inst.Subscribe(), inst.Subscribe()
+			errSub := inst.SubscribeErrors()
+
+			results := make(chan []string, 2)
+			for _, ch := range []<-chan Event{first, second} {
+				go func(ch <-chan Event) {
+					results <- kinds(ch)
+				}(ch)
+			}
+			errs := make(chan []error)
+			go func() {
+				errs <- waitErrors(errSub)
+			}()
+
+			as.Equal([]error{testError(1)}, waitErrors(inst.Run(context.TODO())))
+			expected := []string{
+				"run.RunStarted", "run.RunFailed", "run.BackoffStarted",
+				"run.RunStarted", "run.RunSucceeded", "run.Terminated",
+			}
+			as.Equal(expected, <-results)
+			as.Equal(expected, <-results)
+			as.Equal([]error{testError(1)}, <-errs)
+		},
+		"with Events": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(flaky(), Restart(true))
+			sub := inst.Subscribe()
+			result := make(chan []string)
+			go func() {
+				result <- kinds(sub)
+			}()
+
+			as.Equal(kinds(inst.Events(context.TODO())), <-result)
+		},
+		"after termination": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(flaky())
+			as.Equal([]error{testError(1)}, waitErrors(inst.Run(context.TODO())))
+
+			as.Empty(kinds(inst.Subscribe()))
+			as.Empty(waitErrors(inst.SubscribeErrors()))
+		},
+		"after reset": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(flaky())
+			as.Equal([]error{testError(1)}, waitErrors(inst.Run(context.TODO())))
+			as.NoError(inst.Reset())
+
+			sub := inst.SubscribeErrors()
+			errs := make(chan []error)
+			go func() {
+				errs <- waitErrors(sub)
+			}()
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Empty(<-errs)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,332 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrRestartIntensity is propagated by a supervised group
+// when its members are restarted more often than its restart intensity allows,
+// in which case all of them are stopped.
+var ErrRestartIntensity = errors.New("group restart intensity exceeded")
+
+// SupervisorStrategy determines which members of a group
+// are restarted when one of them fails.
+//
+// A member fails when it terminates with an error
+// (the error of its last failed execution, or its termination reason),
+// unless the group is stopped or its context is cancelled.
+// Members that complete successfully are not restarted.
+type SupervisorStrategy int
+
+const (
+	// Unsupervised does not restart any members.
+	Unsupervised SupervisorStrategy = iota
+	// OneForOne restarts only the failed member.
+	OneForOne
+	// OneForAll stops all running members
+	// and restarts them along with the failed one.
+	OneForAll
+	// RestForOne stops the running members added after the failed one,
+	// and restarts them along with it.
+	RestForOne
+)
+
+// supervisionOptions defines the restart options of a group.
+type supervisionOptions struct {
+	strategy SupervisorStrategy
+	// intensity is the maximum number of restarts allowed within period,
+	// with 0 representing no limit.
+	intensity uint
+	period    time.Duration
+}
+
+// Supervise sets the supervisor strategy of a group (default: Unsupervised).
+//
+// Restarted members are stopped and restarted in the order they were added,
+// once all of them have terminated.
+func Supervise(strategy SupervisorStrategy) GroupOption {
+	return func(o *groupOptions) *groupOptions {
+		o.supervision.strategy = strategy
+		return o
+	}
+}
+
+// RestartIntensity limits the restarts of a supervised group to max
+// within the provided period (default: 0, no limit).
+// Exceeding the limit stops all members of the group,
+// propagating ErrRestartIntensity.
+//
+// Each failure counts as one restart, regardless of the strategy,
+// timed by the clock of the failed member (see WithClock).
+func RestartIntensity(max uint, period time.Duration) GroupOption {
+	return func(o *groupOptions) *groupOptions {
+		o.supervision.intensity = max
+		o.supervision.period = period
+		return o
+	}
+}
+
+// supervisor runs the members of a group,
+// restarting them according to its options.
+//
+// Apart from the channels, its state is guarded by the lock of the group.
+type supervisor struct {
+	// ctx is the context of the group, while members run under memberCtx,
+	// which is detached from its cancellation if the group is ordered.
+	ctx       context.Context
+	memberCtx context.Context
+	g         *Group
+	opts      supervisionOptions
+	errCh     chan error
+	// exits receives the members whose instance terminated,
+	// along with their final error.
+	exits chan exit
+
+	// active is the number of members whose instance is running
+	// (or is about to, in case of sequential start).
+	active int
+	// pending holds the members to be restarted
+	// once all of them have terminated.
+	pending map[*member]bool
+	// restarts holds the times of recent restarts,
+	// with exhausted indicating whether the intensity has been exceeded.
+	restarts  []time.Time
+	exhausted bool
+	// failures is the number of members that failed without being restarted.
+	failures uint
+	// rolling holds the members being restarted by a rolling restart.
+	rolling map[*member]*rollout
+	// quorate indicates whether the quorum of the group was reached
+	// as of the latest assessment.
+	quorate bool
+
+	// stopping is closed once the group is stopped,
+	// while finished is closed once it terminates.
+	stopping chan struct{}
+	finished chan struct{}
+}
+
+// exit represents the termination of the instance of a member.
+type exit struct {
+	m   *member
+	err error
+}
+
+func newSupervisor(ctx context.Context, g *Group) *supervisor {
+	memberCtx := ctx
+	if g.opts.ordered {
+		memberCtx = context.WithoutCancel(ctx)
+	}
+
+	return &supervisor{
+		ctx:       ctx,
+		memberCtx: memberCtx,
+		g:         g,
+		opts:      g.opts.supervision,
+		errCh:     make(chan error),
+		exits:     make(chan exit),
+		pending:   make(map[*member]bool),
+		rolling:   make(map[*member]*rollout),
+		stopping:  make(chan struct{}),
+		finished:  make(chan struct{}),
+	}
+}
+
+// launch starts the provided members in order,
+// sequentially if the group is ordered.
+// The lock of the group should be held.
+func (s *supervisor) launch(members []*member) {
+	if !s.g.opts.ordered {
+		for _, m := range members {
+			s.start(m)
+		}
+		return
+	}
+
+	// Account for the members in advance,
+	// since they are started asynchronously.
+	s.active += len(members)
+	go s.sequence(members)
+}
+
+// start runs the instance of a member in a goroutine, propagating its errors,
+// and indicates whether it started (since it may have already been run).
+// The lock of the group should be held.
+func (s *supervisor) start(m *member) bool {
+	evCh := m.inst.Events(s.memberCtx)
+	if evCh == nil {
+		// Instance has already been run.
+		return false
+	}
+
+	done := make(chan struct{})
+	m.running, m.done = true, done
+	s.active++
+	// Starting a member never loses the quorum.
+	s.assess()
+	go func() {
+		var out outcome
+		for ev := range evCh {
+			out.observe(ev)
+			if err := eventError(ev); err != nil {
+				s.errCh <- MemberError{Member: m.name, Err: err}
+			}
+		}
+		close(done)
+		s.exits <- exit{m: m, err: out.err}
+	}()
+
+	return true
+}
+
+// supervise handles the termination of members until the group is stopped
+// (or its context is done) and all of them terminate,
+// and closes the error channel.
+func (s *supervisor) supervise() {
+	defer close(s.errCh)
+	defer close(s.finished)
+
+	done, stopping := s.ctx.Done(), s.stopping
+	for s.remaining() {
+		select {
+		case ex := <-s.exits:
+			s.exit(ex)
+		case <-done:
+			done = nil
+		case <-stopping:
+			stopping = nil
+		}
+	}
+}
+
+// exit handles the termination of the instance of a member.
+func (s *supervisor) exit(ex exit) {
+	s.g.mu.Lock()
+	s.active--
+	ex.m.running = false
+
+	exhausted, rolling := s.exhausted, s.rolling[ex.m] != nil
+	failed := ex.err != nil && !ex.m.removed && !rolling && !s.pending[ex.m] &&
+		!s.g.stopped && s.ctx.Err() == nil
+	if failed && s.restartable() {
+		s.fail(ex.m)
+	}
+	breached := failed && !s.pending[ex.m] && s.breach()
+	if rolling {
+		s.relaunch(ex.m)
+	}
+	s.restart()
+	exhausted = s.exhausted && !exhausted
+	lost := s.assess()
+	s.g.mu.Unlock()
+
+	// Propagate outside the critical section, since delivery may block.
+	if exhausted {
+		s.errCh <- ErrRestartIntensity
+	}
+	if breached {
+		s.errCh <- ErrFailureThreshold
+	}
+	if lost {
+		s.errCh <- ErrQuorumLost
+	}
+}
+
+// remaining indicates whether any members have not terminated yet,
+// or more can be added, since the group is neither stopped
+// nor is its context done.
+func (s *supervisor) remaining() bool {
+	s.g.mu.Lock()
+	defer s.g.mu.Unlock()
+
+	return s.active > 0 || s.open()
+}
+
+// open indicates whether members can be added to the group,
+// since it is neither stopped nor is its context done.
+// The lock of the group should be held.
+func (s *supervisor) open() bool {
+	return !s.g.stopped && s.ctx.Err() == nil
+}
+
+// restartable indicates whether failed members can be restarted.
+func (s *supervisor) restartable() bool {
+	return s.opts.strategy != Unsupervised &&
+		!s.exhausted && !s.g.stopped && s.ctx.Err() == nil
+}
+
+// fail marks the members to be restarted after the failure of a member,
+// according to the supervisor strategy, stopping those running.
+// If the restart intensity is exceeded, all members are stopped instead.
+func (s *supervisor) fail(failed *member) {
+	if !s.allow(failed.inst.options().clock().Now()) {
+		s.exhausted = true
+		s.pending = make(map[*member]bool)
+		for _, m := range s.g.members {
+			m.inst.Stop()
+		}
+		return
+	}
+
+	after := false
+	for _, m := range s.g.members {
+		switch {
+		case m == failed:
+			s.pending[m] = true
+			after = true
+		case !m.running:
+		case s.opts.strategy == OneForAll,
+			s.opts.strategy == RestForOne && after:
+			s.pending[m] = true
+			m.inst.Stop()
+		}
+	}
+}
+
+// allow records a restart at the provided time,
+// indicating whether it is allowed by the restart intensity.
+func (s *supervisor) allow(now time.Time) bool {
+	if s.opts.intensity == 0 {
+		return true
+	}
+
+	recent := s.restarts[:0]
+	for _, at := range s.restarts {
+		if now.Sub(at) < s.opts.period {
+			recent = append(recent, at)
+		}
+	}
+	s.restarts = recent
+
+	if uint(len(s.restarts)) >= s.opts.intensity {
+		return false
+	}
+	s.restarts = append(s.restarts, now)
+	return true
+}
+
+// restart restarts the pending members in order,
+// once all of them have terminated.
+func (s *supervisor) restart() {
+	for m := range s.pending {
+		if m.running {
+			return
+		}
+	}
+	pending := s.pending
+	s.pending = make(map[*member]bool)
+	if !s.restartable() {
+		return
+	}
+
+	restarted := make([]*member, 0, len(pending))
+	for _, m := range s.g.members {
+		if pending[m] {
+			m.inst = m.inst.clone()
+			restarted = append(restarted, m)
+		}
+	}
+	s.launch(restarted)
+}
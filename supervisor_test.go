@@ -0,0 +1,214 @@
+package run
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testSupervisor(t *testing.T) {
+	// blocking returns a runnable counting its executions,
+	// which block until cancelled.
+	blocking := func(calls *int32) Runnable {
+		return func(ctx context.Context) error {
+			atomic.AddInt32(calls, 1)
+			<-ctx.Done()
+			return nil
+		}
+	}
+	// failing returns a runnable counting its executions,
+	// the first n of which fail once ready is true.
+	failing := func(calls *int32, n int32, ready func() bool) Runnable {
+		return func(context.Context) error {
+			for !ready() {
+				time.Sleep(time.Millisecond)
+			}
+			if atomic.AddInt32(calls, 1) <= n {
+				return testError(n)
+			}
+			return nil
+		}
+	}
+	// started returns a function indicating whether
+	// the provided number of executions have started.
+	started := func(calls *int32, n int32) func() bool {
+		return func() bool {
+			return atomic.LoadInt32(calls) >= n
+		}
+	}
+	// stopAfter stops a group once the condition is satisfied,
+	// and returns the errors it propagated.
+	stopAfter := func(t *testing.T, g *Group, errCh <-chan error,
+		cond func() bool) []error {
+
+		done := make(chan []error)
+		go func() {
+			done <- waitErrors(errCh)
+		}()
+
+		newAssertions(t).Eventually(cond, time.Second, time.Millisecond)
+		g.Stop()
+		return <-done
+	}
+
+	subtests := map[string]func(*testing.T){
+		"options": func(t *testing.T) {
+			as := newAssertions(t)
+
+			g := NewGroup(Supervise(RestForOne), RestartIntensity(3, time.Minute))
+			as.Equal(&groupOptions{
+				supervision: supervisionOptions{
+					strategy:  RestForOne,
+					intensity: 3,
+					period:    time.Minute,
+				},
+			}, g.opts)
+			as.Equal(&groupOptions{}, NewGroup().opts)
+		},
+		"one for one": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var aCalls, bCalls int32
+			g := NewGroup(Supervise(OneForOne))
+			as.NoError(g.Add("a", failing(&aCalls, 2, started(&bCalls, 1))))
+			as.NoError(g.Add("b", blocking(&bCalls)))
+
+			errs := stopAfter(t, g, g.Run(context.TODO()), started(&aCalls, 3))
+			as.Equal([]error{
+				MemberError{Member: "a", Err: testError(int32(2))},
+				MemberError{Member: "a", Err: testError(int32(2))},
+			}, errs)
+			as.Equal(int32(1), atomic.LoadInt32(&bCalls))
+		},
+		"one for all": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var aCalls, bCalls int32
+			g := NewGroup(Supervise(OneForAll))
+			as.NoError(g.Add("a", failing(&aCalls, 1, started(&bCalls, 1))))
+			as.NoError(g.Add("b", blocking(&bCalls)))
+
+			errs := stopAfter(t, g, g.Run(context.TODO()), started(&bCalls, 2))
+			as.Equal([]error{
+				MemberError{Member: "a", Err: testError(int32(1))},
+			}, errs)
+			as.Equal(int32(2), atomic.LoadInt32(&aCalls))
+		},
+		"rest for one": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var aCalls, bCalls, cCalls int32
+			ready := func() bool {
+				return started(&aCalls, 1)() && started(&cCalls, 1)()
+			}
+			g := NewGroup(Supervise(RestForOne))
+			as.NoError(g.Add("a", blocking(&aCalls)))
+			as.NoError(g.Add("b", failing(&bCalls, 1, ready)))
+			as.NoError(g.Add("c", blocking(&cCalls)))
+
+			errs := stopAfter(t, g, g.Run(context.TODO()), started(&cCalls, 2))
+			as.Equal([]error{
+				MemberError{Member: "b", Err: testError(int32(1))},
+			}, errs)
+			as.Equal(int32(1), atomic.LoadInt32(&aCalls))
+			as.Equal(int32(2), atomic.LoadInt32(&bCalls))
+		},
+		"restart intensity": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var aCalls, bCalls int32
+			g := NewGroup(Supervise(OneForOne), RestartIntensity(2, time.Hour))
+			as.NoError(g.Add("a", failing(&aCalls, 10, started(&bCalls, 1))))
+			as.NoError(g.Add("b", blocking(&bCalls)))
+
+			as.Equal([]error{
+				MemberError{Member: "a", Err: testError(int32(10))},
+				MemberError{Member: "a", Err: testError(int32(10))},
+				MemberError{Member: "a", Err: testError(int32(10))},
+				ErrRestartIntensity,
+			}, waitGroup(g, context.TODO()))
+			as.Equal(int32(3), atomic.LoadInt32(&aCalls))
+			as.Equal(int32(1), atomic.LoadInt32(&bCalls))
+		},
+		"restart intensity clock": func(t *testing.T) {
+			as := newAssertions(t)
+
+			// Restarts are an hour apart according to the clock of the member.
+			var calls int32
+			g := NewGroup(Supervise(OneForOne), RestartIntensity(2, time.Hour))
+			as.NoError(g.Add("a", failing(&calls, 3, func() bool { return true }),
+				WithClock(&steppingClock{step: time.Hour})))
+
+			as.Equal([]error{
+				MemberError{Member: "a", Err: testError(int32(3))},
+				MemberError{Member: "a", Err: testError(int32(3))},
+				MemberError{Member: "a", Err: testError(int32(3))},
+			}, waitGroup(g, context.TODO()))
+			as.Equal(int32(4), atomic.LoadInt32(&calls))
+		},
+		"restart intensity period": func(t *testing.T) {
+			as := newAssertions(t)
+
+			s := newSupervisor(context.TODO(),
+				NewGroup(RestartIntensity(2, time.Minute)))
+			now := time.Now()
+
+			as.True(s.allow(now))
+			as.True(s.allow(now.Add(30 * time.Second)))
+			as.False(s.allow(now.Add(45 * time.Second)))
+			as.True(s.allow(now.Add(time.Minute)))
+			as.Len(s.restarts, 2)
+		},
+		"no restarts after cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+
+			var calls int32
+			g := NewGroup(Supervise(OneForAll))
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				<-ctx.Done()
+				return ctx.Err()
+			}))
+
+			as.Equal([]error{
+				MemberError{Member: "a", Err: context.DeadlineExceeded},
+			}, waitGroup(g, ctx))
+			as.Equal(int32(1), atomic.LoadInt32(&calls))
+		},
+		"no restarts after stopping": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			g := NewGroup(Supervise(OneForOne))
+			as.NoError(g.Add("a", func(ctx context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				<-ctx.Done()
+				return ctx.Err()
+			}))
+
+			errs := stopAfter(t, g, g.Run(context.TODO()), started(&calls, 1))
+			as.Equal([]error{
+				MemberError{Member: "a", Err: context.Canceled},
+			}, errs)
+			as.Equal(int32(1), atomic.LoadInt32(&calls))
+		},
+		"unsupervised members are not restarted": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var calls int32
+			g := NewGroup()
+			as.NoError(g.Add("a", failing(&calls, 1, func() bool { return true })))
+
+			as.Len(waitGroup(g, context.TODO()), 1)
+			as.Equal(int32(1), atomic.LoadInt32(&calls))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
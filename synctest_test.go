@@ -0,0 +1,80 @@
+//go:build go1.25
+
+package run
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func init() {
+	tests["synctest"] = testSynctest
+}
+
+func testSynctest(t *testing.T) {
+	// Created outside of the bubbles of the subtests.
+	budget := NewBudget(1, time.Hour)
+
+	subtests := map[string]func(*testing.T){
+		"backoff": func(t *testing.T) {
+			as := newAssertions(t)
+
+			start := time.Now()
+			runs := 0
+			err := Do(context.TODO(), func(context.Context) error {
+				if runs++; runs < 3 {
+					return testError(runs)
+				}
+				return nil
+			}, Restart(true), RestartLimit(0, ConstantBackoff(time.Hour)))
+			as.NoError(err)
+			as.Equal(3, runs)
+			as.Equal(2*time.Hour, time.Since(start))
+		},
+		"recurring": func(t *testing.T) {
+			as := newAssertions(t)
+
+			start := time.Now()
+			inst := New(func(context.Context) error {
+				return nil
+			}, Recur(true), Period(time.Minute), RunLimit(3))
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(2*time.Minute, time.Since(start))
+			as.Equal(uint64(3), inst.Stats().Runs)
+		},
+		"timeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			start := time.Now()
+			err := Do(context.TODO(), func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}, Timeout(time.Hour), HeartbeatTimeout(2*time.Hour))
+			as.ErrorIs(err, ErrRunTimeout)
+			as.Equal(time.Hour, time.Since(start))
+		},
+		"retry budget": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(context.Context) error {
+				runs++
+				return testError(runs)
+			}, Restart(true), RestartLimit(3, ConstantBackoff(time.Hour)),
+				WithRetryBudget(budget), WithChanBuffer(3))
+			evs := waitEvents(inst.Events(context.TODO()))
+			// Refilled by the backoff period in the time of the bubble.
+			as.NotContains(evs, RetryDenied{})
+			as.Equal(3, runs)
+		},
+	}
+
+	for name, test := range subtests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			synctest.Test(t, test)
+		})
+	}
+}
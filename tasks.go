@@ -0,0 +1,147 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+)
+
+// tasksKey is the context key for the goroutines started
+// on behalf of an execution.
+type tasksKey struct{}
+
+// tasks tracks the goroutines started on behalf of an execution
+// of a runnable (see Go).
+type tasks struct {
+	wg sync.WaitGroup
+
+	// mu guards the errors of the goroutines that returned,
+	// along with the value and stack trace of the first one
+	// that panicked (if any).
+	mu       sync.Mutex
+	errs     []error
+	panicked bool
+	episode  interface{}
+	stack    []byte
+
+	// site records the stack trace of panics propagated by the tracker.
+	site *panicSite
+}
+
+// Go calls the provided function in a goroutine started on behalf
+// of the execution of a runnable, provided with its context,
+// which is passed to the function.
+//
+// The execution waits for the goroutine to return before it finishes,
+// failing with its error (joined with that of the execution,
+// and those of any other goroutines), while a panic in it
+// is propagated by the execution once they have all returned,
+// subject to the panic options of the runnable (see Recover and RecoverWith).
+// Outside of executions, the goroutine is not tracked.
+func Go(ctx context.Context, fn func(ctx context.Context) error) {
+	t, ok := ctx.Value(tasksKey{}).(*tasks)
+	if !ok {
+		go func() {
+			_ = fn(ctx)
+		}()
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		panicked := true
+		defer func() {
+			if panicked {
+				t.panic(recover(), debug.Stack())
+			}
+		}()
+
+		err := fn(ctx)
+		panicked = false
+		t.done(err)
+	}()
+}
+
+// Wait waits for the goroutines started by Go on behalf of the execution
+// of a runnable, provided with its context, and returns their errors
+// joined (which are no longer propagated by the execution),
+// or propagates the first panic among them.
+// Outside of executions, it returns nil immediately.
+func Wait(ctx context.Context) error {
+	t, ok := ctx.Value(tasksKey{}).(*tasks)
+	if !ok {
+		return nil
+	}
+	return t.wait()
+}
+
+// withTasks returns a child of the provided context tracking
+// the goroutines started on behalf of an execution,
+// along with their tracker, which records the stack trace
+// of the panics it propagates at the provided site.
+func withTasks(ctx context.Context, site *panicSite) (context.Context, *tasks) {
+	t := &tasks{site: site}
+	return context.WithValue(ctx, tasksKey{}, t), t
+}
+
+// done records the error of a goroutine that returned.
+func (t *tasks) done(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err != nil {
+		t.errs = append(t.errs, err)
+	}
+}
+
+// panic records the value and stack trace of a goroutine that panicked,
+// unless another one panicked before.
+func (t *tasks) panic(episode interface{}, stack []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.panicked {
+		t.panicked, t.episode, t.stack = true, episode, stack
+	}
+}
+
+// wait waits for the tracked goroutines to return, and returns their errors,
+// or propagates the first panic among them.
+func (t *tasks) wait() error {
+	t.wg.Wait()
+
+	t.mu.Lock()
+	errs, panicked, episode, stack := t.errs, t.panicked, t.episode, t.stack
+	t.errs, t.panicked, t.episode, t.stack = nil, false, nil, nil
+	t.mu.Unlock()
+
+	if panicked {
+		t.site.record(stack)
+		panic(episode)
+	}
+	return joined(errs...)
+}
+
+// join waits for the tracked goroutines to return, and returns
+// the provided error of the execution joined with theirs,
+// or propagates the first panic among them.
+func (t *tasks) join(err error) error {
+	return joined(err, t.wait())
+}
+
+// joined joins the provided errors, returning the only non-nil one as is.
+func joined(errs ...error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	if len(nonNil) == 1 {
+		return nonNil[0]
+	}
+	return errors.Join(nonNil...)
+}
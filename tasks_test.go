@@ -0,0 +1,112 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func testTasks(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"executions wait for goroutines": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var returned int32
+			inst := New(func(ctx context.Context) error {
+				for n := 0; n < 3; n++ {
+					Go(ctx, func(ctx context.Context) error {
+						as.NoError(ctx.Err())
+						atomic.AddInt32(&returned, 1)
+						return nil
+					})
+				}
+				return nil
+			})
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal(int32(3), atomic.LoadInt32(&returned))
+		},
+		"errors are joined": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				Go(ctx, func(context.Context) error {
+					return testError(-1)
+				})
+				return testError(1)
+			})
+
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.Len(errs, 1)
+			as.ErrorIs(errs[0], testError(1))
+			as.ErrorIs(errs[0], testError(-1))
+
+			inst = New(func(ctx context.Context) error {
+				Go(ctx, func(context.Context) error {
+					return testError(-1)
+				})
+				return nil
+			})
+			as.Equal([]error{testError(-1)}, waitErrors(inst.Run(context.TODO())))
+		},
+		"panics are propagated": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				for n := 0; n < 2; n++ {
+					Go(ctx, func(context.Context) error {
+						panic("panic message")
+					})
+				}
+				return nil
+			}, Recover(true))
+
+			as.Equal([]error{RunnablePanic{"panic message"}},
+				waitErrors(inst.Run(context.TODO())))
+		},
+		"waiting": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				Go(ctx, func(context.Context) error {
+					return testError(-1)
+				})
+				Go(ctx, func(context.Context) error {
+					return testError(-2)
+				})
+				err := Wait(ctx)
+				as.ErrorIs(err, testError(-1))
+				as.ErrorIs(err, testError(-2))
+
+				Go(ctx, func(context.Context) error {
+					panic("panic message")
+				})
+				defer func() {
+					as.Equal("panic message", recover())
+				}()
+				_ = Wait(ctx)
+				return nil
+			})
+
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+		},
+		"outside executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			done := make(chan struct{})
+			Go(context.TODO(), func(context.Context) error {
+				close(done)
+				return testError(-1)
+			})
+			<-done
+			as.NoError(Wait(context.TODO()))
+			as.NoError(joined())
+			as.True(errors.Is(joined(nil, testError(1)), testError(1)))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
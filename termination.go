@@ -0,0 +1,200 @@
+package run
+
+import "fmt"
+
+// TerminationReason describes why an instance terminated,
+// as part of its terminal error (see Instance.Err).
+type TerminationReason int
+
+const (
+	// Completed indicates that the instance completed
+	// according to its options (e.g. after a successful execution
+	// of a runnable that does not recur, or the end of its schedule).
+	Completed TerminationReason = iota + 1
+	// RunLimitReached indicates that the run limit of the instance
+	// was reached.
+	RunLimitReached
+	// RestartLimitExceeded indicates that a failed execution
+	// was not restarted, since the restart limit of the instance was reached.
+	RestartLimitExceeded
+	// Failed indicates that a failed execution was not restarted,
+	// since restarts are disabled or the retry budget of the instance
+	// was depleted.
+	Failed
+	// ContextCancelled indicates that the context of the instance was done.
+	ContextCancelled
+	// Panicked indicates that the runnable of the instance panicked.
+	Panicked
+	// Stopped indicates that the instance was stopped.
+	Stopped
+	// Limited indicates that the limiter of the instance
+	// prevented an execution.
+	Limited
+	// AttemptLimitReached indicates that the attempt limit of the instance
+	// was reached.
+	AttemptLimitReached
+	// TotalTimeoutExceeded indicates that the total timeout
+	// of the instance elapsed.
+	TotalTimeoutExceeded
+	// StoreFailed indicates that the state of the instance
+	// could not be loaded from or saved to its store.
+	StoreFailed
+	// NameConflict indicates that another instance was registered
+	// under the name of the instance (see WithName).
+	NameConflict
+)
+
+// String returns the description of a termination reason.
+func (r TerminationReason) String() string {
+	switch r {
+	case Completed:
+		return "completed"
+	case RunLimitReached:
+		return "run limit reached"
+	case RestartLimitExceeded:
+		return "restart limit exceeded"
+	case Failed:
+		return "failed without restart"
+	case ContextCancelled:
+		return "context cancelled"
+	case Panicked:
+		return "panicked"
+	case Stopped:
+		return "stopped"
+	case Limited:
+		return "limited"
+	case AttemptLimitReached:
+		return "attempt limit reached"
+	case TotalTimeoutExceeded:
+		return "total timeout exceeded"
+	case StoreFailed:
+		return "store failed"
+	case NameConflict:
+		return "name conflict"
+	default:
+		return "not terminated"
+	}
+}
+
+// Error returns the description of a termination reason,
+// so that it can be part of the terminal error of an instance.
+func (r TerminationReason) Error() string {
+	return r.String()
+}
+
+// Err returns the terminal error of an instance once it terminates
+// (see Done), or nil until then.
+//
+// It joins the errors of the failed executions of the instance
+// (the first and last few of them, see DroppedErrors),
+// the error it terminated with (if any) and the reason it terminated,
+// which can be matched with errors.Is
+// (e.g. errors.Is(err, RestartLimitExceeded)).
+func (i *Instance) Err() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.err
+}
+
+// TerminationReason returns the reason an instance terminated,
+// or zero until it does.
+func (i *Instance) TerminationReason() TerminationReason {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.ended
+}
+
+// ReportTermination indicates whether to propagate the reason
+// an instance terminated to its error channel, as its final error
+// (default: false).
+func ReportTermination(report bool) Option {
+	return func(o *options) *options {
+		o.reportEnd = report
+		return o
+	}
+}
+
+// reportsTermination indicates whether the termination reason
+// of a runnable is propagated to its error channel.
+func (o *options) reportsTermination() bool {
+	return o != nil && o.reportEnd
+}
+
+// Done returns a channel closed once an instance terminates.
+func (i *Instance) Done() <-chan struct{} {
+	_, done := i.readiness()
+	return done
+}
+
+// termination returns the reason a copy of the runnable of an instance
+// does not run again after an execution,
+// provided with the return value of the execution.
+func (i *Instance) termination(err error, w *worker) TerminationReason {
+	switch {
+	case i.attemptsExhausted():
+		return AttemptLimitReached
+	case err != nil && !w.denied && i.options().restarts(err):
+		return RestartLimitExceeded
+	case err != nil:
+		return Failed
+	case i.exhausted():
+		return RunLimitReached
+	}
+	return Completed
+}
+
+// retainedErrors is the number of errors of failed executions retained
+// at each end of the terminal error of an instance.
+const retainedErrors = 8
+
+// DroppedErrors is part of the terminal error of an instance
+// in place of the errors of failed executions that were not retained,
+// since only the first and last few of them are.
+type DroppedErrors struct {
+	// Count is the number of errors dropped.
+	Count int
+}
+
+// Error satisfies error interface for DroppedErrors.
+func (e DroppedErrors) Error() string {
+	return fmt.Sprintf("%d more errors dropped", e.Count)
+}
+
+// errorLog retains the first and last errors of failed executions
+// for the terminal error of an instance, counting the rest.
+type errorLog struct {
+	first []error
+	// last is a ring buffer of the latest errors, with next
+	// being the position of the oldest one once it is full.
+	last    []error
+	next    int
+	dropped int
+}
+
+// add adds an error to the log, dropping the oldest of the latest errors
+// if they are already as many as retained.
+func (l *errorLog) add(err error) {
+	switch {
+	case len(l.first) < retainedErrors:
+		l.first = append(l.first, err)
+	case len(l.last) < retainedErrors:
+		l.last = append(l.last, err)
+	default:
+		l.last[l.next] = err
+		l.next = (l.next + 1) % retainedErrors
+		l.dropped++
+	}
+}
+
+// errors returns the retained errors in the order they were added,
+// with the dropped ones represented by DroppedErrors.
+func (l *errorLog) errors() []error {
+	errs := append([]error(nil), l.first...)
+	if l.dropped > 0 {
+		errs = append(errs, DroppedErrors{Count: l.dropped})
+	}
+	errs = append(errs, l.last[l.next:]...)
+	return append(errs, l.last[:l.next]...)
+}
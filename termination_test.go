@@ -0,0 +1,211 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testTermination(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"reasons": func(t *testing.T) {
+			as := newAssertions(t)
+
+			for reason, desc := range map[TerminationReason]string{
+				0:                    "not terminated",
+				Completed:            "completed",
+				RunLimitReached:      "run limit reached",
+				RestartLimitExceeded: "restart limit exceeded",
+				Failed:               "failed without restart",
+				ContextCancelled:     "context cancelled",
+				Panicked:             "panicked",
+				Stopped:              "stopped",
+				Limited:              "limited",
+				AttemptLimitReached:  "attempt limit reached",
+				TotalTimeoutExceeded: "total timeout exceeded",
+				StoreFailed:          "store failed",
+				NameConflict:         "name conflict",
+			} {
+				as.Equal(desc, reason.String())
+				as.EqualError(reason, desc)
+			}
+			as.EqualError(DroppedErrors{Count: 2}, "2 more errors dropped")
+		},
+		"terminal errors": func(t *testing.T) {
+			cancelled, cancel := context.WithCancel(context.TODO())
+			cancel()
+			limited := errors.New("limited")
+
+			type testcase struct {
+				ctx      context.Context
+				runnable Runnable
+				opts     []Option
+				expected error
+			}
+			succeeding := func(context.Context) error {
+				return nil
+			}
+			failing := func(context.Context) error {
+				return testError(1)
+			}
+			testcases := map[string]testcase{
+				"completed": {
+					runnable: succeeding,
+					expected: errors.Join(Completed),
+				},
+				"schedule ended": {
+					runnable: succeeding,
+					opts: []Option{Recur(true), WithSchedule(ScheduleFunc(
+						func(time.Time) time.Time {
+							return time.Time{}
+						}))},
+					expected: errors.Join(Completed),
+				},
+				"run limit reached": {
+					runnable: succeeding,
+					opts:     []Option{Recur(true), RunLimit(2), Concurrency(2)},
+					expected: errors.Join(RunLimitReached),
+				},
+				"attempt limit reached": {
+					runnable: func() Runnable {
+						runs := 0
+						return func(context.Context) error {
+							if runs++; runs%2 == 1 {
+								return testError(runs)
+							}
+							return nil
+						}
+					}(),
+					opts: []Option{Recur(true), Restart(true),
+						RestartLimit(0, nil), AttemptLimit(3)},
+					expected: errors.Join(testError(1), testError(3),
+						AttemptLimitReached),
+				},
+				"attempt limit of copies": {
+					runnable: succeeding,
+					opts: []Option{Recur(true), AttemptLimit(2),
+						RunLimit(5), Concurrency(2)},
+					expected: errors.Join(AttemptLimitReached),
+				},
+				"restart limit exceeded": {
+					runnable: failing,
+					opts:     []Option{Restart(true), RestartLimit(2, nil)},
+					expected: errors.Join(testError(1), testError(1),
+						RestartLimitExceeded),
+				},
+				"errors dropped": {
+					runnable: func() Runnable {
+						runs := 0
+						return func(context.Context) error {
+							runs++
+							return testError(runs)
+						}
+					}(),
+					opts: []Option{Restart(true), RestartLimit(20, nil)},
+					expected: errors.Join(testError(1), testError(2),
+						testError(3), testError(4), testError(5),
+						testError(6), testError(7), testError(8),
+						DroppedErrors{Count: 4}, testError(13),
+						testError(14), testError(15), testError(16),
+						testError(17), testError(18), testError(19),
+						testError(20), RestartLimitExceeded),
+				},
+				"failed": {
+					runnable: failing,
+					expected: errors.Join(testError(1), Failed),
+				},
+				"retry denied": {
+					runnable: failing,
+					opts: []Option{Restart(true), RestartLimit(0, nil),
+						WithRetryBudget(NewBudget(0, time.Hour))},
+					expected: errors.Join(testError(1), Failed),
+				},
+				"context cancelled": {
+					ctx:      cancelled,
+					runnable: succeeding,
+					expected: errors.Join(context.Canceled, ContextCancelled),
+				},
+				"panicked": {
+					runnable: func(context.Context) error {
+						panic("boom")
+					},
+					opts: []Option{Recover(true)},
+					expected: errors.Join(RunnablePanic{Value: "boom"},
+						Panicked),
+				},
+				"limited": {
+					runnable: succeeding,
+					opts: []Option{WithLimiter(limiterFunc(
+						func(context.Context) error {
+							return limited
+						}))},
+					expected: errors.Join(limited, Limited),
+				},
+			}
+
+			for name, tc := range testcases {
+				tc := tc
+				t.Run(name, func(t *testing.T) {
+					as := newAssertions(t)
+
+					ctx := tc.ctx
+					if ctx == nil {
+						ctx = context.TODO()
+					}
+					inst := New(tc.runnable,
+						append(tc.opts, WithChanBuffer(10))...)
+					waitErrors(inst.Run(ctx))
+					<-inst.Done()
+					as.Equal(tc.expected, inst.Err())
+				})
+			}
+		},
+		"ReportTermination": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{ReportTermination(true)})
+			as.Equal(&options{reportEnd: true}, opts)
+			as.True(opts.reportsTermination())
+			opts = nil
+			as.False(opts.reportsTermination())
+
+			failing := func(context.Context) error {
+				return testError(1)
+			}
+			inst := New(failing, Restart(true), RestartLimit(2, nil),
+				ReportTermination(true))
+			as.Zero(inst.TerminationReason())
+			as.Equal([]error{testError(1), testError(1), RestartLimitExceeded},
+				waitErrors(inst.Run(context.TODO())))
+			as.Equal(RestartLimitExceeded, inst.TerminationReason())
+
+			var handled []error
+			inst = New(failing, ReportTermination(true), OnError(func(err error) {
+				handled = append(handled, err)
+			}))
+			as.Empty(waitErrors(inst.Run(context.TODO())))
+			as.Equal([]error{testError(1), Failed}, handled)
+		},
+		"stopped": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, Recur(true), Period(time.Hour))
+			as.NoError(inst.Err())
+
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+			inst.Stop()
+			as.Empty(waitErrors(errCh))
+			as.ErrorIs(inst.Err(), Stopped)
+			as.Equal(Stopped, inst.TerminationReason())
+			as.Equal(errors.Join(Stopped), inst.Err())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
@@ -0,0 +1,112 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRunTimeout is wrapped around the error of an execution
+// that failed after exceeding its timeout (see Timeout),
+// distinguishing it from the cancellation of the context of its instance.
+var ErrRunTimeout = errors.New("run timeout")
+
+// ErrTotalTimeout is the error an instance terminates with
+// once its total timeout elapses (see TotalTimeout).
+var ErrTotalTimeout = errors.New("total timeout")
+
+// TotalTimeout sets a single deadline for an instance, covering all of
+// its executions, as well as the waits and backoff between them
+// (default: 0, no deadline).
+//
+// Once it elapses, the context of the instance (and of any execution
+// in progress) is cancelled with ErrTotalTimeout as its cause
+// (see context.Cause), and the instance terminates with it,
+// distinguishing it from the cancellation of the context
+// the instance is run with (unless an execution in progress fails
+// as a result, and is not restarted).
+func TotalTimeout(d time.Duration) Option {
+	return func(o *options) *options {
+		o.totalTimeout = d
+		return o
+	}
+}
+
+// withTotalTimeout creates a child of the provided context of an instance,
+// applying its total timeout if applicable,
+// and returns it along with its cancellation function.
+func (i *Instance) withTotalTimeout(ctx context.Context) (
+	context.Context, context.CancelFunc) {
+
+	if i.options() == nil || i.options().totalTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return withClockTimeout(ctx, i.options().clock(), i.options().totalTimeout,
+		ErrTotalTimeout)
+}
+
+// RestartOnTimeout indicates whether executions that failed
+// after exceeding their timeout are restarted,
+// according to the restart options of a runnable (default: true).
+//
+// If unset, the instance terminates after a timed out execution.
+func RestartOnTimeout(restart bool) Option {
+	return func(o *options) *options {
+		o.restartable.fatalTimeout = !restart
+		return o
+	}
+}
+
+// TimeoutRestartLimit accounts for executions that failed after exceeding
+// their timeout separately from other failed executions, restarting them
+// up to the provided limit (0 for no limit) with their own backoff,
+// e.g. to back off harder on timeouts than on transient errors.
+//
+// Consecutive timed out executions then count towards neither
+// the restart limit nor the backoff of other failed executions
+// (and vice versa), and are reported as Stats.ConsecutiveTimeouts,
+// while Attempt.ConsecutiveFailures includes both.
+// Restart is still required, and RestartOnTimeout(false) takes precedence.
+//
+// If nil is provided as the backoff function, no backoff is applied.
+func TimeoutRestartLimit(limit uint64, backoffFn BackoffFn) Option {
+	boff := ConstantBackoff(0)
+	if backoffFn != nil {
+		boff = backoffFn
+	}
+
+	return func(o *options) *options {
+		o.restartable.timeoutLimit = limit
+		o.restartable.timeoutBackoff = boff
+		return o
+	}
+}
+
+// separates indicates whether the provided error of a failed execution
+// is a timeout accounted separately from other failures.
+func (r restartOptions) separates(err error) bool {
+	return r.timeoutBackoff != nil && errors.Is(err, ErrRunTimeout)
+}
+
+// timedOut wraps the error of an execution in ErrRunTimeout,
+// if it failed after exceeding its timeout, provided with its context.
+//
+// The cause of the cancellation of the context is checked,
+// so that the timeout is not confused with any other deadline
+// (e.g. that of the instance).
+func timedOut(err error, ctxt context.Context) error {
+	if err == nil || context.Cause(ctxt) != ErrRunTimeout {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrRunTimeout, err)
+}
+
+// restarts indicates whether a failed execution of a runnable
+// can be restarted (within its restart limit),
+// provided with its error.
+func (o *options) restarts(err error) bool {
+	return o != nil && o.restartable.restartOnError &&
+		!(o.restartable.fatalTimeout && errors.Is(err, ErrRunTimeout)) &&
+		o.classify(err) != Fatal
+}
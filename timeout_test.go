@@ -0,0 +1,217 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testTimeout(t *testing.T) {
+	blocking := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	subtests := map[string]func(*testing.T){
+		"RestartOnTimeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{RestartOnTimeout(false)})
+			as.Equal(&options{
+				restartable: restartOptions{fatalTimeout: true},
+			}, opts)
+			as.False(opts.restarts(testError(1)))
+
+			opts = apply(t, opts, []Option{Restart(true)})
+			as.True(opts.restarts(testError(1)))
+			as.False(opts.restarts(timedOut(context.DeadlineExceeded,
+				expired(ErrRunTimeout))))
+
+			opts = nil
+			as.False(opts.restarts(testError(1)))
+		},
+		"timedOut": func(t *testing.T) {
+			as := newAssertions(t)
+
+			as.NoError(timedOut(nil, expired(ErrRunTimeout)))
+			as.Equal(testError(1), timedOut(testError(1), context.TODO()))
+			as.Equal(context.DeadlineExceeded,
+				timedOut(context.DeadlineExceeded, expired(nil)))
+
+			err := timedOut(context.DeadlineExceeded, expired(ErrRunTimeout))
+			as.ErrorIs(err, ErrRunTimeout)
+			as.ErrorIs(err, context.DeadlineExceeded)
+			as.EqualError(err, "run timeout: context deadline exceeded")
+		},
+		"parent deadline is not a run timeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			ctx, cancel := context.WithTimeout(context.TODO(), testTimeDelta)
+			defer cancel()
+			as.Equal(context.DeadlineExceeded,
+				Do(ctx, blocking, Timeout(time.Hour)))
+		},
+		"progressive timeouts": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var attempts []uint64
+			err := Do(context.TODO(), func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(2 * testTimeDelta):
+					return nil
+				}
+			}, Restart(true), RestartLimit(0, nil),
+				TimeoutFn(func(attempt uint64) time.Duration {
+					attempts = append(attempts, attempt)
+					if attempt < 3 {
+						return testTimeDelta
+					}
+					return 0
+				}))
+			as.NoError(err)
+			as.Equal([]uint64{1, 2, 3}, attempts)
+		},
+		"TotalTimeout": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{TotalTimeout(time.Minute)})
+			as.Equal(&options{totalTimeout: time.Minute}, opts)
+		},
+		"total timeout across attempts": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs := 0
+			inst := New(func(ctx context.Context) error {
+				runs++
+				return testError(runs)
+			}, Restart(true), RestartLimit(0, ConstantBackoff(testTimeDelta)),
+				TotalTimeout(5*testTimeDelta/2), WithChanBuffer(5))
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.Equal(3, runs)
+			as.Equal([]error{testError(1), testError(2), testError(3),
+				ErrTotalTimeout}, errs)
+			as.Equal(TotalTimeoutExceeded, inst.TerminationReason())
+		},
+		"total timeout during execution": func(t *testing.T) {
+			as := newAssertions(t)
+
+			causes := make(chan error, 1)
+			err := Do(context.TODO(), func(ctx context.Context) error {
+				<-ctx.Done()
+				causes <- context.Cause(ctx)
+				return ctx.Err()
+			}, TotalTimeout(testTimeDelta), Timeout(time.Hour),
+				Restart(true), RestartLimit(0, nil))
+			as.Equal(ErrTotalTimeout, err)
+			as.Equal(ErrTotalTimeout, <-causes)
+		},
+		"TimeoutRestartLimit": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{
+				TimeoutRestartLimit(3, func(n uint64) time.Duration {
+					return time.Duration(n) * time.Second
+				}),
+			})
+			as.Equal(uint64(3), opts.restartable.timeoutLimit)
+			as.Equal(2*time.Second, opts.restartable.timeoutBackoff(2))
+			as.True(opts.restartable.separates(
+				timedOut(context.DeadlineExceeded, expired(ErrRunTimeout))))
+			as.False(opts.restartable.separates(testError(1)))
+
+			opts = apply(t, new(options), []Option{TimeoutRestartLimit(0, nil)})
+			as.Zero(opts.restartable.timeoutBackoff(2))
+			as.False(new(options).restartable.separates(
+				timedOut(context.DeadlineExceeded, expired(ErrRunTimeout))))
+
+			_, err := NewChecked(nil, TimeoutRestartLimit(1, nil))
+			as.Equal(OptionError{Option: "TimeoutRestartLimit", Reason: "requires Restart"}, err)
+		},
+		"timed out executions are accounted separately": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var attempts []uint64
+			inst := New(func(ctx context.Context) error {
+				attempt, _ := AttemptFromContext(ctx)
+				attempts = append(attempts, attempt.ConsecutiveFailures)
+				if attempt.Number == 2 {
+					return testError(2)
+				}
+				<-ctx.Done()
+				return ctx.Err()
+			}, Timeout(testTimeDelta), Restart(true),
+				RestartLimit(2, func(n uint64) time.Duration {
+					return time.Duration(n) * time.Millisecond
+				}),
+				TimeoutRestartLimit(2, func(n uint64) time.Duration {
+					return time.Duration(n) * 10 * time.Millisecond
+				}))
+
+			var delays []time.Duration
+			for ev := range inst.Events(context.TODO()) {
+				if e, ok := ev.(BackoffStarted); ok {
+					delays = append(delays, e.Delay)
+				}
+			}
+			as.Equal([]time.Duration{10 * time.Millisecond, time.Millisecond}, delays)
+			as.Equal([]uint64{0, 1, 2}, attempts)
+
+			stats := inst.Stats()
+			as.Equal(uint64(3), stats.FailedRuns)
+			as.Equal(uint64(1), stats.ConsecutiveFailures)
+			as.Equal(uint64(2), stats.ConsecutiveTimeouts)
+			as.ErrorIs(stats.LastError, ErrRunTimeout)
+			as.Equal(RestartLimitExceeded, inst.TerminationReason())
+
+			// Timed out executions are not accounted separately unless set.
+			inst = New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}, Timeout(testTimeDelta), Restart(true), RestartLimit(2, nil))
+			waitErrors(inst.Run(context.TODO()))
+			as.Equal(uint64(2), inst.Stats().ConsecutiveFailures)
+			as.Zero(inst.Stats().ConsecutiveTimeouts)
+		},
+		"successful executions reset timeouts": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				if attempt, _ := AttemptFromContext(ctx); attempt.Number == 2 {
+					return nil
+				}
+				<-ctx.Done()
+				return ctx.Err()
+			}, Timeout(testTimeDelta), Restart(true), Recur(true), RunLimit(1),
+				TimeoutRestartLimit(0, nil))
+			waitErrors(inst.Run(context.TODO()))
+
+			stats := inst.Stats()
+			as.Equal(uint64(2), stats.Runs)
+			as.Zero(stats.ConsecutiveTimeouts)
+		},
+		"timed out executions are not restarted": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(blocking, Timeout(testTimeDelta), Restart(true),
+				RestartLimit(0, nil), RestartOnTimeout(false))
+			errs := waitErrors(inst.Run(context.TODO()))
+			as.Len(errs, 1)
+			as.ErrorIs(errs[0], ErrRunTimeout)
+			as.Equal(Failed, inst.TerminationReason())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
+
+// expired returns a context whose deadline has elapsed,
+// with the provided cause (if any).
+func expired(cause error) context.Context {
+	ctx, cancel := context.WithDeadlineCause(context.TODO(), time.Time{}, cause)
+	cancel()
+	return ctx
+}
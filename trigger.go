@@ -0,0 +1,207 @@
+package run
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// TriggerNow starts the next execution of an instance immediately,
+// skipping the remaining wait before it, whether for the period
+// or schedule of a recurring instance or the backoff of a restarting one.
+//
+// Triggers coalesce: triggering an instance while an execution is
+// in progress, or while already triggered, starts at most one additional
+// execution once it completes. Triggering an instance which does not
+// rerun has no effect (other than skipping any initial delay),
+// while paused instances still wait to be resumed.
+//
+// It is safe to call while the instance is running.
+func (i *Instance) TriggerNow() {
+	select {
+	case i.triggered() <- struct{}{}:
+	default:
+	}
+}
+
+// triggered returns the channel the triggers of an instance
+// are delivered on.
+func (i *Instance) triggered() chan struct{} {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.trigger == nil {
+		i.trigger = make(chan struct{}, 1)
+	}
+	return i.trigger
+}
+
+// triggerOptions defines trigger-driven execution options.
+type triggerOptions struct {
+	// source (if set) delivers the triggers of executions.
+	source <-chan struct{}
+	// coalesce denotes whether the triggers pending once an instance
+	// waits for its next execution collapse into a single one.
+	coalesce bool
+	// debounce is the quiet period following a trigger, during which
+	// further triggers postpone the execution it starts.
+	debounce time.Duration
+	// throttle is the minimum interval between the starts
+	// of triggered executions.
+	throttle time.Duration
+}
+
+// Triggers drives the executions of a runnable by the values received
+// from the provided channel, each of which starts an execution
+// (skipping any remaining wait before it, as TriggerNow does).
+//
+// A runnable that does not recur waits for a trigger before each execution
+// (including the first one, ignoring any initial delay) after
+// a successful one, while a recurring runnable is triggered in addition
+// to its period or schedule. Closing the channel terminates the instance
+// once it waits for its next execution.
+//
+// Triggers sent while an execution is in progress remain in the channel,
+// each starting another execution, unless they are coalesced
+// (see CoalesceTriggers).
+func Triggers(ch <-chan struct{}) Option {
+	return func(o *options) *options {
+		o.triggering.source = ch
+		return o
+	}
+}
+
+// CoalesceTriggers indicates whether the triggers pending in the channel
+// of a trigger-driven runnable (see Triggers), once it waits for
+// its next execution, collapse into a single execution (default: false).
+func CoalesceTriggers(coalesce bool) Option {
+	return func(o *options) *options {
+		o.triggering.coalesce = coalesce
+		return o
+	}
+}
+
+// Debounce delays the execution started by a trigger of a trigger-driven
+// runnable (see Triggers) until no further trigger is received for
+// the provided duration, collapsing a burst of triggers
+// into a single execution (default: 0, no debouncing).
+func Debounce(d time.Duration) Option {
+	return func(o *options) *options {
+		o.triggering.debounce = d
+		return o
+	}
+}
+
+// Throttle delays the executions started by the triggers of a trigger-driven
+// runnable (see Triggers) so that they start at least the provided duration
+// apart, collapsing the triggers received in the meantime
+// into a single execution (default: 0, no throttling).
+func Throttle(d time.Duration) Option {
+	return func(o *options) *options {
+		o.triggering.throttle = d
+		return o
+	}
+}
+
+// triggers returns the channel the triggers of executions are received from,
+// or nil if not trigger-driven.
+func (o *options) triggers() <-chan struct{} {
+	if o == nil {
+		return nil
+	}
+	return o.triggering.source
+}
+
+// awaitsTriggers indicates whether a runnable waits for a trigger
+// before each execution following a successful one.
+func (o *options) awaitsTriggers() bool {
+	return o.triggers() != nil && !o.recurring.recur
+}
+
+// coalesce discards the triggers pending in the provided channel,
+// if triggers coalesce.
+func (o *options) coalesce(ch <-chan struct{}) {
+	if !o.triggering.coalesce {
+		return
+	}
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// settle waits for a burst of triggers of a copy of the runnable
+// of an instance to settle, according to its debounce and throttle options,
+// collapsing them into a single execution. It indicates whether
+// the execution should proceed, returning the context error
+// in case of cancellation.
+func (i *Instance) settle(ctx context.Context, w *worker) (bool, error) {
+	clock, ch := i.options().clock(), i.options().triggers()
+
+	// Each trigger postpones the execution by the debounce period.
+	if d := i.options().triggering.debounce; d > 0 {
+		timer := clock.NewTimer(d)
+	quiet:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return false, context.Cause(ctx)
+			case <-w.halted:
+				timer.Stop()
+				return false, nil
+			case _, ok := <-ch:
+				if !ok {
+					// Closing the channel terminates the instance
+					// after the pending execution.
+					ch = nil
+					continue
+				}
+				timer.Stop()
+				timer = clock.NewTimer(d)
+			case <-timer.C():
+				break quiet
+			}
+		}
+	}
+
+	// Triggers received until the throttle interval elapses
+	// collapse into the pending execution.
+	i.mu.Lock()
+	last := i.lastTriggered
+	i.mu.Unlock()
+	if d := i.options().triggering.throttle; d > 0 && !last.IsZero() {
+		timer := clock.NewTimer(last.Add(d).Sub(clock.Now()))
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return false, context.Cause(ctx)
+			case <-w.halted:
+				timer.Stop()
+				return false, nil
+			case _, ok := <-ch:
+				if !ok {
+					ch = nil
+				}
+			case <-timer.C():
+				break wait
+			}
+		}
+	}
+
+	i.mu.Lock()
+	i.lastTriggered = clock.Now()
+	i.mu.Unlock()
+	return true, nil
+}
+
+// untimed is the delay before an execution that waits for a trigger instead.
+const untimed time.Duration = math.MinInt64
@@ -0,0 +1,294 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testTrigger(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"skips period": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return nil
+			}, Recur(true), Period(time.Hour), RunLimit(2))
+			errCh := inst.Run(context.TODO())
+			as.Eventually(func() bool {
+				return inst.State() == StateWaitingPeriod
+			}, time.Second, time.Millisecond)
+
+			inst.TriggerNow()
+			as.Empty(waitErrors(errCh))
+			as.Equal(uint64(2), inst.Stats().Runs)
+		},
+		"skips backoff": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return testError(1)
+			}, Restart(true), RestartLimit(2, ConstantBackoff(time.Hour)),
+				WithChanBuffer(3))
+			errCh := inst.Run(context.TODO())
+			as.Equal(testError(1), <-errCh)
+
+			inst.TriggerNow()
+			as.Equal(testError(1), <-errCh)
+			inst.Stop()
+			as.Empty(waitErrors(errCh))
+			as.Equal(uint64(2), inst.Stats().Runs)
+		},
+		"coalesces while running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			release := make(chan struct{})
+			inst := New(func(ctx context.Context) error {
+				Ready(ctx)
+				<-release
+				return nil
+			}, Recur(true), Period(time.Hour), AwaitReady(true))
+			errCh := inst.Run(context.TODO())
+			as.NoError(inst.WaitReady(context.TODO()))
+
+			for n := 0; n < 3; n++ {
+				inst.TriggerNow()
+			}
+			close(release)
+			as.Eventually(func() bool {
+				return inst.Stats().Runs == 2 &&
+					inst.State() == StateWaitingPeriod
+			}, time.Second, time.Millisecond)
+
+			inst.Stop()
+			as.Empty(waitErrors(errCh))
+			as.Equal(uint64(2), inst.Stats().Runs)
+		},
+		"driven by triggers": func(t *testing.T) {
+			as := newAssertions(t)
+
+			triggers := make(chan struct{}, 2)
+			var attempts []Attempt
+			inst := New(func(ctx context.Context) error {
+				a, _ := AttemptFromContext(ctx)
+				attempts = append(attempts, a)
+				return nil
+			}, Triggers(triggers), InitialDelay(time.Hour))
+			errCh := inst.Run(context.TODO())
+
+			// Waiting for the first trigger.
+			as.Eventually(func() bool {
+				return inst.State() == StateIdle
+			}, time.Second, time.Millisecond)
+			as.Zero(inst.Stats().NextRun)
+			as.Zero(inst.Stats().Runs)
+
+			triggers <- struct{}{}
+			as.Eventually(func() bool {
+				return inst.Stats().Runs == 1 &&
+					inst.State() == StateWaitingPeriod
+			}, time.Second, time.Millisecond)
+			as.Zero(inst.Stats().NextRun)
+
+			// Each pending trigger starts an execution.
+			triggers <- struct{}{}
+			triggers <- struct{}{}
+			close(triggers)
+			as.Empty(waitErrors(errCh))
+			as.Equal(uint64(3), inst.Stats().Runs)
+			as.Equal(Completed, inst.TerminationReason())
+			if as.Len(attempts, 3) {
+				as.False(attempts[0].ScheduledAt.IsZero())
+			}
+		},
+		"coalesced triggers": func(t *testing.T) {
+			as := newAssertions(t)
+
+			triggers := make(chan struct{}, 3)
+			release := make(chan struct{})
+			inst := New(func(ctx context.Context) error {
+				Ready(ctx)
+				<-release
+				return nil
+			}, Triggers(triggers), CoalesceTriggers(true), AwaitReady(true))
+			errCh := inst.Run(context.TODO())
+
+			triggers <- struct{}{}
+			as.NoError(inst.WaitReady(context.TODO()))
+			for n := 0; n < 3; n++ {
+				triggers <- struct{}{}
+			}
+			close(release)
+			as.Eventually(func() bool {
+				return inst.Stats().Runs == 2 &&
+					inst.State() == StateWaitingPeriod
+			}, time.Second, time.Millisecond)
+
+			// Closed while coalescing.
+			triggers <- struct{}{}
+			close(triggers)
+			as.Empty(waitErrors(errCh))
+			as.LessOrEqual(inst.Stats().Runs, uint64(3))
+		},
+		"recurring": func(t *testing.T) {
+			as := newAssertions(t)
+
+			triggers := make(chan struct{})
+			inst := New(func(context.Context) error {
+				return nil
+			}, Triggers(triggers), Recur(true), Period(time.Hour))
+			errCh := inst.Run(context.TODO())
+			as.Eventually(func() bool {
+				return inst.State() == StateWaitingPeriod
+			}, time.Second, time.Millisecond)
+
+			triggers <- struct{}{}
+			as.Eventually(func() bool {
+				return inst.Stats().Runs == 2 &&
+					inst.State() == StateWaitingPeriod
+			}, time.Second, time.Millisecond)
+			as.False(inst.Stats().NextRun.IsZero())
+
+			close(triggers)
+			as.Empty(waitErrors(errCh))
+			as.Equal(Completed, inst.TerminationReason())
+		},
+		"backoff after failure": func(t *testing.T) {
+			as := newAssertions(t)
+
+			triggers := make(chan struct{}, 1)
+			runs := 0
+			inst := New(func(context.Context) error {
+				if runs++; runs == 1 {
+					return testError(1)
+				}
+				return nil
+			}, Triggers(triggers), Restart(true),
+				RestartLimit(0, ConstantBackoff(testTimeDelta)), RunLimit(1))
+
+			triggers <- struct{}{}
+			as.Equal([]error{testError(1)}, waitErrors(inst.Run(context.TODO())))
+			as.Equal(2, runs)
+		},
+		"debounce": func(t *testing.T) {
+			as := newAssertions(t)
+
+			triggers := make(chan struct{}, 1)
+			var started time.Time
+			inst := New(func(context.Context) error {
+				started = time.Now()
+				return nil
+			}, Triggers(triggers), Debounce(3*testTimeDelta))
+			errCh := inst.Run(context.TODO())
+
+			// A burst of triggers, each postponing the execution.
+			begin := time.Now()
+			for n := 0; n < 3; n++ {
+				triggers <- struct{}{}
+				time.Sleep(testTimeDelta)
+			}
+			as.Eventually(func() bool {
+				return inst.Stats().Runs == 1 &&
+					inst.State() == StateWaitingPeriod
+			}, time.Second, time.Millisecond)
+			as.GreaterOrEqual(started.Sub(begin), 5*testTimeDelta)
+
+			// Closed while debouncing.
+			triggers <- struct{}{}
+			close(triggers)
+			as.Empty(waitErrors(errCh))
+			as.Equal(uint64(2), inst.Stats().Runs)
+		},
+		"throttle": func(t *testing.T) {
+			as := newAssertions(t)
+
+			triggers := make(chan struct{}, 2)
+			var starts []time.Time
+			inst := New(func(context.Context) error {
+				starts = append(starts, time.Now())
+				return nil
+			}, Triggers(triggers), Throttle(3*testTimeDelta))
+			errCh := inst.Run(context.TODO())
+
+			triggers <- struct{}{}
+			as.Eventually(func() bool {
+				return inst.Stats().Runs == 1 &&
+					inst.State() == StateWaitingPeriod
+			}, time.Second, time.Millisecond)
+
+			// Triggers collapse while throttled,
+			// including the closing of the channel.
+			triggers <- struct{}{}
+			triggers <- struct{}{}
+			close(triggers)
+			as.Empty(waitErrors(errCh))
+			if as.Len(starts, 2) {
+				as.GreaterOrEqual(starts[1].Sub(starts[0]), 3*testTimeDelta)
+			}
+		},
+		"settling cancelled": func(t *testing.T) {
+			for name, opts := range map[string][]Option{
+				"debounce": {Debounce(time.Hour)},
+				"throttle": {Throttle(time.Hour)},
+			} {
+				opts := opts
+				t.Run(name, func(t *testing.T) {
+					as := newAssertions(t)
+
+					triggers := make(chan struct{}, 1)
+					inst := New(func(context.Context) error {
+						return nil
+					}, append(opts, Triggers(triggers))...)
+					ctx, cancel := context.WithCancelCause(context.TODO())
+					errCh := inst.Run(ctx)
+
+					triggers <- struct{}{}
+					triggers <- struct{}{}
+					as.Eventually(func() bool {
+						return len(triggers) == 0
+					}, time.Second, time.Millisecond)
+					cancel(testError(1))
+					as.Equal([]error{testError(1)}, waitErrors(errCh))
+				})
+			}
+		},
+		"settling halted": func(t *testing.T) {
+			cases := map[string]struct {
+				opts     []Option
+				triggers int
+			}{
+				"debounce": {[]Option{Debounce(time.Hour), RunLimit(1)}, 1},
+				"throttle": {[]Option{Throttle(time.Hour), RunLimit(2)}, 2},
+			}
+			for name, tc := range cases {
+				tc := tc
+				t.Run(name, func(t *testing.T) {
+					as := newAssertions(t)
+
+					triggers := make(chan struct{}, 1)
+					inst := New(func(context.Context) error {
+						return nil
+					}, append(tc.opts, Triggers(triggers), Concurrency(2))...)
+					errCh := inst.Run(context.TODO())
+
+					// One copy settles a trigger, while the other
+					// reaches the run limit when triggered.
+					for n := 0; n < tc.triggers; n++ {
+						triggers <- struct{}{}
+					}
+					as.Eventually(func() bool {
+						return len(triggers) == 0
+					}, time.Second, time.Millisecond)
+					time.Sleep(testTimeDelta)
+					inst.TriggerNow()
+					as.Empty(waitErrors(errCh))
+					as.Equal(RunLimitReached, inst.TerminationReason())
+				})
+			}
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
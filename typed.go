@@ -0,0 +1,86 @@
+package run
+
+import "context"
+
+// TypedRunnable defines the contract for a runnable producing a result.
+//
+// It should respect context cancellation.
+type TypedRunnable[T any] func(context.Context) (T, error)
+
+// TypedInstance represents a runnable instance
+// whose successful executions produce results.
+type TypedInstance[T any] struct {
+	Instance
+
+	typed   TypedRunnable[T]
+	results chan T
+}
+
+// NewTyped creates a new runnable instance with the provided options,
+// whose runnable produces results of type T.
+//
+// In case of conflicting options, the last one will be applied.
+func NewTyped[T any](r TypedRunnable[T], opts ...Option) *TypedInstance[T] {
+	return newTyped(r, new(options).with(opts))
+}
+
+// newTyped creates a new runnable instance with the provided options,
+// whose runnable produces results of type T.
+func newTyped[T any](r TypedRunnable[T], o *options) *TypedInstance[T] {
+	inst := &TypedInstance[T]{
+		Instance: Instance{opts: o},
+		typed:    r,
+	}
+	inst.results = make(chan T, inst.options().chanSize())
+	inst.finalize = func() {
+		close(inst.results)
+	}
+
+	if r != nil {
+		inst.r = func(ctx context.Context) error {
+			res, err := r(ctx)
+			if err != nil {
+				return err
+			}
+			// Abandon delivery in case of cancellation.
+			select {
+			case inst.results <- res:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return inst
+}
+
+// Results returns the channel where the results of successful executions
+// of an instance are propagated, which is closed once the instance terminates.
+//
+// The channel buffer size is controlled by WithChanBuffer,
+// and similarly to the error channel, delivery of a result
+// blocks the execution of the instance until it is received
+// (or the execution's context is cancelled, failing it),
+// so both channels should be consumed concurrently.
+func (i *TypedInstance[T]) Results() <-chan T {
+	return i.results
+}
+
+// Reset restores an instance that has terminated to its initial state
+// (see Instance.Reset), with a new channel for its results.
+func (i *TypedInstance[T]) Reset() error {
+	if err := i.Instance.Reset(); err != nil {
+		return err
+	}
+	i.results = make(chan T, i.options().chanSize())
+	return nil
+}
+
+// Clone creates a new, unstarted instance with the runnable
+// and effective options of an instance, with the provided options
+// applied on top of them (see Instance.Clone),
+// and a channel of its own for its results.
+func (i *TypedInstance[T]) Clone(extra ...Option) *TypedInstance[T] {
+	return newTyped(i.typed, i.options().with(extra))
+}
@@ -0,0 +1,107 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testTyped(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"results of successful executions": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			inst := NewTyped(func(context.Context) (int, error) {
+				calls++
+				if calls == 2 {
+					return 0, testError(calls)
+				}
+				return calls, nil
+			},
+				Recur(true),
+				RunLimit(3),
+				Restart(true),
+				RestartLimit(0, nil),
+			)
+
+			errs := make(chan []error)
+			go func(errCh <-chan error) {
+				errs <- waitErrors(errCh)
+			}(inst.Run(context.TODO()))
+
+			results := make([]int, 0)
+			for res := range inst.Results() {
+				results = append(results, res)
+			}
+
+			as.Equal([]int{1, 3, 4}, results)
+			as.Equal([]error{testError(2)}, <-errs)
+		},
+		"clone delivers its own results": func(t *testing.T) {
+			as := newAssertions(t)
+
+			calls := 0
+			blueprint := NewTyped(func(context.Context) (int, error) {
+				calls++
+				return calls, nil
+			}, Recur(true), RunLimit(1), WithChanBuffer(2))
+			clone := blueprint.Clone(RunLimit(2))
+
+			for _, tc := range []struct {
+				inst     *TypedInstance[int]
+				expected []int
+			}{
+				{inst: clone, expected: []int{1, 2}},
+				{inst: blueprint, expected: []int{3}},
+			} {
+				inst := tc.inst
+				as.Empty(waitErrors(inst.Run(context.TODO())))
+				results := make([]int, 0)
+				for res := range inst.Results() {
+					results = append(results, res)
+				}
+				as.Equal(tc.expected, results)
+			}
+		},
+		"nil runnable panics": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := NewTyped[int](nil, Recover(true))
+
+			errCh := inst.Run(context.TODO())
+
+			as.Equal([]error{RunnablePanic{NilRunnable}}, waitErrors(errCh))
+			_, open := <-inst.Results()
+			as.False(open)
+		},
+		"delivery abandoned on cancellation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := NewTyped(func(context.Context) (string, error) {
+				return "result", nil
+			}, Timeout(testTimeDelta))
+
+			errCh := inst.Run(context.TODO())
+
+			errs := waitErrors(errCh)
+			as.Len(errs, 1)
+			as.ErrorIs(errs[0], ErrRunTimeout)
+			as.ErrorIs(errs[0], context.DeadlineExceeded)
+			_, open := <-inst.Results()
+			as.False(open)
+		},
+		"options are applied": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := NewTyped[int](nil, WithChanBuffer(2), Timeout(time.Second))
+
+			as.Equal(2, cap(inst.Results()))
+			as.Equal(time.Second, inst.opts.constrained.timeout)
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
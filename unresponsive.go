@@ -0,0 +1,88 @@
+package run
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// UnresponsiveGrace sets the amount of time an execution may keep running
+// once its context is cancelled (due to its timeout, a missed heartbeat,
+// or its instance being stopped), before it is considered unresponsive
+// (default: 0, never).
+//
+// Once an execution overruns it, RunnableUnresponsive is emitted,
+// while the execution is still waited for as usual (see StopGrace
+// for abandoning it instead), so that runnables ignoring
+// the cancellation of their context are detected.
+func UnresponsiveGrace(d time.Duration) Option {
+	return func(o *options) *options {
+		o.unresponsiveAfter = d
+		return o
+	}
+}
+
+// RunnableUnresponsive is emitted when an execution of the runnable
+// keeps running beyond the unresponsive grace of its instance
+// after its context is cancelled (see UnresponsiveGrace).
+type RunnableUnresponsive struct {
+	// Attempt is the (1-based) number of the execution.
+	Attempt uint64
+	// Cause is the cause of the cancellation of the context of the execution.
+	Cause error
+	// Overrun is the duration of the execution since its context was cancelled.
+	Overrun time.Duration
+}
+
+func (RunnableUnresponsive) event() {}
+
+// watchUnresponsive emits RunnableUnresponsive once an execution
+// with the provided context overruns the unresponsive grace (if any)
+// after the context is cancelled, unless the returned function
+// is called before that.
+func (o *options) watchUnresponsive(ctx context.Context, attempt Attempt,
+	emit func(Event)) func() {
+
+	if o == nil || o.unresponsiveAfter <= 0 {
+		return func() {}
+	}
+
+	var (
+		mu       sync.Mutex
+		finished bool
+		timer    Timer
+	)
+	stop := context.AfterFunc(ctx, func() {
+		cancelled, cause := o.clock().Now(), context.Cause(ctx)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// The execution may have returned while its context was being cancelled.
+		if !finished {
+			timer = o.clock().AfterFunc(o.unresponsiveAfter, func() {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if !finished {
+					emit(RunnableUnresponsive{
+						Attempt: attempt.Number,
+						Cause:   cause,
+						Overrun: o.clock().Now().Sub(cancelled),
+					})
+				}
+			})
+		}
+	})
+	return func() {
+		stop()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		finished = true
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
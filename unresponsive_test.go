@@ -0,0 +1,85 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testUnresponsive(t *testing.T) {
+	// ignoring returns a runnable ignoring the cancellation of its context,
+	// signalling once it starts.
+	ignoring := func(started chan<- struct{}) Runnable {
+		return func(context.Context) error {
+			close(started)
+			time.Sleep(3 * testTimeDelta)
+			return nil
+		}
+	}
+
+	subtests := map[string]func(*testing.T){
+		"UnresponsiveGrace": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{UnresponsiveGrace(time.Second)})
+			as.Equal(&options{unresponsiveAfter: time.Second}, opts)
+
+			_, err := NewChecked(nil, UnresponsiveGrace(-time.Second))
+			as.Equal(OptionError{Option: "UnresponsiveGrace", Reason: "must not be negative"}, err)
+		},
+		"timeout overrun": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(ignoring(make(chan struct{})),
+				Timeout(testTimeDelta), UnresponsiveGrace(testTimeDelta))
+
+			var unresponsive []RunnableUnresponsive
+			for ev := range inst.Events(context.TODO()) {
+				if e, ok := ev.(RunnableUnresponsive); ok {
+					unresponsive = append(unresponsive, e)
+				}
+			}
+			if as.Len(unresponsive, 1) {
+				as.Equal(uint64(1), unresponsive[0].Attempt)
+				as.Equal(ErrRunTimeout, unresponsive[0].Cause)
+				as.GreaterOrEqual(unresponsive[0].Overrun, testTimeDelta)
+				as.Less(unresponsive[0].Overrun, 2*testTimeDelta)
+			}
+		},
+		"stop overrun": func(t *testing.T) {
+			as := newAssertions(t)
+
+			started := make(chan struct{})
+			inst := New(ignoring(started), UnresponsiveGrace(testTimeDelta))
+
+			evCh := inst.Events(context.TODO())
+			go func() {
+				<-started
+				inst.Stop()
+			}()
+			as.Equal([]Event{
+				RunStarted{},
+				RunnableUnresponsive{Attempt: 1, Cause: ErrStopped},
+				RunSucceeded{},
+				Terminated{},
+			}, waitEvents(evCh))
+		},
+		"responsive": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}, Timeout(testTimeDelta), UnresponsiveGrace(testTimeDelta))
+
+			for ev := range inst.Events(context.TODO()) {
+				_, unresponsive := ev.(RunnableUnresponsive)
+				as.False(unresponsive)
+			}
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
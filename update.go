@@ -0,0 +1,61 @@
+package run
+
+import "slices"
+
+// Update applies the provided options to an instance,
+// which may be running, after validating their combination
+// with its existing ones (see NewChecked).
+//
+// Options of a running instance take effect from its next scheduling
+// decision: an ongoing execution keeps its timeout, while its period,
+// restart limits and backoff apply to the executions after it.
+// Options consulted only when an instance starts, such as Concurrency,
+// WithChanBuffer, WithName or WithStore, take effect the next time it runs.
+// Updated options are preserved if the instance is reset.
+//
+// It returns the OptionError of each nonsensical combination
+// (joined, see errors.Join), in which case the instance is not updated.
+func (i *Instance) Update(opts ...Option) error {
+	for {
+		prev := i.updated.Load()
+		current := prev
+		if current == nil {
+			current = i.opts
+		}
+		o := current.with(opts)
+		if err := o.check(); err != nil {
+			return err
+		}
+
+		// Retry if updated concurrently, so that no update is lost.
+		if i.updated.CompareAndSwap(prev, o) {
+			return nil
+		}
+	}
+}
+
+// options returns the current options of an instance,
+// which are updated atomically (see Update).
+func (i *Instance) options() *options {
+	if o := i.updated.Load(); o != nil {
+		return o
+	}
+	return i.opts
+}
+
+// with returns a copy of a set of options (empty, if nil)
+// with the provided options applied, leaving the original unaffected.
+func (o *options) with(opts []Option) *options {
+	copied := new(options)
+	if o != nil {
+		*copied = *o
+		// Clip slices, so that appending to them does not affect the original.
+		copied.metrics = slices.Clip(copied.metrics)
+		copied.middleware = slices.Clip(copied.middleware)
+		copied.health = slices.Clip(copied.health)
+	}
+	for _, opt := range opts {
+		copied = opt(copied)
+	}
+	return copied
+}
@@ -0,0 +1,123 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testUpdate(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"idle instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(nil, Recur(true), Period(time.Hour))
+			as.NoError(inst.Update(Period(time.Minute), RunLimit(3)))
+
+			as.Equal(&options{
+				recurring: recurrenceOptions{recur: true, period: time.Hour},
+			}, inst.opts)
+			as.Equal(&options{
+				recurring:   recurrenceOptions{recur: true, period: time.Minute},
+				constrained: constraintOptions{runLimit: 3},
+			}, inst.options())
+		},
+		"zero instance": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var inst Instance
+			as.Nil(inst.options())
+			as.NoError(inst.Update(WithName("updated")))
+			as.Equal("updated", inst.Name())
+		},
+		"invalid update": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(nil, Recur(true))
+			err := inst.Update(Period(time.Minute), Recur(false))
+
+			var oerr OptionError
+			if as.ErrorAs(err, &oerr) {
+				as.Equal(OptionError{Option: "Period", Reason: "requires Recur"}, oerr)
+			}
+			as.Equal(inst.opts, inst.options())
+		},
+		"period updated while running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var inst Instance
+			runs := 0
+			inst = New(func(context.Context) error {
+				if runs++; runs == 1 {
+					as.NoError(inst.Update(Period(0)))
+				}
+				return nil
+			}, Recur(true), Period(time.Hour), RunLimit(3))
+
+			ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+			defer cancel()
+			as.Empty(waitErrors(inst.Run(ctx)))
+			as.Equal(3, runs)
+			as.Equal(RunLimitReached, inst.TerminationReason())
+		},
+		"restart limit updated while running": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var inst Instance
+			runs := 0
+			inst = New(func(context.Context) error {
+				if runs++; runs == 1 {
+					as.NoError(inst.Update(RestartLimit(2, nil)))
+				}
+				return testError(runs)
+			}, Restart(true), RestartLimit(10, ConstantBackoff(time.Hour)))
+
+			ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+			defer cancel()
+			as.Equal([]error{testError(1), testError(2)}, waitErrors(inst.Run(ctx)))
+			as.Equal(RestartLimitExceeded, inst.TerminationReason())
+		},
+		"concurrent updates": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(nil, Recur(true))
+			increment := func(o *options) *options {
+				o.constrained.runLimit++
+				return o
+			}
+
+			const n = 50
+			var wg sync.WaitGroup
+			for range [n]struct{}{} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					as.NoError(inst.Update(increment))
+				}()
+			}
+			wg.Wait()
+
+			as.Equal(uint64(n), inst.options().constrained.runLimit)
+		},
+		"preserved on reset": func(t *testing.T) {
+			as := newAssertions(t)
+
+			inst := New(func(context.Context) error {
+				return errors.New("failed")
+			})
+			as.NoError(inst.Update(Restart(true), RestartLimit(1, nil)))
+			waitErrors(inst.Run(context.TODO()))
+			as.NoError(inst.Reset())
+
+			as.Equal([]error{errors.New("failed")},
+				waitErrors(inst.Run(context.TODO())))
+			as.Equal(RestartLimitExceeded, inst.TerminationReason())
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
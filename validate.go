@@ -0,0 +1,25 @@
+package run
+
+import "context"
+
+// ValidateSuccess sets a function validating each successful execution
+// of a runnable, called with the context of the execution once it
+// returns nil (default: nil, no validation).
+//
+// An execution failing validation is considered failed with its error,
+// subject to the restart options of the runnable.
+func ValidateSuccess(validate func(ctx context.Context) error) Option {
+	return func(o *options) *options {
+		o.validator = validate
+		return o
+	}
+}
+
+// validate returns the error of an execution, provided with its context
+// and return value, after validating it if successful.
+func (o *options) validate(ctx context.Context, err error) error {
+	if err != nil || o == nil || o.validator == nil {
+		return err
+	}
+	return o.validator(ctx)
+}
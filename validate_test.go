@@ -0,0 +1,49 @@
+package run
+
+import (
+	"context"
+	"testing"
+)
+
+func testValidate(t *testing.T) {
+	subtests := map[string]func(*testing.T){
+		"failed validation is restarted": func(t *testing.T) {
+			as := newAssertions(t)
+
+			runs, checks := 0, 0
+			inst := New(func(context.Context) error {
+				runs++
+				if runs == 1 {
+					return testError(runs)
+				}
+				return nil
+			}, Restart(true), RestartLimit(0, nil),
+				ValidateSuccess(func(ctx context.Context) error {
+					as.NoError(ctx.Err())
+					_, ok := AttemptFromContext(ctx)
+					as.True(ok)
+					if checks++; checks == 1 {
+						return testError(-1)
+					}
+					return nil
+				}))
+
+			as.Equal([]error{testError(1), testError(-1)},
+				waitErrors(inst.Run(context.TODO())))
+			as.Equal(3, runs)
+			as.Equal(2, checks)
+			as.Equal(uint64(2), inst.Stats().FailedRuns)
+		},
+		"without validation": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var opts *options
+			as.NoError(opts.validate(context.TODO(), nil))
+			as.Equal(testError(1), opts.validate(context.TODO(), testError(1)))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}
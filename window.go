@@ -0,0 +1,85 @@
+package run
+
+import "time"
+
+// windowOptions restricts the executions of a runnable
+// to a daily time window.
+type windowOptions struct {
+	// restricted indicates whether a window is set.
+	restricted bool
+	// from and to are the offsets from midnight
+	// at which the window opens and closes respectively.
+	from, to time.Duration
+	// loc is the location the window is evaluated in.
+	loc *time.Location
+}
+
+// RunWindow restricts the executions of a runnable
+// (including restarts) to a daily time window,
+// opening and closing at the provided offsets from midnight
+// in the provided location (the local one, if nil),
+// e.g. RunWindow(22*time.Hour, 6*time.Hour, nil) for executions overnight.
+//
+// Executions due outside the window are deferred until it opens.
+// Equal offsets leave executions unrestricted.
+func RunWindow(from, to time.Duration, loc *time.Location) Option {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return func(o *options) *options {
+		o.window = windowOptions{
+			restricted: from != to,
+			from:       from,
+			to:         to,
+			loc:        loc,
+		}
+		return o
+	}
+}
+
+// open returns the earliest time at or after the provided one
+// that falls within the window.
+func (w windowOptions) open(t time.Time) time.Time {
+	if !w.restricted {
+		return t
+	}
+
+	lt := t.In(w.loc)
+	midnight := time.Date(lt.Year(), lt.Month(), lt.Day(), 0, 0, 0, 0, w.loc)
+	offset := lt.Sub(midnight)
+
+	var within bool
+	switch {
+	case w.from < w.to:
+		within = offset >= w.from && offset < w.to
+	default:
+		// Window spans midnight.
+		within = offset >= w.from || offset < w.to
+	}
+
+	switch {
+	case within:
+		return t
+	case offset < w.from:
+		return midnight.Add(w.from)
+	default:
+		tomorrow := time.Date(lt.Year(), lt.Month(), lt.Day()+1, 0, 0, 0, 0, w.loc)
+		return tomorrow.Add(w.from)
+	}
+}
+
+// delay extends the provided delay from the provided time,
+// so that the execution due after it falls within the window.
+func (w windowOptions) delay(now time.Time, after time.Duration) time.Duration {
+	return w.open(now.Add(after)).Sub(now)
+}
+
+// windowed adjusts the provided delay from the provided time
+// according to the run window of the options (if any).
+func (o *options) windowed(now time.Time, after time.Duration) time.Duration {
+	if o == nil {
+		return after
+	}
+	return o.window.delay(now, after)
+}
@@ -0,0 +1,77 @@
+package run
+
+import (
+	"testing"
+	"time"
+)
+
+func testWindow(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2022, 1, day, hour, min, 0, 0, time.UTC)
+	}
+
+	subtests := map[string]func(*testing.T){
+		"RunWindow": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{
+				RunWindow(9*time.Hour, 17*time.Hour, time.UTC),
+			})
+			as.Equal(windowOptions{
+				restricted: true,
+				from:       9 * time.Hour,
+				to:         17 * time.Hour,
+				loc:        time.UTC,
+			}, opts.window)
+
+			opts = apply(t, opts, []Option{RunWindow(0, 0, nil)})
+			as.False(opts.window.restricted)
+			as.Equal(time.Local, opts.window.loc)
+		},
+		"daytime window": func(t *testing.T) {
+			as := newAssertions(t)
+
+			w := RunWindow(9*time.Hour, 17*time.Hour, time.UTC)(new(options)).window
+
+			as.Equal(at(1, 9, 0), w.open(at(1, 3, 0)))
+			as.Equal(at(1, 9, 0), w.open(at(1, 9, 0)))
+			as.Equal(at(1, 12, 30), w.open(at(1, 12, 30)))
+			as.Equal(at(2, 9, 0), w.open(at(1, 17, 0)))
+			as.Equal(at(2, 9, 0), w.open(at(1, 23, 0)))
+		},
+		"overnight window": func(t *testing.T) {
+			as := newAssertions(t)
+
+			w := RunWindow(22*time.Hour, 6*time.Hour, time.UTC)(new(options)).window
+
+			as.Equal(at(1, 3, 0), w.open(at(1, 3, 0)))
+			as.Equal(at(1, 22, 0), w.open(at(1, 6, 0)))
+			as.Equal(at(1, 22, 0), w.open(at(1, 12, 0)))
+			as.Equal(at(1, 23, 0), w.open(at(1, 23, 0)))
+		},
+		"unrestricted": func(t *testing.T) {
+			as := newAssertions(t)
+
+			var w windowOptions
+			var opts *options
+
+			as.Equal(at(1, 3, 0), w.open(at(1, 3, 0)))
+			as.Equal(time.Minute, opts.windowed(at(1, 3, 0), time.Minute))
+		},
+		"delays are extended to the window": func(t *testing.T) {
+			as := newAssertions(t)
+
+			opts := apply(t, new(options), []Option{
+				RunWindow(9*time.Hour, 17*time.Hour, time.UTC),
+			})
+
+			as.Equal(time.Minute, opts.windowed(at(1, 12, 0), time.Minute))
+			as.Equal(16*time.Hour, opts.windowed(at(1, 17, 0), 0))
+			as.Equal(9*time.Hour, opts.windowed(at(1, 0, 0), time.Hour))
+		},
+	}
+
+	for name, test := range subtests {
+		t.Run(name, test)
+	}
+}